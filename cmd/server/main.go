@@ -1,21 +1,30 @@
 package main
 
 import (
-	"fmt"
+	"context"
+	"errors"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/antonchaban/articles-go/internal/api"
+	"github.com/antonchaban/articles-go/internal/api/middleware"
 	v1 "github.com/antonchaban/articles-go/internal/api/v1"
 	"github.com/antonchaban/articles-go/internal/config"
+	"github.com/antonchaban/articles-go/internal/health"
 	logger "github.com/antonchaban/articles-go/internal/log"
-	"github.com/antonchaban/articles-go/internal/repository"
+	"github.com/antonchaban/articles-go/internal/scheduler"
 	"github.com/antonchaban/articles-go/internal/services"
-	"github.com/antonchaban/articles-go/pkg/database"
+	"github.com/antonchaban/articles-go/internal/tracing"
 
+	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
 
-
 func main() {
 	// Load Config
 	cfg, err := config.Load()
@@ -34,22 +43,144 @@ func main() {
 		zap.String("env", cfg.AppEnv),
 		zap.String("port", cfg.HTTPPort))
 
-	// DB Connection
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%d sslmode=disable",
-		cfg.DBHost, cfg.DBUser, cfg.DBPassword, cfg.DBName, cfg.DBPort)
+	// stop on SIGTERM (Kubernetes) or SIGINT (Ctrl+C), which also
+	// interrupts waiting for the database on startup
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	flushTraces, err := tracing.Setup(ctx, tracing.Config{
+		Exporter:     cfg.TracingExporter,
+		ServiceName:  cfg.TracingServiceName,
+		OTLPEndpoint: cfg.TracingOTLPEndpoint,
+		File:         cfg.TracingFile,
+		SampleRatio:  cfg.TracingSampleRatio,
+	})
+	if err != nil {
+		l.Fatal("failed to init tracing", zap.String("exporter", cfg.TracingExporter), zap.Error(err))
+	}
 
-	db, err := database.NewPostgresConnection(dsn)
+	st, err := openStorage(ctx, cfg, l)
 	if err != nil {
-		l.Fatal("failed to connect to db", zap.Error(err))
+		l.Fatal("failed to open storage", zap.String("driver", cfg.StorageDriver), zap.Error(err))
+	}
+
+	// "server migrate ..." manages the schema and exits
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if st.db == nil {
+			l.Fatal("migrations need a database", zap.String("driver", cfg.StorageDriver))
+		}
+		if err := runMigrate(context.Background(), st.db, l, os.Args[2:]); err != nil {
+			l.Fatal("migration command failed", zap.Error(err))
+		}
+		return
+	}
+
+	if cfg.DBAutoMigrate && st.db != nil {
+		if err := runMigrate(context.Background(), st.db, l, []string{"up"}); err != nil {
+			l.Fatal("failed to migrate db", zap.Error(err))
+		}
+	}
+	if st.db == nil {
+		l.Warn("storage is kept in memory and lost on exit", zap.String("driver", cfg.StorageDriver))
 	}
 
-	// init repo, service, handler, and server
-	repo := repository.NewPostgresRepo(db, l)
-	svc := services.NewArticleService(repo, l)
+	// init service, handler, and server
+	svc := services.NewArticleService(st.articles, st.authors, l)
 	handler := v1.NewArticleHandler(svc, l)
-	r := api.NewServer(cfg, handler)
+	authorHandler := v1.NewAuthorHandler(services.NewAuthorService(st.authors, l), l)
+	state := &api.State{}
+
+	checks := health.NewRegistry(cfg.HealthCheckTimeout, cfg.HealthCacheTTL, l)
+	if st.db != nil {
+		checks.Register("database", health.DBCheck(st.db))
+	}
 
-	if err := r.Run(":" + cfg.HTTPPort); err != nil {
-		l.Fatal("server failed to start", zap.Error(err))
+	var auth *middleware.JWTAuth
+	if cfg.AuthEnabled {
+		auth, err = middleware.NewJWTAuth(middleware.JWTConfig{
+			Secret:        cfg.JWTSecret,
+			PublicKeyFile: cfg.JWTPublicKeyFile,
+			JWKSFile:      cfg.JWTJWKSFile,
+			Issuer:        cfg.JWTIssuer,
+			Audience:      cfg.JWTAudience,
+			WriteRole:     cfg.JWTWriteRole,
+		})
+		if err != nil {
+			l.Fatal("failed to init jwt auth", zap.Error(err))
+		}
+	} else {
+		l.Warn("authentication disabled, write endpoints are open to everyone")
+	}
+
+	var idempotency gin.HandlerFunc
+	if st.idempotency != nil {
+		idempotency = middleware.Idempotency(st.idempotency, cfg.IdempotencyTTL, l)
+	} else {
+		l.Warn("Idempotency-Key headers are ignored without a database", zap.String("driver", cfg.StorageDriver))
+	}
+
+	r := api.NewServer(cfg, state, checks, auth, idempotency, handler, authorHandler)
+
+	srv := &http.Server{
+		Addr:              ":" + cfg.HTTPPort,
+		Handler:           r,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	// background jobs stop with the signal context and are waited for before the DB closes
+	var jobs sync.WaitGroup
+	if cfg.SchedulerEnabled {
+		publisher := scheduler.NewPublisher(st.articles, cfg.SchedulerInterval, cfg.SchedulerBatchSize, l)
+		jobs.Go(func() { publisher.Run(ctx) })
+	}
+	if st.idempotency != nil {
+		sweeper := scheduler.NewSweeper("idempotency_keys", st.idempotency.DeleteExpired, cfg.IdempotencySweepInterval, l)
+		jobs.Go(func() { sweeper.Run(ctx) })
+	}
+
+	schedulerDone := make(chan struct{})
+	go func() {
+		defer close(schedulerDone)
+		jobs.Wait()
+	}()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serveErr <- err
+		}
+	}()
+
+	l.Info("server listening", zap.String("addr", srv.Addr))
+
+	// a server that failed to serve has no traffic to drain
+	delay := cfg.ShutdownDelay
+	select {
+	case <-ctx.Done():
+		l.Info("shutdown signal received")
+	case err := <-serveErr:
+		l.Error("server failed", zap.Error(err))
+		delay = 0
+	}
+	stop()
+
+	t := &teardown{
+		state:       state,
+		server:      srv,
+		jobsDone:    schedulerDone,
+		flushTraces: flushTraces,
+		delay:       delay,
+		timeout:     cfg.ShutdownTimeout,
+		sleep:       time.Sleep,
+	}
+	if st.db != nil {
+		t.closeDB = func() error {
+			sqlDB, err := st.db.DB()
+			if err != nil {
+				return err
+			}
+			return sqlDB.Close()
+		}
 	}
+	t.run(l)
 }
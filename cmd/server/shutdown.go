@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/antonchaban/articles-go/internal/api"
+
+	"go.uber.org/zap"
+)
+
+// traceFlushTimeout bounds sending the spans left on shutdown.
+const traceFlushTimeout = 5 * time.Second
+
+// teardown holds what the application stops on shutdown.
+type teardown struct {
+	state  *api.State
+	server interface {
+		Shutdown(ctx context.Context) error
+	}
+	// jobsDone is closed once the background jobs returned.
+	jobsDone <-chan struct{}
+	// closeDB closes the DB pool; nil without a database.
+	closeDB     func() error
+	flushTraces func(ctx context.Context) error
+
+	// delay is how long readiness fails before draining starts, so that
+	// load balancers notice; timeout bounds draining and waiting for jobs.
+	delay   time.Duration
+	timeout time.Duration
+	sleep   func(time.Duration)
+}
+
+// run tears the application down in order: fail readiness, wait for load
+// balancers to notice, drain in-flight requests, wait for the background
+// jobs to finish their run, close the DB pool, if there is one, and send
+// the spans not exported yet. The logger is flushed by the deferred cleanup
+// in main.
+func (t *teardown) run(l *zap.Logger) {
+	t.state.StartDraining()
+	l.Info("readiness flipped to draining", zap.Duration("delay", t.delay))
+	if t.delay > 0 {
+		t.sleep(t.delay)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), t.timeout)
+	defer cancel()
+
+	if err := t.server.Shutdown(ctx); err != nil {
+		l.Error("http server did not drain in time", zap.Duration("timeout", t.timeout), zap.Error(err))
+	} else {
+		l.Info("http server drained")
+	}
+
+	select {
+	case <-t.jobsDone:
+	case <-ctx.Done():
+		l.Error("scheduler did not stop in time", zap.Duration("timeout", t.timeout))
+	}
+
+	if t.closeDB != nil {
+		if err := t.closeDB(); err != nil {
+			l.Error("failed to close db pool", zap.Error(err))
+		} else {
+			l.Info("db pool closed")
+		}
+	}
+
+	// the shutdown timeout may be used up by now, which is when the
+	// remaining spans matter most
+	flushCtx, cancelFlush := context.WithTimeout(context.Background(), traceFlushTimeout)
+	defer cancelFlush()
+	if err := t.flushTraces(flushCtx); err != nil {
+		l.Error("failed to flush traces", zap.Error(err))
+	}
+
+	l.Info("shutdown complete")
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/antonchaban/articles-go/internal/api"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// stepRecorder notes the steps of a teardown in the order they happen.
+type stepRecorder struct {
+	mu    sync.Mutex
+	steps []string
+}
+
+func (r *stepRecorder) add(step string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.steps = append(r.steps, step)
+}
+
+func (r *stepRecorder) list() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.steps...)
+}
+
+type fakeServer func(ctx context.Context) error
+
+func (f fakeServer) Shutdown(ctx context.Context) error {
+	return f(ctx)
+}
+
+// newTeardown returns a teardown recording its steps. The background jobs
+// return a moment after the server drained.
+func newTeardown(rec *stepRecorder, delay time.Duration) *teardown {
+	state := &api.State{}
+	jobsDone := make(chan struct{})
+	return &teardown{
+		state: state,
+		server: fakeServer(func(context.Context) error {
+			if state.Draining() {
+				rec.add("drain")
+			}
+			go func() {
+				time.Sleep(20 * time.Millisecond)
+				rec.add("jobs")
+				close(jobsDone)
+			}()
+			return nil
+		}),
+		jobsDone: jobsDone,
+		closeDB: func() error {
+			rec.add("db")
+			return nil
+		},
+		flushTraces: func(ctx context.Context) error {
+			if ctx.Err() == nil {
+				rec.add("traces")
+			}
+			return nil
+		},
+		delay:   delay,
+		timeout: time.Second,
+		sleep: func(time.Duration) {
+			if state.Draining() {
+				rec.add("delay")
+			}
+		},
+	}
+}
+
+func TestTeardownStopsInOrder(t *testing.T) {
+	rec := &stepRecorder{}
+
+	newTeardown(rec, 5*time.Second).run(zap.NewNop())
+
+	assert.Equal(t, []string{"delay", "drain", "jobs", "db", "traces"}, rec.list())
+}
+
+func TestTeardownDrainsRightAwayAfterServerFailure(t *testing.T) {
+	// main drops the delay when the server failed to serve
+	rec := &stepRecorder{}
+
+	newTeardown(rec, 0).run(zap.NewNop())
+
+	assert.Equal(t, []string{"drain", "jobs", "db", "traces"}, rec.list())
+}
+
+func TestTeardownFlushesTracesAfterTimeout(t *testing.T) {
+	rec := &stepRecorder{}
+	td := newTeardown(rec, 0)
+	td.timeout = 10 * time.Millisecond
+	td.server = fakeServer(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	td.run(zap.NewNop())
+
+	assert.Equal(t, []string{"db", "traces"}, rec.list())
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/antonchaban/articles-go/internal/config"
+	"github.com/antonchaban/articles-go/internal/repository"
+	"github.com/antonchaban/articles-go/internal/scheduler"
+	"github.com/antonchaban/articles-go/internal/services"
+	"github.com/antonchaban/articles-go/pkg/database"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Storage drivers selectable through STORAGE_DRIVER.
+const (
+	driverPostgres = "postgres"
+	driverSQLite   = "sqlite"
+	driverMemory   = "memory"
+)
+
+// articleStore is the article storage used by the services and the scheduler.
+type articleStore interface {
+	services.ArticleRepository
+	scheduler.Store
+}
+
+// storage holds the repositories of the configured storage driver.
+type storage struct {
+	articles articleStore
+	authors  services.AuthorRepository
+	// idempotency is nil when the driver can't keep idempotency keys.
+	idempotency *repository.PostgresIdempotencyRepo
+	// db is nil for drivers without a database.
+	db *gorm.DB
+}
+
+// openStorage connects to the storage selected by cfg.StorageDriver.
+// Connecting to a database is retried until ctx is canceled or
+// cfg.DBConnectTimeout has passed.
+func openStorage(ctx context.Context, cfg *config.Config, l *zap.Logger) (*storage, error) {
+	switch cfg.StorageDriver {
+	case driverPostgres:
+		dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%d sslmode=disable",
+			cfg.DBHost, cfg.DBUser, cfg.DBPassword, cfg.DBName, cfg.DBPort)
+
+		db, err := database.NewPostgresConnection(ctx, dsn, dbOptions(cfg), l)
+		if err != nil {
+			return nil, err
+		}
+		return &storage{
+			articles:    repository.NewPostgresRepo(db, l),
+			authors:     repository.NewPostgresAuthorRepo(db, l),
+			idempotency: repository.NewPostgresIdempotencyRepo(db, l),
+			db:          db,
+		}, nil
+
+	case driverSQLite:
+		db, err := database.NewSQLiteConnection(ctx, cfg.SQLitePath, dbOptions(cfg), l)
+		if err != nil {
+			return nil, err
+		}
+		// the author and idempotency queries are plain enough for SQLite
+		return &storage{
+			articles:    repository.NewSQLiteRepo(db, l),
+			authors:     repository.NewPostgresAuthorRepo(db, l),
+			idempotency: repository.NewPostgresIdempotencyRepo(db, l),
+			db:          db,
+		}, nil
+
+	case driverMemory:
+		articles := repository.NewMemoryRepo(l)
+		return &storage{
+			articles: articles,
+			authors:  repository.NewMemoryAuthorRepo(articles, l),
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown storage driver %q, use %q, %q or %q",
+		cfg.StorageDriver, driverPostgres, driverSQLite, driverMemory)
+}
+
+// dbOptions returns the connection pool and retry settings of cfg.
+func dbOptions(cfg *config.Config) database.Options {
+	return database.Options{
+		MaxOpenConns:    cfg.DBMaxOpenConns,
+		MaxIdleConns:    cfg.DBMaxIdleConns,
+		ConnMaxLifetime: cfg.DBConnMaxLifetime,
+		ConnMaxIdleTime: cfg.DBConnMaxIdleTime,
+		Retry: database.RetryPolicy{
+			InitialInterval: cfg.DBConnectRetryInterval,
+			MaxInterval:     cfg.DBConnectRetryMaxInterval,
+			MaxElapsedTime:  cfg.DBConnectTimeout,
+		},
+	}
+}
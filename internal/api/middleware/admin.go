@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/antonchaban/articles-go/internal/api/problem"
+	"github.com/gin-gonic/gin"
+)
+
+// AdminTokenHeader is the request header carrying the admin token.
+const AdminTokenHeader = "X-Admin-Token"
+
+// AdminToken restricts access to requests presenting the configured admin token.
+// If token is empty every request is rejected, which disables admin endpoints.
+func AdminToken(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		provided := c.GetHeader(AdminTokenHeader)
+		if token == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			problem.Write(c, http.StatusForbidden, "admin_required", "admin access required")
+			return
+		}
+
+		c.Next()
+	}
+}
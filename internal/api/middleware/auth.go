@@ -0,0 +1,299 @@
+package middleware
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/antonchaban/articles-go/internal/api/problem"
+	"github.com/antonchaban/articles-go/internal/auth"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// authRealm is advertised in WWW-Authenticate challenges.
+	authRealm = "articles"
+
+	// Gin context keys under which the authenticated principal is stored.
+	ContextSubject = "auth.subject"
+	ContextRoles   = "auth.roles"
+)
+
+// JWTConfig describes where token verification keys come from and which
+// claims a token must carry. At least one key source must be set.
+type JWTConfig struct {
+	// Secret verifies HS256 tokens.
+	Secret string
+	// PublicKeyFile is a PEM encoded RSA public key verifying RS256 tokens.
+	PublicKeyFile string
+	// JWKSFile is a local JSON Web Key Set with RSA and/or symmetric keys.
+	JWKSFile string
+
+	// Issuer and Audience are enforced when not empty.
+	Issuer   string
+	Audience string
+
+	// WriteRole, when set, must be present in the token roles to modify data.
+	WriteRole string
+}
+
+// Claims are the JWT claims understood by the service.
+type Claims struct {
+	jwt.RegisteredClaims
+	Roles []string `json:"roles,omitempty"`
+}
+
+// JWTAuth verifies bearer tokens signed with HS256 or RS256.
+type JWTAuth struct {
+	secrets   map[string][]byte
+	rsaKeys   map[string]*rsa.PublicKey
+	parser    *jwt.Parser
+	writeRole string
+}
+
+// NewJWTAuth loads the verification keys described by cfg.
+func NewJWTAuth(cfg JWTConfig) (*JWTAuth, error) {
+	a := &JWTAuth{
+		secrets:   map[string][]byte{},
+		rsaKeys:   map[string]*rsa.PublicKey{},
+		writeRole: cfg.WriteRole,
+	}
+
+	if cfg.Secret != "" {
+		a.secrets[""] = []byte(cfg.Secret)
+	}
+
+	if cfg.PublicKeyFile != "" {
+		pemBytes, err := os.ReadFile(cfg.PublicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read jwt public key: %w", err)
+		}
+		key, err := jwt.ParseRSAPublicKeyFromPEM(pemBytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse jwt public key: %w", err)
+		}
+		a.rsaKeys[""] = key
+	}
+
+	if cfg.JWKSFile != "" {
+		if err := a.loadJWKS(cfg.JWKSFile); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(a.secrets) == 0 && len(a.rsaKeys) == 0 {
+		return nil, errors.New("no jwt verification key configured")
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg(), jwt.SigningMethodRS256.Alg()}),
+		jwt.WithExpirationRequired(),
+	}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+	a.parser = jwt.NewParser(opts...)
+
+	return a, nil
+}
+
+// jwk is the subset of RFC 7517 fields needed for RSA and symmetric keys.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	K   string `json:"k"`
+}
+
+func (a *JWTAuth) loadJWKS(path string) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read jwks: %w", err)
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(raw, &set); err != nil {
+		return fmt.Errorf("failed to parse jwks: %w", err)
+	}
+
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+
+		switch k.Kty {
+		case "RSA":
+			n, err := base64.RawURLEncoding.DecodeString(k.N)
+			if err != nil {
+				return fmt.Errorf("invalid modulus of jwk %q: %w", k.Kid, err)
+			}
+			e, err := base64.RawURLEncoding.DecodeString(k.E)
+			if err != nil {
+				return fmt.Errorf("invalid exponent of jwk %q: %w", k.Kid, err)
+			}
+			a.rsaKeys[k.Kid] = &rsa.PublicKey{
+				N: new(big.Int).SetBytes(n),
+				E: int(new(big.Int).SetBytes(e).Int64()),
+			}
+		case "oct":
+			secret, err := base64.RawURLEncoding.DecodeString(k.K)
+			if err != nil {
+				return fmt.Errorf("invalid secret of jwk %q: %w", k.Kid, err)
+			}
+			a.secrets[k.Kid] = secret
+		}
+	}
+	return nil
+}
+
+// keyFor picks the verification key matching the token algorithm and kid.
+// Tokens without a kid are accepted when exactly one key of that type exists.
+func (a *JWTAuth) keyFor(t *jwt.Token) (any, error) {
+	kid, _ := t.Header["kid"].(string)
+
+	switch t.Method.Alg() {
+	case jwt.SigningMethodHS256.Alg():
+		return lookupKey(a.secrets, kid)
+	case jwt.SigningMethodRS256.Alg():
+		return lookupKey(a.rsaKeys, kid)
+	}
+	return nil, fmt.Errorf("unexpected signing method %q", t.Method.Alg())
+}
+
+func lookupKey[K any](keys map[string]K, kid string) (K, error) {
+	if key, ok := keys[kid]; ok {
+		return key, nil
+	}
+	if kid == "" && len(keys) == 1 {
+		for _, key := range keys {
+			return key, nil
+		}
+	}
+	var zero K
+	return zero, errors.New("unknown signing key")
+}
+
+// RequireWrite authenticates requests that modify data (POST, PUT, PATCH and
+// DELETE). Safe methods are let through; they are only authenticated when
+// they carry a token, so that callers can read their own unpublished
+// articles, and are marked anonymous otherwise. The token subject and roles
+// are stored in the gin context under ContextSubject and ContextRoles, and as
+// an auth.Principal in the request context for the service layer.
+func (a *JWTAuth) RequireWrite() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		safe := false
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			safe = true
+		}
+
+		header := c.GetHeader("Authorization")
+		if header == "" {
+			if safe {
+				c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), auth.Anonymous))
+				c.Next()
+				return
+			}
+			// RFC 6750 3.1: no error code when credentials are simply missing
+			challenge(c, http.StatusUnauthorized, "", "")
+			return
+		}
+
+		claims, ok := a.authenticate(c, header)
+		if !ok {
+			return
+		}
+
+		if !safe && a.writeRole != "" && !slices.Contains(claims.Roles, a.writeRole) {
+			challenge(c, http.StatusForbidden, "insufficient_scope", fmt.Sprintf("role %q required", a.writeRole))
+			return
+		}
+
+		c.Set(ContextSubject, claims.Subject)
+		c.Set(ContextRoles, claims.Roles)
+		c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), auth.Principal{
+			Subject: claims.Subject,
+			Roles:   claims.Roles,
+		}))
+		c.Next()
+	}
+}
+
+// authenticate verifies the bearer token of an Authorization header.
+// It aborts with a challenge and returns false if the token is not acceptable.
+func (a *JWTAuth) authenticate(c *gin.Context, header string) (*Claims, bool) {
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		challenge(c, http.StatusBadRequest, "invalid_request", "malformed Authorization header")
+		return nil, false
+	}
+
+	var claims Claims
+	if _, err := a.parser.ParseWithClaims(strings.TrimSpace(token), &claims, a.keyFor); err != nil {
+		challenge(c, http.StatusUnauthorized, "invalid_token", tokenErrorDescription(err))
+		return nil, false
+	}
+	if claims.Subject == "" {
+		challenge(c, http.StatusUnauthorized, "invalid_token", "token has no subject")
+		return nil, false
+	}
+	return &claims, true
+}
+
+// Subject returns the authenticated subject, or "" for anonymous requests.
+func Subject(c *gin.Context) string {
+	return c.GetString(ContextSubject)
+}
+
+// Roles returns the roles of the authenticated subject.
+func Roles(c *gin.Context) []string {
+	return c.GetStringSlice(ContextRoles)
+}
+
+// challenge aborts with an RFC 6750 WWW-Authenticate challenge and a problem
+// carrying the same error code.
+func challenge(c *gin.Context, status int, code, description string) {
+	value := fmt.Sprintf("Bearer realm=%q", authRealm)
+	if code != "" {
+		value += fmt.Sprintf(", error=%q", code)
+	}
+	if description != "" {
+		value += fmt.Sprintf(", error_description=%q", description)
+	}
+	c.Header("WWW-Authenticate", value)
+
+	if code == "" {
+		code = "unauthorized"
+	}
+	problem.Write(c, status, code, description)
+}
+
+// tokenErrorDescription hides verification details from the client
+// apart from the ones that help it recover, like expiry.
+func tokenErrorDescription(err error) string {
+	switch {
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return "token expired"
+	case errors.Is(err, jwt.ErrTokenNotValidYet):
+		return "token not valid yet"
+	case errors.Is(err, jwt.ErrTokenInvalidIssuer), errors.Is(err, jwt.ErrTokenInvalidAudience):
+		return "token not issued for this service"
+	}
+	return "invalid token"
+}
@@ -0,0 +1,250 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/antonchaban/articles-go/internal/auth"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecret = "test-secret"
+
+func setupAuthRouter(t *testing.T, cfg JWTConfig) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	auth, err := NewJWTAuth(cfg)
+	require.NoError(t, err)
+
+	r := gin.New()
+	r.Use(auth.RequireWrite())
+	ok := func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"subject": Subject(c), "roles": Roles(c)})
+	}
+	r.GET("/articles", ok)
+	r.POST("/articles", ok)
+	return r
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, key any, claims Claims, kid string) string {
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	s, err := token.SignedString(key)
+	require.NoError(t, err)
+	return s
+}
+
+func validClaims() Claims {
+	return Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "user-1",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+		Roles: []string{"editor"},
+	}
+}
+
+func post(r *gin.Engine, authorization string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/articles", nil)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestNewJWTAuthRequiresKey(t *testing.T) {
+	_, err := NewJWTAuth(JWTConfig{})
+	assert.Error(t, err)
+}
+
+func TestRequireWriteSkipsSafeMethods(t *testing.T) {
+	r := setupAuthRouter(t, JWTConfig{Secret: testSecret})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/articles", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRequireWriteMissingToken(t *testing.T) {
+	r := setupAuthRouter(t, JWTConfig{Secret: testSecret})
+
+	w := post(r, "")
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, `Bearer realm="articles"`, w.Header().Get("WWW-Authenticate"))
+}
+
+func TestRequireWriteMalformedHeader(t *testing.T) {
+	r := setupAuthRouter(t, JWTConfig{Secret: testSecret})
+
+	w := post(r, "Basic dXNlcjpwYXNz")
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Header().Get("WWW-Authenticate"), `error="invalid_request"`)
+}
+
+func TestRequireWriteHS256(t *testing.T) {
+	r := setupAuthRouter(t, JWTConfig{Secret: testSecret})
+	token := sign(t, jwt.SigningMethodHS256, []byte(testSecret), validClaims(), "")
+
+	w := post(r, "Bearer "+token)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"subject":"user-1","roles":["editor"]}`, w.Body.String())
+}
+
+func TestRequireWriteRejectsInvalidTokens(t *testing.T) {
+	expired := validClaims()
+	expired.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+
+	noExpiry := validClaims()
+	noExpiry.ExpiresAt = nil
+
+	wrongIssuer := validClaims()
+	wrongIssuer.Issuer = "someone-else"
+
+	tests := []struct {
+		name        string
+		token       string
+		description string
+	}{
+		{"wrong secret", sign(t, jwt.SigningMethodHS256, []byte("other"), validClaims(), ""), "invalid token"},
+		{"expired", sign(t, jwt.SigningMethodHS256, []byte(testSecret), expired, ""), "token expired"},
+		{"no expiry", sign(t, jwt.SigningMethodHS256, []byte(testSecret), noExpiry, ""), "invalid token"},
+		{"wrong issuer", sign(t, jwt.SigningMethodHS256, []byte(testSecret), wrongIssuer, ""), "token not issued for this service"},
+		{"unsupported alg", sign(t, jwt.SigningMethodHS384, []byte(testSecret), validClaims(), ""), "invalid token"},
+		{"garbage", "not-a-jwt", "invalid token"},
+	}
+
+	r := setupAuthRouter(t, JWTConfig{Secret: testSecret, Issuer: "articles-auth"})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := post(r, "Bearer "+tt.token)
+
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+			assert.Equal(t,
+				fmt.Sprintf(`Bearer realm="articles", error="invalid_token", error_description=%q`, tt.description),
+				w.Header().Get("WWW-Authenticate"))
+		})
+	}
+}
+
+func TestRequireWriteMissingRole(t *testing.T) {
+	r := setupAuthRouter(t, JWTConfig{Secret: testSecret, WriteRole: "writer"})
+	token := sign(t, jwt.SigningMethodHS256, []byte(testSecret), validClaims(), "")
+
+	w := post(r, "Bearer "+token)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Header().Get("WWW-Authenticate"), `error="insufficient_scope"`)
+}
+
+func TestRequireWriteRS256PublicKeyFile(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "public.pem")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600))
+
+	r := setupAuthRouter(t, JWTConfig{PublicKeyFile: path})
+
+	w := post(r, "Bearer "+sign(t, jwt.SigningMethodRS256, key, validClaims(), ""))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// an HS256 token must not be verified with the RSA key
+	w = post(r, "Bearer "+sign(t, jwt.SigningMethodHS256, []byte(testSecret), validClaims(), ""))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestRequireWriteJWKSFile(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	b64 := base64.RawURLEncoding.EncodeToString
+	jwks := fmt.Sprintf(`{"keys":[
+		{"kty":"RSA","kid":"rsa-1","use":"sig","n":%q,"e":%q},
+		{"kty":"oct","kid":"hmac-1","k":%q}
+	]}`, b64(key.N.Bytes()), b64(big.NewInt(int64(key.E)).Bytes()), b64([]byte(testSecret)))
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, []byte(jwks), 0o600))
+
+	r := setupAuthRouter(t, JWTConfig{JWKSFile: path})
+
+	w := post(r, "Bearer "+sign(t, jwt.SigningMethodRS256, key, validClaims(), "rsa-1"))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = post(r, "Bearer "+sign(t, jwt.SigningMethodHS256, []byte(testSecret), validClaims(), "hmac-1"))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = post(r, "Bearer "+sign(t, jwt.SigningMethodRS256, key, validClaims(), "unknown"))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestRequireWriteAuthenticatesReadsWithToken(t *testing.T) {
+	r := setupAuthRouter(t, JWTConfig{Secret: testSecret, WriteRole: "writer"})
+	token := sign(t, jwt.SigningMethodHS256, []byte(testSecret), validClaims(), "")
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/articles", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	r.ServeHTTP(w, req)
+
+	// the write role is not needed to read
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"subject":"user-1","roles":["editor"]}`, w.Body.String())
+}
+
+func TestRequireWriteRejectsReadsWithInvalidToken(t *testing.T) {
+	r := setupAuthRouter(t, JWTConfig{Secret: testSecret})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/articles", nil)
+	req.Header.Set("Authorization", "Bearer not-a-token")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestRequireWriteMarksAnonymousReads(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	a, err := NewJWTAuth(JWTConfig{Secret: testSecret})
+	require.NoError(t, err)
+
+	r := gin.New()
+	r.Use(a.RequireWrite())
+	r.GET("/articles", func(c *gin.Context) {
+		p, ok := auth.FromContext(c.Request.Context())
+		c.JSON(http.StatusOK, gin.H{"present": ok, "anonymous": p.IsAnonymous()})
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/articles", nil)
+	r.ServeHTTP(w, req)
+
+	assert.JSONEq(t, `{"present":true,"anonymous":true}`, w.Body.String())
+}
@@ -0,0 +1,183 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/antonchaban/articles-go/internal/api/problem"
+	"github.com/antonchaban/articles-go/internal/auth"
+	"github.com/antonchaban/articles-go/internal/entities"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	// IdempotencyKeyHeader carries the key a client picked for a POST request
+	// it may retry.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader marks responses replayed from an earlier request.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	// maxIdempotencyKeyLength bounds the keys clients may pick.
+	maxIdempotencyKeyLength = 255
+)
+
+// replayedHeaders are the response headers stored and replayed with a response.
+var replayedHeaders = []string{"Content-Type", "Location", "ETag"}
+
+// IdempotencyStore keeps the responses of requests sent with an idempotency key.
+type IdempotencyStore interface {
+	// Reserve claims k.Key for k.Subject unless it is taken by a record that
+	// has not expired, which is then returned instead.
+	Reserve(ctx context.Context, k *entities.IdempotencyKey) (*entities.IdempotencyKey, error)
+	// Complete stores the response of a reserved key.
+	Complete(ctx context.Context, k *entities.IdempotencyKey) error
+	// Release frees the reservation k without a response.
+	Release(ctx context.Context, k *entities.IdempotencyKey) error
+}
+
+// Idempotency makes POST requests carrying an Idempotency-Key header safe to
+// retry. The first request with a key is handled and its response kept for
+// ttl; later requests with the same key and the same method, path and body
+// get that response replayed. Reusing a key for a different request, or while
+// its first request is still being handled, is rejected with 409.
+//
+// Keys are scoped to the authenticated subject, so the middleware must run
+// after authentication. While authentication is enabled, anonymous requests
+// can't use keys, as they would see each other's responses; while it is
+// disabled, every caller may do anything anyway and all keys share one
+// anonymous scope. Server errors and panics are not kept, so that the
+// request can be retried with the same key.
+func Idempotency(store IdempotencyStore, ttl time.Duration, logger *zap.Logger) gin.HandlerFunc {
+	log := logger.With(zap.String("layer", "middleware"))
+
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if c.Request.Method != http.MethodPost || key == "" {
+			c.Next()
+			return
+		}
+		subject := Subject(c)
+		if p, ok := auth.FromContext(c.Request.Context()); ok && p.IsAnonymous() {
+			problem.Write(c, http.StatusBadRequest, problem.CodeInvalidRequest, "Idempotency-Key requires an authenticated request")
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			problem.Write(c, http.StatusBadRequest, problem.CodeInvalidRequest, "Idempotency-Key must be at most 255 characters long")
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			problem.Write(c, http.StatusBadRequest, problem.CodeInvalidRequest, "failed to read request body")
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		// the database keeps microseconds, and the reservation is told
+		// apart from ones taking it over by its creation time
+		now := time.Now().UTC().Truncate(time.Microsecond)
+		record := &entities.IdempotencyKey{
+			Subject:     subject,
+			Key:         key,
+			RequestHash: requestHash(c.Request, body),
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(ttl),
+		}
+
+		ctx := c.Request.Context()
+		stored, err := store.Reserve(ctx, record)
+		if err != nil {
+			problem.Error(c, err)
+			return
+		}
+		if stored != nil {
+			switch {
+			case stored.RequestHash != record.RequestHash:
+				problem.Write(c, http.StatusConflict, "idempotency_key_reused", "Idempotency-Key was already used for a different request")
+			case !stored.Completed():
+				problem.Write(c, http.StatusConflict, "idempotency_key_in_use", "a request with this Idempotency-Key is still being processed")
+			default:
+				replay(c, stored)
+			}
+			return
+		}
+
+		// the outcome is kept even if the client went away meanwhile
+		ctx = context.WithoutCancel(ctx)
+		completed := false
+		defer func() {
+			// runs while a panic unwinds as well
+			if completed {
+				return
+			}
+			if err := store.Release(ctx, record); err != nil {
+				log.Error("failed to release idempotency key", zap.String("key", key), zap.Error(err))
+			}
+		}()
+
+		w := &recordingWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+
+		if w.Status() >= http.StatusInternalServerError {
+			return
+		}
+
+		completed = true
+		record.Status = w.Status()
+		record.Header = map[string]string{}
+		for _, h := range replayedHeaders {
+			if v := w.Header().Get(h); v != "" {
+				record.Header[h] = v
+			}
+		}
+		record.Body = w.body.Bytes()
+		if err := store.Complete(ctx, record); err != nil {
+			log.Error("failed to store idempotent response", zap.String("key", key), zap.Error(err))
+		}
+	}
+}
+
+// requestHash identifies a request by its method, path, query and body.
+func requestHash(r *http.Request, body []byte) string {
+	h := sha256.New()
+	io.WriteString(h, r.Method+" "+r.URL.RequestURI()+"\n")
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// replay answers the request with the response stored in k.
+func replay(c *gin.Context, k *entities.IdempotencyKey) {
+	for h, v := range k.Header {
+		c.Header(h, v)
+	}
+	c.Header(IdempotentReplayedHeader, "true")
+	c.Status(k.Status)
+	if len(k.Body) > 0 {
+		c.Writer.Write(k.Body)
+	}
+	c.Abort()
+}
+
+// recordingWriter keeps a copy of the response body written through it.
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,235 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/antonchaban/articles-go/internal/auth"
+	"github.com/antonchaban/articles-go/internal/entities"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type fakeIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]*entities.IdempotencyKey
+}
+
+func (s *fakeIdempotencyStore) Reserve(_ context.Context, k *entities.IdempotencyKey) (*entities.IdempotencyKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if stored, ok := s.records[k.Subject+"/"+k.Key]; ok && stored.ExpiresAt.After(k.CreatedAt) {
+		cp := *stored
+		return &cp, nil
+	}
+	cp := *k
+	s.records[k.Subject+"/"+k.Key] = &cp
+	return nil, nil
+}
+
+func (s *fakeIdempotencyStore) Complete(_ context.Context, k *entities.IdempotencyKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cp := *k
+	s.records[k.Subject+"/"+k.Key] = &cp
+	return nil
+}
+
+func (s *fakeIdempotencyStore) Release(_ context.Context, k *entities.IdempotencyKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, k.Subject+"/"+k.Key)
+	return nil
+}
+
+func setupIdempotencyRouter(store IdempotencyStore, calls *int, status int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	// stands in for JWTAuth, which is left out by X-Test-Auth: disabled
+	r.Use(func(c *gin.Context) {
+		if c.GetHeader("X-Test-Auth") == "disabled" {
+			return
+		}
+		subject := c.GetHeader("X-Test-Subject")
+		c.Set(ContextSubject, subject)
+		c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), auth.Principal{Subject: subject}))
+	})
+	r.Use(Idempotency(store, time.Hour, zap.NewNop()))
+	r.POST("/articles", func(c *gin.Context) {
+		*calls++
+		// status 0 stands for a handler that panics
+		if status == 0 {
+			panic("handler failed")
+		}
+		c.Header("Location", "/api/v1/articles/1")
+		c.JSON(status, gin.H{"id": *calls})
+	})
+	return r
+}
+
+func postWithKey(r http.Handler, key, body string) *httptest.ResponseRecorder {
+	return postAs(r, "alice", key, body)
+}
+
+func postAs(r http.Handler, subject, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/articles", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Test-Subject", subject)
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotencyReplaysStoredResponse(t *testing.T) {
+	store := &fakeIdempotencyStore{records: map[string]*entities.IdempotencyKey{}}
+	calls := 0
+	r := setupIdempotencyRouter(store, &calls, http.StatusCreated)
+
+	first := postWithKey(r, "key-1", `{"title":"Hello"}`)
+	retry := postWithKey(r, "key-1", `{"title":"Hello"}`)
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, http.StatusCreated, retry.Code)
+	assert.JSONEq(t, first.Body.String(), retry.Body.String())
+	assert.Equal(t, "/api/v1/articles/1", retry.Header().Get("Location"))
+	assert.Equal(t, "true", retry.Header().Get(IdempotentReplayedHeader))
+	assert.Empty(t, first.Header().Get(IdempotentReplayedHeader))
+}
+
+func TestIdempotencyRejectsKeyReusedWithDifferentBody(t *testing.T) {
+	store := &fakeIdempotencyStore{records: map[string]*entities.IdempotencyKey{}}
+	calls := 0
+	r := setupIdempotencyRouter(store, &calls, http.StatusCreated)
+
+	postWithKey(r, "key-1", `{"title":"Hello"}`)
+	w := postWithKey(r, "key-1", `{"title":"Other"}`)
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"idempotency_key_reused"`)
+}
+
+func TestIdempotencyRejectsKeyInUse(t *testing.T) {
+	store := &fakeIdempotencyStore{records: map[string]*entities.IdempotencyKey{}}
+	calls := 0
+	r := setupIdempotencyRouter(store, &calls, http.StatusCreated)
+
+	// a reservation without a response belongs to a request still in flight
+	req := httptest.NewRequest(http.MethodPost, "/articles", strings.NewReader(`{}`))
+	store.records["alice/key-1"] = &entities.IdempotencyKey{
+		Subject:     "alice",
+		Key:         "key-1",
+		RequestHash: requestHash(req, []byte(`{}`)),
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}
+	w := postWithKey(r, "key-1", `{}`)
+
+	assert.Equal(t, 0, calls)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"idempotency_key_in_use"`)
+}
+
+func TestIdempotencyReleasesKeyOnServerError(t *testing.T) {
+	store := &fakeIdempotencyStore{records: map[string]*entities.IdempotencyKey{}}
+	calls := 0
+	r := setupIdempotencyRouter(store, &calls, http.StatusServiceUnavailable)
+
+	postWithKey(r, "key-1", `{}`)
+	postWithKey(r, "key-1", `{}`)
+
+	assert.Equal(t, 2, calls)
+	assert.Empty(t, store.records)
+}
+
+func TestIdempotencyReleasesKeyWhenHandlerPanics(t *testing.T) {
+	store := &fakeIdempotencyStore{records: map[string]*entities.IdempotencyKey{}}
+	calls := 0
+	r := setupIdempotencyRouter(store, &calls, 0)
+
+	assert.Panics(t, func() { postWithKey(r, "key-1", `{}`) })
+
+	assert.Empty(t, store.records, "a retry must not be told the key is in use")
+}
+
+func TestIdempotencyRejectsAnonymousRequestsWhenAuthIsEnabled(t *testing.T) {
+	store := &fakeIdempotencyStore{records: map[string]*entities.IdempotencyKey{}}
+	calls := 0
+	r := setupIdempotencyRouter(store, &calls, http.StatusCreated)
+
+	w := postAs(r, "", "key-1", `{}`)
+
+	assert.Equal(t, 0, calls)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Empty(t, store.records)
+}
+
+func TestIdempotencyReplaysWithAuthDisabled(t *testing.T) {
+	store := &fakeIdempotencyStore{records: map[string]*entities.IdempotencyKey{}}
+	calls := 0
+	r := setupIdempotencyRouter(store, &calls, http.StatusCreated)
+	post := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/articles", strings.NewReader(`{"title":"Hello"}`))
+		req.Header.Set(IdempotencyKeyHeader, "key-1")
+		req.Header.Set("X-Test-Auth", "disabled")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	first := post()
+	retry := post()
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, http.StatusCreated, first.Code)
+	assert.Equal(t, http.StatusCreated, retry.Code)
+	assert.Equal(t, "true", retry.Header().Get(IdempotentReplayedHeader))
+}
+
+func TestIdempotencyScopesKeysToSubject(t *testing.T) {
+	store := &fakeIdempotencyStore{records: map[string]*entities.IdempotencyKey{}}
+	calls := 0
+	r := setupIdempotencyRouter(store, &calls, http.StatusCreated)
+
+	postAs(r, "alice", "key-1", `{}`)
+	w := postAs(r, "bob", "key-1", `{}`)
+
+	assert.Equal(t, 2, calls)
+	assert.Empty(t, w.Header().Get(IdempotentReplayedHeader))
+}
+
+func TestIdempotencyIgnoresRequestsWithoutKey(t *testing.T) {
+	store := &fakeIdempotencyStore{records: map[string]*entities.IdempotencyKey{}}
+	calls := 0
+	r := setupIdempotencyRouter(store, &calls, http.StatusCreated)
+
+	postWithKey(r, "", `{}`)
+	postWithKey(r, "", `{}`)
+
+	assert.Equal(t, 2, calls)
+	assert.Empty(t, store.records)
+}
+
+func TestIdempotencyRejectsLongKey(t *testing.T) {
+	store := &fakeIdempotencyStore{records: map[string]*entities.IdempotencyKey{}}
+	calls := 0
+	r := setupIdempotencyRouter(store, &calls, http.StatusCreated)
+
+	w := postWithKey(r, strings.Repeat("k", 256), `{}`)
+
+	assert.Equal(t, 0, calls)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.43.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func setupTracingRouter(t *testing.T) (*gin.Engine, *tracetest.SpanRecorder) {
+	gin.SetMode(gin.TestMode)
+
+	spans := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	r := gin.New()
+	r.Use(Tracing())
+	r.GET("/articles/:id", func(c *gin.Context) {
+		sc := trace.SpanContextFromContext(c.Request.Context())
+		c.String(http.StatusOK, sc.TraceID().String())
+	})
+	r.GET("/broken", func(c *gin.Context) {
+		c.Status(http.StatusInternalServerError)
+	})
+	return r, spans
+}
+
+func TestTracingContinuesTraceOfCaller(t *testing.T) {
+	r, spans := setupTracingRouter(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/articles/42", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", w.Body.String(), "handlers see the span in the request context")
+	ended := spans.Ended()
+	require.Len(t, ended, 1)
+	span := ended[0]
+	assert.Equal(t, "GET /articles/:id", span.Name())
+	assert.Equal(t, trace.SpanKindServer, span.SpanKind())
+	assert.Equal(t, "00f067aa0ba902b7", span.Parent().SpanID().String())
+	assert.True(t, span.Parent().IsRemote())
+	assert.Contains(t, span.Attributes(), semconv.HTTPResponseStatusCode(http.StatusOK))
+	assert.Equal(t, codes.Unset, span.Status().Code)
+}
+
+func TestTracingStartsNewTraceWithoutTraceparent(t *testing.T) {
+	r, spans := setupTracingRouter(t)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/articles/42", nil))
+
+	ended := spans.Ended()
+	require.Len(t, ended, 1)
+	assert.False(t, ended[0].Parent().IsValid())
+	assert.Equal(t, ended[0].SpanContext().TraceID().String(), w.Body.String())
+}
+
+func TestTracingMarksServerErrors(t *testing.T) {
+	r, spans := setupTracingRouter(t)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/broken", nil))
+
+	ended := spans.Ended()
+	require.Len(t, ended, 1)
+	assert.Equal(t, codes.Error, ended[0].Status().Code)
+}
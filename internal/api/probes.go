@@ -0,0 +1,58 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/antonchaban/articles-go/internal/health"
+
+	"github.com/gin-gonic/gin"
+)
+
+// registerProbes adds the Kubernetes style probe endpoints:
+//   - /livez reports whether the process is able to serve requests at all
+//   - /readyz additionally checks dependencies and fails while draining
+//   - /healthz is the same as /readyz, intended for humans and monitoring
+//
+// /readyz and /healthz return per-check details when ?verbose is present.
+// /health is kept for existing deployments and behaves like before.
+func registerProbes(r *gin.Engine, state *State, checks *health.Registry) {
+	r.GET("/livez", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": health.StatusOK})
+	})
+
+	ready := readiness(state, checks)
+	r.GET("/readyz", ready)
+	r.GET("/healthz", ready)
+
+	r.GET("/health", func(c *gin.Context) {
+		if state.Draining() {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"status": "draining"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "alive"})
+	})
+}
+
+func readiness(state *State, checks *health.Registry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// draining is checked on every request, not cached with the
+		// dependency checks, so traffic stops as soon as shutdown begins
+		if state.Draining() {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"status": "draining"})
+			return
+		}
+
+		report := checks.Run(c.Request.Context())
+
+		code := http.StatusOK
+		if !report.Healthy() {
+			code = http.StatusServiceUnavailable
+		}
+
+		if _, verbose := c.GetQuery("verbose"); verbose {
+			c.JSON(code, report)
+			return
+		}
+		c.JSON(code, gin.H{"status": report.Status})
+	}
+}
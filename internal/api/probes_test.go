@@ -0,0 +1,96 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/antonchaban/articles-go/internal/health"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func setupProbeRouter(state *State, checks *health.Registry) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	registerProbes(r, state, checks)
+	return r
+}
+
+func get(r *gin.Engine, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, path, nil)
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestLivezIgnoresDependencies(t *testing.T) {
+	checks := health.NewRegistry(time.Second, 0, zap.NewNop())
+	checks.Register("database", func(ctx context.Context) error { return errors.New("down") })
+	r := setupProbeRouter(&State{}, checks)
+
+	w := get(r, "/livez")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"status":"ok"}`, w.Body.String())
+}
+
+func TestReadyzReportsFailingCheck(t *testing.T) {
+	checks := health.NewRegistry(time.Second, 0, zap.NewNop())
+	checks.Register("database", func(ctx context.Context) error { return errors.New("down") })
+	r := setupProbeRouter(&State{}, checks)
+
+	w := get(r, "/readyz")
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.JSONEq(t, `{"status":"fail"}`, w.Body.String())
+}
+
+func TestReadyzFailsWhileDraining(t *testing.T) {
+	state := &State{}
+	state.StartDraining()
+	r := setupProbeRouter(state, health.NewRegistry(time.Second, 0, zap.NewNop()))
+
+	w := get(r, "/readyz")
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.JSONEq(t, `{"status":"draining"}`, w.Body.String())
+}
+
+func TestHealthzVerboseListsChecks(t *testing.T) {
+	checks := health.NewRegistry(time.Second, 0, zap.NewNop())
+	checks.Register("database", func(ctx context.Context) error { return nil })
+	r := setupProbeRouter(&State{}, checks)
+
+	w := get(r, "/healthz?verbose")
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var report health.Report
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, health.StatusOK, report.Status)
+	require.Len(t, report.Checks, 1)
+	assert.Equal(t, "database", report.Checks[0].Name)
+	assert.Equal(t, health.StatusOK, report.Checks[0].Status)
+}
+
+func TestHealthzVerboseHidesErrorDetail(t *testing.T) {
+	checks := health.NewRegistry(time.Second, 0, zap.NewNop())
+	checks.Register("database", func(ctx context.Context) error {
+		return errors.New("dial tcp 10.0.0.5:5432: connect: connection refused")
+	})
+	r := setupProbeRouter(&State{}, checks)
+
+	w := get(r, "/healthz?verbose")
+
+	require.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.NotContains(t, w.Body.String(), "10.0.0.5")
+	assert.Contains(t, w.Body.String(), `"error":"check failed"`)
+}
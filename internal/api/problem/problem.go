@@ -0,0 +1,131 @@
+// Package problem renders error responses as RFC 7807 problem details.
+package problem
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/antonchaban/articles-go/internal/services"
+	"github.com/antonchaban/articles-go/pkg/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ContentType is the media type of problem details.
+const ContentType = "application/problem+json"
+
+// Codes of problems that don't originate from a domain error.
+const (
+	// CodeInvalidRequest is used for malformed parameters, headers and bodies.
+	CodeInvalidRequest = "invalid_request"
+	// CodeUnsupportedMediaType is used for request bodies of an unsupported type.
+	CodeUnsupportedMediaType = "unsupported_media_type"
+	// CodePreconditionRequired is used for conditional requests missing If-Match.
+	CodePreconditionRequired = "precondition_required"
+	// CodeValidationFailed is used for input breaking validation rules.
+	CodeValidationFailed = "validation_failed"
+	// CodeNotFound is used for requests to routes that don't exist.
+	CodeNotFound = "not_found"
+	// CodeInternal is used for unexpected failures.
+	CodeInternal = "internal_error"
+)
+
+// Details is an RFC 7807 problem details object. Code extends it with a
+// stable, machine-readable identifier of the problem that clients can act on;
+// Detail is meant for humans and may change. Errors lists the offending
+// fields of input that failed validation.
+type Details struct {
+	Type     string            `json:"type"`
+	Title    string            `json:"title"`
+	Status   int               `json:"status"`
+	Detail   string            `json:"detail,omitempty"`
+	Instance string            `json:"instance,omitempty"`
+	Code     string            `json:"code"`
+	Errors   validation.Errors `json:"errors,omitempty"`
+}
+
+// statuses maps the kinds of domain errors to HTTP status codes.
+var statuses = map[services.Kind]int{
+	services.KindInternal:    http.StatusInternalServerError,
+	services.KindNotFound:    http.StatusNotFound,
+	services.KindInvalid:     http.StatusBadRequest,
+	services.KindValidation:  http.StatusUnprocessableEntity,
+	services.KindConflict:    http.StatusConflict,
+	services.KindStale:       http.StatusPreconditionFailed,
+	services.KindForbidden:   http.StatusForbidden,
+	services.KindUnavailable: http.StatusServiceUnavailable,
+	services.KindAborted:     http.StatusFailedDependency,
+}
+
+// Write aborts the request with a problem of the given status and code.
+// Problems carry no type of their own ("about:blank"); the code tells them apart.
+func Write(c *gin.Context, status int, code, detail string) {
+	write(c, New(status, code, detail))
+}
+
+// New returns a problem of the given status and code.
+func New(status int, code, detail string) Details {
+	return Details{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+func write(c *gin.Context, d Details) {
+	d.Instance = c.Request.URL.Path
+	c.Header("Content-Type", ContentType)
+	c.AbortWithStatusJSON(d.Status, d)
+}
+
+// Invalid aborts the request with a problem for input that could not be
+// bound. Input breaking validation rules is answered with status and lists
+// every offending field; anything else, like malformed JSON, is a 400.
+func Invalid(c *gin.Context, status int, err error) {
+	fields, ok := validation.Fields(err)
+	if !ok {
+		Write(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+	d := New(status, CodeValidationFailed, fields.Error())
+	d.Errors = fields
+	write(c, d)
+}
+
+// Error aborts the request with the problem describing err, see Of.
+func Error(c *gin.Context, err error) {
+	write(c, Of(err))
+}
+
+// Of describes err as a problem. Domain errors of the services package are
+// mapped by kind and keep their code; anything else is an internal error.
+// Details of server-side failures are not exposed.
+func Of(err error) Details {
+	var e *services.Error
+	if !errors.As(err, &e) {
+		return New(http.StatusInternalServerError, CodeInternal, "internal server error")
+	}
+
+	status := Status(err)
+	detail := err.Error()
+	if status >= http.StatusInternalServerError {
+		detail = e.Message
+	}
+	d := New(status, e.Code, detail)
+	d.Errors, _ = validation.Fields(err)
+	return d
+}
+
+// Status returns the HTTP status code Error responds to err with.
+func Status(err error) int {
+	var e *services.Error
+	if !errors.As(err, &e) {
+		return http.StatusInternalServerError
+	}
+	if status, ok := statuses[e.Kind]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,121 @@
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/antonchaban/articles-go/internal/services"
+	"github.com/antonchaban/articles-go/pkg/validation"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serve(t *testing.T, err error) (*httptest.ResponseRecorder, Details) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/articles/:id", func(c *gin.Context) { Error(c, err) })
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/articles/1", nil))
+
+	var d Details
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &d))
+	return w, d
+}
+
+func TestErrorRendersDomainErrors(t *testing.T) {
+	w, d := serve(t, fmt.Errorf("%w: title cannot be empty", services.ErrInvalidArticle))
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, ContentType, w.Header().Get("Content-Type"))
+	assert.Equal(t, Details{
+		Type:     "about:blank",
+		Title:    "Unprocessable Entity",
+		Status:   http.StatusUnprocessableEntity,
+		Detail:   "invalid article: title cannot be empty",
+		Instance: "/articles/1",
+		Code:     "invalid_article",
+	}, d)
+}
+
+func TestErrorMapsKinds(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+	}{
+		{services.ErrArticleNotFound, http.StatusNotFound},
+		{services.ErrInvalidCursor, http.StatusBadRequest},
+		{services.ErrInvalidTransition, http.StatusConflict},
+		{services.ErrVersionConflict, http.StatusPreconditionFailed},
+		{services.ErrForbidden, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			w, d := serve(t, tt.err)
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, tt.status, d.Status)
+		})
+	}
+}
+
+func TestErrorHidesServerSideCauses(t *testing.T) {
+	w, d := serve(t, services.Unavailable(errors.New("dial tcp 10.0.0.1:5432: connection refused")))
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "unavailable", d.Code)
+	assert.Equal(t, "storage unavailable", d.Detail)
+
+	w, d = serve(t, errors.New(`pq: relation "articles" does not exist`))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, CodeInternal, d.Code)
+	assert.NotContains(t, w.Body.String(), "relation")
+}
+
+func TestErrorListsInvalidFields(t *testing.T) {
+	fields := validation.Errors{{Field: "title", Rule: "required", Message: "is required"}}
+	w, d := serve(t, fmt.Errorf("%w: %w", services.ErrInvalidArticle, fields))
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, "invalid_article", d.Code)
+	assert.Equal(t, fields, d.Errors)
+}
+
+func TestInvalid(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/articles", func(c *gin.Context) {
+		var req struct {
+			Title string `json:"title" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			Invalid(c, http.StatusUnprocessableEntity, err)
+		}
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/articles", strings.NewReader(`{}`)))
+
+	var d Details
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &d))
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, CodeValidationFailed, d.Code)
+	if assert.Len(t, d.Errors, 1) {
+		assert.Equal(t, "required", d.Errors[0].Rule)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/articles", strings.NewReader(`{"title":`)))
+
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &d))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, CodeInvalidRequest, d.Code)
+}
@@ -1,11 +1,10 @@
 package api
 
 import (
-	"net/http"
-
 	"github.com/antonchaban/articles-go/internal/api/middleware"
 	v1 "github.com/antonchaban/articles-go/internal/api/v1"
 	"github.com/antonchaban/articles-go/internal/config"
+	"github.com/antonchaban/articles-go/internal/health"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
 	"github.com/gin-gonic/gin"
@@ -15,17 +14,24 @@ import (
 //
 // The function performs the following setup:
 //   - Configures Gin mode (Debug/Release) based on environment
-//   - Initializes default middleware (Logger and Recovery)
-//   - Registers a health check endpoint at /health
-//   - Sets up API versioning with v1 routes at /api/v1
+//   - Initializes default middleware (tracing, Recovery and metrics)
+//   - Registers liveness (/livez) and readiness (/readyz, /healthz) probes
+//   - Sets up API versioning with v1 routes at /api/v1, requiring a JWT for writes when auth is set
+//     and honoring Idempotency-Key headers on POST requests when idempotency is set
+//   - Registers admin-only routes at /api/v1/admin guarded by the admin token
 //
 // Parameters:
 //   - cfg: Application configuration containing environment settings
+//   - state: Shared server state, flipped to draining on shutdown
+//   - checks: Dependency checks backing the readiness probes
+//   - auth: Bearer token verification for write endpoints, nil disables it
+//   - idempotency: Middleware replaying retried POST requests, nil disables it
 //   - articleHandler: Handler for article-related API endpoints (injected via DI)
+//   - authorHandler: Handler for author-related API endpoints (injected via DI)
 //
 // Returns:
 //   - *gin.Engine: Configured Gin engine ready to serve HTTP requests
-func NewServer(cfg *config.Config, articleHandler *v1.ArticleHandler) *gin.Engine {
+func NewServer(cfg *config.Config, state *State, checks *health.Registry, auth *middleware.JWTAuth, idempotency gin.HandlerFunc, articleHandler *v1.ArticleHandler, authorHandler *v1.AuthorHandler) *gin.Engine {
 	// Set Gin mode based on environment configuration
 	// Production mode disables debug logging for better performance
 	if cfg.AppEnv == "production" {
@@ -34,23 +40,35 @@ func NewServer(cfg *config.Config, articleHandler *v1.ArticleHandler) *gin.Engin
 
 	r := gin.New()
 
+	// Trace requests first, so that spans see the 500 of recovered panics
+	r.Use(middleware.Tracing())
+
 	// Apply additional recovery middleware for redundancy
 	// Ensures graceful handling of panics in request handlers
 	r.Use(gin.Recovery())
 	r.Use(middleware.PrometheusMiddleware())
 
-	// Register health check endpoint
-	// Used for liveness probes
-	r.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"status": "alive"})
-	})
+	// Register liveness and readiness probes
+	registerProbes(r, state, checks)
 
 	// Expose metrics for Prometheus scraper
 	r.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
 	apiV1 := r.Group("/api/v1")
 	{
-		v1.RegisterRoutes(apiV1, articleHandler)
+		articles := apiV1.Group("")
+		if auth != nil {
+			articles.Use(auth.RequireWrite())
+		}
+		// after auth, keys are scoped to the subject
+		if idempotency != nil {
+			articles.Use(idempotency)
+		}
+		v1.RegisterRoutes(articles, articleHandler, cfg.SearchLanguage)
+		v1.RegisterAuthorRoutes(articles, authorHandler, articleHandler)
+
+		admin := apiV1.Group("/admin", middleware.AdminToken(cfg.AdminToken))
+		v1.RegisterAdminRoutes(admin, articleHandler, cfg.SoftDeleteRetention)
 	}
 
 	return r
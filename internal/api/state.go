@@ -0,0 +1,22 @@
+package api
+
+import (
+	"sync/atomic"
+)
+
+// State tracks whether the server should still receive new traffic.
+// It is flipped to draining at the start of a graceful shutdown so that
+// readiness probes fail before the listener is closed.
+type State struct {
+	draining atomic.Bool
+}
+
+// StartDraining marks the server as shutting down.
+func (s *State) StartDraining() {
+	s.draining.Store(true)
+}
+
+// Draining reports whether a shutdown is in progress.
+func (s *State) Draining() bool {
+	return s.draining.Load()
+}
@@ -0,0 +1,139 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/antonchaban/articles-go/internal/api/problem"
+	"github.com/antonchaban/articles-go/internal/dto"
+	"github.com/antonchaban/articles-go/internal/log"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// AuthorService defines the business logic operations for authors.
+type AuthorService interface {
+	// Create registers a new author.
+	Create(ctx context.Context, req dto.CreateAuthorRequest) (*dto.AuthorResponse, error)
+	// GetByID retrieves an author by its unique identifier.
+	GetByID(ctx context.Context, id uint) (*dto.AuthorResponse, error)
+	// List returns a paginated page of authors.
+	List(ctx context.Context, req dto.ListAuthorsRequest) (*dto.ListAuthorsResponse, error)
+	// Update replaces the profile of an author.
+	Update(ctx context.Context, id uint, req dto.UpdateAuthorRequest) (*dto.AuthorResponse, error)
+	// Delete removes an author without articles.
+	Delete(ctx context.Context, id uint) error
+}
+
+type AuthorHandler struct {
+	service AuthorService
+	log     *zap.Logger
+}
+
+func NewAuthorHandler(s AuthorService, logger *zap.Logger) *AuthorHandler {
+	return &AuthorHandler{
+		service: s,
+		log:     logger.With(zap.String("layer", "handler")),
+	}
+}
+
+// Create handles POST requests to register a new author.
+// Returns 201 Created on success, 400 Bad Request for malformed JSON,
+// 422 Unprocessable Entity listing the fields that fail validation,
+// 403 Forbidden when registering someone else without admin rights,
+// or 409 Conflict if the email or subject is already taken.
+func (h *AuthorHandler) Create(c *gin.Context) {
+	var req dto.CreateAuthorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.WithTrace(c.Request.Context(), h.log).Warn("invalid json request", zap.Error(err))
+		problem.Invalid(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	resp, err := h.service.Create(c.Request.Context(), req)
+	if err != nil {
+		fail(c, h.log, "author operation failed", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// Get handles GET requests to retrieve an author by ID.
+// Returns 200 OK, 400 Bad Request for an invalid ID or 404 Not Found.
+func (h *AuthorHandler) Get(c *gin.Context) {
+	id, ok := parseID(c, h.log)
+	if !ok {
+		return
+	}
+
+	resp, err := h.service.GetByID(c.Request.Context(), id)
+	if err != nil {
+		fail(c, h.log, "author operation failed", err, zap.Uint("id", id))
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// List handles GET requests to enumerate authors.
+// Supported query parameters: limit and offset.
+func (h *AuthorHandler) List(c *gin.Context) {
+	var req dto.ListAuthorsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.WithTrace(c.Request.Context(), h.log).Warn("invalid list query", zap.Error(err))
+		problem.Invalid(c, http.StatusBadRequest, err)
+		return
+	}
+
+	resp, err := h.service.List(c.Request.Context(), req)
+	if err != nil {
+		fail(c, h.log, "failed to list authors", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Update handles PUT requests to replace an author profile.
+// Returns 200 OK, 400 Bad Request, 403 Forbidden for someone else's profile,
+// 404 Not Found, 409 Conflict if the new email is taken or
+// 422 Unprocessable Entity listing the fields that fail validation.
+func (h *AuthorHandler) Update(c *gin.Context) {
+	id, ok := parseID(c, h.log)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateAuthorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.WithTrace(c.Request.Context(), h.log).Warn("invalid json request", zap.Error(err))
+		problem.Invalid(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	resp, err := h.service.Update(c.Request.Context(), id, req)
+	if err != nil {
+		fail(c, h.log, "author operation failed", err, zap.Uint("id", id))
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Delete handles DELETE requests to remove an author.
+// Returns 204 No Content, 403 Forbidden, 404 Not Found,
+// or 409 Conflict while the author still owns articles.
+func (h *AuthorHandler) Delete(c *gin.Context) {
+	id, ok := parseID(c, h.log)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), id); err != nil {
+		fail(c, h.log, "author operation failed", err, zap.Uint("id", id))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,146 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/antonchaban/articles-go/internal/dto"
+	"github.com/antonchaban/articles-go/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type MockAuthorService struct {
+	mock.Mock
+}
+
+func (m *MockAuthorService) Create(ctx context.Context, req dto.CreateAuthorRequest) (*dto.AuthorResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.AuthorResponse), args.Error(1)
+}
+
+func (m *MockAuthorService) GetByID(ctx context.Context, id uint) (*dto.AuthorResponse, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.AuthorResponse), args.Error(1)
+}
+
+func (m *MockAuthorService) List(ctx context.Context, req dto.ListAuthorsRequest) (*dto.ListAuthorsResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.ListAuthorsResponse), args.Error(1)
+}
+
+func (m *MockAuthorService) Update(ctx context.Context, id uint, req dto.UpdateAuthorRequest) (*dto.AuthorResponse, error) {
+	args := m.Called(ctx, id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.AuthorResponse), args.Error(1)
+}
+
+func (m *MockAuthorService) Delete(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func TestCreateAuthorHandler(t *testing.T) {
+	mockService := new(MockAuthorService)
+	handler := NewAuthorHandler(mockService, zap.NewNop())
+
+	router := setupTestRouter()
+	router.POST("/authors", handler.Create)
+
+	req := dto.CreateAuthorRequest{Name: "Jane", Email: "jane@example.com"}
+	mockService.On("Create", mock.Anything, req).Return(&dto.AuthorResponse{ID: 7, Name: "Jane"}, nil)
+
+	body, _ := json.Marshal(req)
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest(http.MethodPost, "/authors", bytes.NewBuffer(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, httpReq)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestCreateAuthorHandlerWithInvalidEmail(t *testing.T) {
+	mockService := new(MockAuthorService)
+	handler := NewAuthorHandler(mockService, zap.NewNop())
+
+	router := setupTestRouter()
+	router.POST("/authors", handler.Create)
+
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest(http.MethodPost, "/authors", bytes.NewBufferString(`{"name":"Jane","email":"nope"}`))
+	httpReq.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, httpReq)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	mockService.AssertNotCalled(t, "Create")
+}
+
+func TestAuthorHandlerMapsErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"not found", services.ErrAuthorNotFound, http.StatusNotFound},
+		{"forbidden", services.ErrForbidden, http.StatusForbidden},
+		{"has articles", services.ErrAuthorHasArticles, http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockAuthorService)
+			handler := NewAuthorHandler(mockService, zap.NewNop())
+
+			router := setupTestRouter()
+			router.DELETE("/authors/:id", handler.Delete)
+
+			mockService.On("Delete", mock.Anything, uint(7)).Return(tt.err)
+
+			w := httptest.NewRecorder()
+			httpReq, _ := http.NewRequest(http.MethodDelete, "/authors/7", nil)
+			router.ServeHTTP(w, httpReq)
+
+			assert.Equal(t, tt.code, w.Code)
+		})
+	}
+}
+
+func TestListArticlesByAuthorHandler(t *testing.T) {
+	mockService := new(MockArticleService)
+	handler := NewArticleHandler(mockService, zap.NewNop())
+
+	router := setupTestRouter()
+	router.GET("/authors/:id/articles", handler.ListByAuthor)
+
+	mockService.On("ListByAuthor", mock.Anything, uint(7), dto.ListArticlesRequest{Limit: 5}).
+		Return(&dto.ListArticlesResponse{Items: []dto.ArticleResponse{{ID: 1}}}, nil)
+	mockService.On("ListByAuthor", mock.Anything, uint(999), mock.Anything).
+		Return(nil, services.ErrAuthorNotFound)
+
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest(http.MethodGet, "/authors/7/articles?limit=5", nil)
+	router.ServeHTTP(w, httpReq)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	httpReq, _ = http.NewRequest(http.MethodGet, "/authors/999/articles", nil)
+	router.ServeHTTP(w, httpReq)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
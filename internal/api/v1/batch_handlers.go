@@ -0,0 +1,72 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/antonchaban/articles-go/internal/api/problem"
+	"github.com/antonchaban/articles-go/internal/dto"
+	"github.com/antonchaban/articles-go/internal/log"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// batchItem reports the outcome of one operation of a batch with the status
+// the matching single article endpoint would have responded with.
+type batchItem struct {
+	dto.BatchResult
+	Status int              `json:"status"`
+	Error  *problem.Details `json:"error,omitempty"`
+}
+
+// successStatuses are the statuses of operations that went through.
+var successStatuses = map[string]int{
+	dto.BatchCreate: http.StatusCreated,
+	dto.BatchUpdate: http.StatusOK,
+	dto.BatchDelete: http.StatusNoContent,
+}
+
+// Batch handles POST requests applying several article operations at once.
+// It expects a JSON body conforming to dto.BatchRequest; operations carry
+// their version instead of an If-Match header.
+// Returns 207 Multi-Status with an item per operation, in order, 400 Bad
+// Request for malformed JSON or 422 Unprocessable Entity listing the
+// operations that fail validation.
+func (h *ArticleHandler) Batch(c *gin.Context) {
+	var req dto.BatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.WithTrace(c.Request.Context(), h.log).Warn("invalid batch request", zap.Error(err))
+		problem.Invalid(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	resp, err := h.service.Batch(c.Request.Context(), req)
+	if err != nil {
+		fail(c, h.log, "failed to run batch", err)
+		return
+	}
+
+	items := make([]batchItem, 0, len(resp.Items))
+	for _, res := range resp.Items {
+		it := batchItem{BatchResult: res, Status: successStatuses[res.Op]}
+		if res.Err != nil {
+			d := problem.Of(res.Err)
+			it.Status, it.Error = d.Status, &d
+		}
+		items = append(items, it)
+	}
+
+	c.JSON(http.StatusMultiStatus, gin.H{"items": items})
+}
+
+// customMethods serves custom methods on the article collection, like
+// POST /articles:batch. Gin can't match a literal colon, so they are routed
+// through a parameter holding ":" and the method name.
+func customMethods(methods map[string]gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if h, ok := methods[c.Param("method")]; ok {
+			h(c)
+			return
+		}
+		problem.Write(c, http.StatusNotFound, problem.CodeNotFound, "no such method")
+	}
+}
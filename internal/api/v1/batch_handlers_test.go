@@ -0,0 +1,87 @@
+package v1
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/antonchaban/articles-go/internal/dto"
+	"github.com/antonchaban/articles-go/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestBatchHandlerReportsEveryItem(t *testing.T) {
+	mockService := new(MockArticleService)
+	handler := NewArticleHandler(mockService, zap.NewNop())
+
+	router := setupTestRouter()
+	RegisterRoutes(router.Group("/api/v1"), handler, "english")
+
+	mockService.On("Batch", mock.Anything, mock.MatchedBy(func(req dto.BatchRequest) bool {
+		return req.Atomic && len(req.Operations) == 3
+	})).Return(&dto.BatchResponse{Items: []dto.BatchResult{
+		{Index: 0, Op: dto.BatchCreate, ID: 7, Slug: "new", Version: 1},
+		{Index: 1, Op: dto.BatchUpdate, ID: 3, Err: services.ErrVersionConflict},
+		{Index: 2, Op: dto.BatchDelete, ID: 4},
+	}}, nil)
+
+	body := `{"atomic":true,"operations":[
+		{"op":"create","article":{"title":"New"}},
+		{"op":"update","id":3,"version":1,"article":{"title":"Renamed"}},
+		{"op":"delete","id":4,"version":2}
+	]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/articles:batch", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusMultiStatus, w.Code)
+	assert.JSONEq(t, `{"items":[
+		{"index":0,"op":"create","id":7,"slug":"new","version":1,"status":201},
+		{"index":1,"op":"update","id":3,"status":412,"error":{
+			"type":"about:blank","title":"Precondition Failed","status":412,
+			"detail":"article version conflict","code":"version_conflict"}},
+		{"index":2,"op":"delete","id":4,"status":204}
+	]}`, w.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+func TestBatchHandlerValidatesOperations(t *testing.T) {
+	mockService := new(MockArticleService)
+	handler := NewArticleHandler(mockService, zap.NewNop())
+
+	router := setupTestRouter()
+	RegisterRoutes(router.Group("/api/v1"), handler, "english")
+
+	body := `{"operations":[{"op":"update","article":{"title":"x"}},{"op":"merge"}]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/articles:batch", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), `"field":"operations[0].id"`)
+	assert.Contains(t, w.Body.String(), `"field":"operations[1].op"`)
+	mockService.AssertNotCalled(t, "Batch", mock.Anything, mock.Anything)
+}
+
+func TestCustomMethodsRejectUnknownMethods(t *testing.T) {
+	mockService := new(MockArticleService)
+	handler := NewArticleHandler(mockService, zap.NewNop())
+
+	router := setupTestRouter()
+	RegisterRoutes(router.Group("/api/v1"), handler, "english")
+
+	for _, path := range []string{"/api/v1/articles:merge", "/api/v1/articlesbatch"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString(`{}`)))
+
+		assert.Equal(t, http.StatusNotFound, w.Code, path)
+	}
+	mockService.AssertNotCalled(t, "Batch", mock.Anything, mock.Anything)
+}
@@ -4,9 +4,15 @@ package v1
 import (
 	"context"
 	"net/http"
+	"net/url"
+	"path"
 	"strconv"
+	"time"
 
+	"github.com/antonchaban/articles-go/internal/api/problem"
 	"github.com/antonchaban/articles-go/internal/dto"
+	"github.com/antonchaban/articles-go/internal/entities"
+	"github.com/antonchaban/articles-go/internal/log"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
@@ -16,9 +22,51 @@ type ArticleService interface {
 	// Create creates a new article and returns the created article details.
 	Create(ctx context.Context, req dto.CreateArticleRequest) (*dto.CreateArticleResponse, error)
 	// GetByID retrieves an article by its unique identifier.
-	GetByID(ctx context.Context, id uint) (*dto.ArticleResponse, error)
+	// Soft-deleted articles are only returned when includeDeleted is set,
+	// and only to their author and admins.
+	GetByID(ctx context.Context, id uint, includeDeleted bool) (*dto.ArticleResponse, error)
+	// GetBySlug retrieves an article by its current or a former slug.
+	GetBySlug(ctx context.Context, slug string) (*dto.ArticleResponse, error)
+	// List returns a filtered, paginated page of articles.
+	List(ctx context.Context, req dto.ListArticlesRequest) (*dto.ListArticlesResponse, error)
+	// Update replaces all mutable fields of an existing article.
+	// Mutations only succeed while the article is still at the given version.
+	Update(ctx context.Context, id uint, version uint, req dto.UpdateArticleRequest) (*dto.ArticleResponse, error)
+	// Patch applies a merge patch or JSON patch document to an existing article.
+	Patch(ctx context.Context, id uint, version uint, contentType string, patch []byte) (*dto.ArticleResponse, error)
+	// Delete soft-deletes an article.
+	Delete(ctx context.Context, id uint, version uint) error
+	// ListByAuthor returns a filtered, paginated page of articles owned by an author.
+	ListByAuthor(ctx context.Context, authorID uint, req dto.ListArticlesRequest) (*dto.ListArticlesResponse, error)
+	// ListTags returns all tags with their usage counts.
+	ListTags(ctx context.Context) (*dto.ListTagsResponse, error)
+	// Search runs a full-text query, using defaultLanguage unless the request names one.
+	Search(ctx context.Context, req dto.SearchArticlesRequest, defaultLanguage string) (*dto.SearchArticlesResponse, error)
+	// Restore undoes a soft delete.
+	Restore(ctx context.Context, id uint, version uint) (*dto.ArticleResponse, error)
+	// Transition moves an article to another publication status.
+	Transition(ctx context.Context, id uint, version uint, to entities.ArticleStatus) (*dto.ArticleResponse, error)
+	// Schedule queues an article under review to be published at publishAt.
+	Schedule(ctx context.Context, id uint, version uint, publishAt time.Time) (*dto.ArticleResponse, error)
+	// Unschedule cancels a scheduled publication.
+	Unschedule(ctx context.Context, id uint, version uint) (*dto.ArticleResponse, error)
+	// ListRevisions returns a page of the revisions of an article, newest first.
+	ListRevisions(ctx context.Context, id uint, req dto.ListRevisionsRequest) (*dto.ListRevisionsResponse, error)
+	// GetRevision returns an article as of the given version.
+	GetRevision(ctx context.Context, id uint, version uint) (*dto.RevisionResponse, error)
+	// DiffRevisions returns a unified diff between two revisions of an article.
+	DiffRevisions(ctx context.Context, id uint, from, to uint) (*dto.RevisionDiffResponse, error)
+	// RestoreRevision brings the content of an article back to the given revision.
+	RestoreRevision(ctx context.Context, id uint, revision uint, version uint) (*dto.ArticleResponse, error)
+	// Batch applies several create, update and delete operations at once.
+	Batch(ctx context.Context, req dto.BatchRequest) (*dto.BatchResponse, error)
+	// Purge permanently removes articles soft-deleted longer than retention ago.
+	Purge(ctx context.Context, retention time.Duration) (*dto.PurgeArticlesResponse, error)
 }
 
+// mimeMarkdown is the media type of raw article bodies (RFC 7763).
+const mimeMarkdown = "text/markdown"
+
 type ArticleHandler struct {
 	service ArticleService
 	log     *zap.Logger
@@ -33,54 +81,476 @@ func NewArticleHandler(s ArticleService, logger *zap.Logger) *ArticleHandler {
 
 // Create handles POST requests to create a new article.
 // It expects a JSON body conforming to dto.CreateArticleRequest.
-// Returns 201 Created on success, 400 Bad Request for invalid input,
+// Returns 201 Created on success, 400 Bad Request for malformed JSON,
+// 403 Forbidden if the caller may not create articles for the requested author,
+// 422 Unprocessable Entity listing the fields that break their rules or
+// if the author does not exist,
 // or 500 Internal Server Error if article creation fails.
 func (h *ArticleHandler) Create(c *gin.Context) {
 	var req dto.CreateArticleRequest
 
 	// Bind and validate JSON request body
 	if err := c.ShouldBindJSON(&req); err != nil {
-		h.log.Warn("invalid json request", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		log.WithTrace(c.Request.Context(), h.log).Warn("invalid json request", zap.Error(err))
+		problem.Invalid(c, http.StatusUnprocessableEntity, err)
 		return
 	}
 
 	// call service layer to create the article
 	resp, err := h.service.Create(c.Request.Context(), req)
 	if err != nil {
-		h.log.Error("failed to create article", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		fail(c, h.log, "failed to create article", err)
 		return
 	}
 
+	setETag(c, resp.Version)
 	c.JSON(http.StatusCreated, resp)
 }
 
 // Get handles GET requests to retrieve an article by ID.
 // The article ID should be provided as a URL parameter.
+// Soft-deleted articles are only returned with ?include_deleted=true, and
+// only to their author and admins.
+// The body representation is chosen by ?format=raw|html|both (default both).
+// Without a format parameter, an Accept header preferring text/markdown or
+// text/html returns the bare Markdown source or rendered HTML instead of JSON.
+// The response carries an ETag; a matching If-None-Match yields 304 Not Modified.
 // Returns 200 OK with article data on success, 400 Bad Request for invalid ID format,
 // or 404 Not Found if the article doesn't exist.
 func (h *ArticleHandler) Get(c *gin.Context) {
-	// Extract article ID from URL parameter
+	idUint, ok := parseID(c, h.log)
+	if !ok {
+		return
+	}
+
+	includeDeleted, err := strconv.ParseBool(c.DefaultQuery("include_deleted", "false"))
+	if err != nil {
+		problem.Write(c, http.StatusBadRequest, problem.CodeInvalidRequest, "include_deleted must be a boolean")
+		return
+	}
+
+	format, ok := parseFormat(c)
+	if !ok {
+		return
+	}
+
+	// Fetch article from service layer
+	resp, err := h.service.GetByID(c.Request.Context(), idUint, includeDeleted)
+	if err != nil {
+		fail(c, h.log, "failed to fetch article", err, zap.Uint("id", idUint))
+		return
+	}
+
+	writeArticle(c, resp, format)
+}
+
+// GetBySlug handles GET requests to retrieve an article by its slug.
+// A former slug of an article is answered with 301 Moved Permanently
+// pointing at the current one. Formats, content negotiation and ETags
+// work as for Get.
+// Returns 200 OK, 301 Moved Permanently, 400 Bad Request for an invalid
+// format, or 404 Not Found.
+func (h *ArticleHandler) GetBySlug(c *gin.Context) {
+	name := c.Param("slug")
+
+	format, ok := parseFormat(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.service.GetBySlug(c.Request.Context(), name)
+	if err != nil {
+		fail(c, h.log, "failed to fetch article", err, zap.String("slug", name))
+		return
+	}
+
+	if resp.Slug != name {
+		location := path.Join(path.Dir(c.Request.URL.Path), url.PathEscape(resp.Slug))
+		if c.Request.URL.RawQuery != "" {
+			location += "?" + c.Request.URL.RawQuery
+		}
+		c.Redirect(http.StatusMovedPermanently, location)
+		return
+	}
+
+	writeArticle(c, resp, format)
+}
+
+// parseFormat extracts the format query parameter, defaulting to both.
+// It writes a 400 Bad Request response and returns false on failure.
+func parseFormat(c *gin.Context) (dto.ArticleFormat, bool) {
+	format := dto.ArticleFormat(c.DefaultQuery("format", string(dto.FormatBoth)))
+	switch format {
+	case dto.FormatRaw, dto.FormatHTML, dto.FormatBoth:
+		return format, true
+	default:
+		problem.Write(c, http.StatusBadRequest, problem.CodeInvalidRequest, "format must be one of raw, html, both")
+		return "", false
+	}
+}
+
+// writeArticle writes resp in the requested format, or negotiated from the
+// Accept header when no format was given, honoring If-None-Match.
+func writeArticle(c *gin.Context, resp *dto.ArticleResponse, format dto.ArticleFormat) {
+	tag := etag(resp.Version)
+	c.Header("ETag", tag)
+	c.Header("Vary", "Accept")
+	if notModified(c, tag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	if c.Query("format") == "" {
+		switch c.NegotiateFormat(gin.MIMEJSON, mimeMarkdown, gin.MIMEHTML) {
+		case mimeMarkdown:
+			c.Data(http.StatusOK, mimeMarkdown+"; charset=utf-8", []byte(resp.Body))
+			return
+		case gin.MIMEHTML:
+			c.Data(http.StatusOK, gin.MIMEHTML+"; charset=utf-8", []byte(resp.BodyHTML))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, resp.WithFormat(format))
+}
+
+// Update handles PUT requests to fully replace an article.
+// It expects a JSON body conforming to dto.UpdateArticleRequest and an
+// If-Match header carrying the ETag the change is based on.
+// Returns 200 OK with the updated article, 400 Bad Request for malformed JSON,
+// 404 Not Found if the article doesn't exist, 412 Precondition Failed if the
+// article changed in the meantime, 428 Precondition Required without If-Match,
+// or 422 Unprocessable Entity listing the fields that fail validation.
+func (h *ArticleHandler) Update(c *gin.Context) {
+	id, ok := parseID(c, h.log)
+	if !ok {
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateArticleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.WithTrace(c.Request.Context(), h.log).Warn("invalid json request", zap.Error(err))
+		problem.Invalid(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	resp, err := h.service.Update(c.Request.Context(), id, version, req)
+	if err != nil {
+		fail(c, h.log, "failed to modify article", err, zap.Uint("id", id))
+		return
+	}
+
+	setETag(c, resp.Version)
+	c.JSON(http.StatusOK, resp)
+}
+
+// Patch handles PATCH requests to partially update an article.
+// The body is interpreted according to its Content-Type:
+// application/merge-patch+json (or application/json) for RFC 7396 and
+// application/json-patch+json for RFC 6902. If-Match is required as for Update.
+// Returns 200 OK with the updated article, 400 Bad Request for a malformed patch,
+// 404 Not Found, 412/428 for precondition failures, 415 Unsupported Media Type,
+// or 422 Unprocessable Entity if the patch cannot be applied or the result
+// fails validation.
+func (h *ArticleHandler) Patch(c *gin.Context) {
+	id, ok := parseID(c, h.log)
+	if !ok {
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	contentType := c.ContentType()
+	switch contentType {
+	case dto.MergePatchContentType, dto.JSONPatchContentType, "application/json":
+	default:
+		log.WithTrace(c.Request.Context(), h.log).Warn("unsupported patch content type", zap.String("content_type", contentType))
+		problem.Write(c, http.StatusUnsupportedMediaType, problem.CodeUnsupportedMediaType, "unsupported patch content type")
+		return
+	}
+
+	body, err := c.GetRawData()
+	if err != nil {
+		problem.Write(c, http.StatusBadRequest, problem.CodeInvalidRequest, "failed to read request body")
+		return
+	}
+
+	resp, err := h.service.Patch(c.Request.Context(), id, version, contentType, body)
+	if err != nil {
+		fail(c, h.log, "failed to modify article", err, zap.Uint("id", id))
+		return
+	}
+
+	setETag(c, resp.Version)
+	c.JSON(http.StatusOK, resp)
+}
+
+// Delete handles DELETE requests to soft-delete an article.
+// If-Match is required as for Update.
+// Returns 204 No Content on success, 404 Not Found if the article
+// doesn't exist or is already deleted, or 412/428 for precondition failures.
+func (h *ArticleHandler) Delete(c *gin.Context) {
+	id, ok := parseID(c, h.log)
+	if !ok {
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), id, version); err != nil {
+		fail(c, h.log, "failed to modify article", err, zap.Uint("id", id))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Restore handles POST requests to undo a soft delete.
+// If-Match is required as for Update.
+// Returns 200 OK with the restored article, 404 Not Found if there is
+// no deleted article with the given ID, or 412/428 for precondition failures.
+func (h *ArticleHandler) Restore(c *gin.Context) {
+	id, ok := parseID(c, h.log)
+	if !ok {
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.service.Restore(c.Request.Context(), id, version)
+	if err != nil {
+		fail(c, h.log, "failed to modify article", err, zap.Uint("id", id))
+		return
+	}
+
+	setETag(c, resp.Version)
+	c.JSON(http.StatusOK, resp)
+}
+
+// Transition returns a handler that moves an article to the given publication
+// status. If-Match is required as for Update.
+// Returns 200 OK with the article, 403 Forbidden if the caller may not make
+// the move, 404 Not Found, 409 Conflict if the workflow does not allow it
+// from the current status, or 412/428 for precondition failures.
+func (h *ArticleHandler) Transition(to entities.ArticleStatus) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseID(c, h.log)
+		if !ok {
+			return
+		}
+
+		version, ok := ifMatchVersion(c)
+		if !ok {
+			return
+		}
+
+		resp, err := h.service.Transition(c.Request.Context(), id, version, to)
+		if err != nil {
+			fail(c, h.log, "failed to modify article", err, zap.Uint("id", id))
+			return
+		}
+
+		setETag(c, resp.Version)
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// Schedule handles PUT requests queueing an article under review for
+// publication at the publish_at of the body. If-Match is required as for Update.
+// Returns 200 OK with the article, 400 Bad Request for malformed JSON,
+// 403 Forbidden for non-reviewers, 404 Not Found, 409 Conflict unless the
+// article is in review, 412/428 for precondition failures, or
+// 422 Unprocessable Entity if publish_at is missing or not in the future.
+func (h *ArticleHandler) Schedule(c *gin.Context) {
+	id, ok := parseID(c, h.log)
+	if !ok {
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	var req dto.ScheduleArticleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.WithTrace(c.Request.Context(), h.log).Warn("invalid json request", zap.Error(err))
+		problem.Invalid(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	resp, err := h.service.Schedule(c.Request.Context(), id, version, req.PublishAt)
+	if err != nil {
+		fail(c, h.log, "failed to modify article", err, zap.Uint("id", id))
+		return
+	}
+
+	setETag(c, resp.Version)
+	c.JSON(http.StatusOK, resp)
+}
+
+// Unschedule handles DELETE requests cancelling a scheduled publication.
+// If-Match is required as for Update.
+// Returns 200 OK with the article, 403 Forbidden for non-reviewers,
+// 404 Not Found, 409 Conflict if the article is not scheduled, or 412/428
+// for precondition failures.
+func (h *ArticleHandler) Unschedule(c *gin.Context) {
+	id, ok := parseID(c, h.log)
+	if !ok {
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.service.Unschedule(c.Request.Context(), id, version)
+	if err != nil {
+		fail(c, h.log, "failed to modify article", err, zap.Uint("id", id))
+		return
+	}
+
+	setETag(c, resp.Version)
+	c.JSON(http.StatusOK, resp)
+}
+
+// Purge returns a handler that permanently removes articles soft-deleted
+// longer than retention ago. Returns 200 OK with the number of purged articles.
+func (h *ArticleHandler) Purge(retention time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		resp, err := h.service.Purge(c.Request.Context(), retention)
+		if err != nil {
+			fail(c, h.log, "failed to purge articles", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// parseID extracts the ID URL parameter and validates it's a positive number.
+// It writes a 400 Bad Request response and returns false on failure.
+func parseID(c *gin.Context, l *zap.Logger) (uint, bool) {
 	idStr := c.Param("id")
 
-	// Convert ID to integer and validate it's a positive number
 	idInt, err := strconv.Atoi(idStr)
 	if err != nil || idInt < 0 {
-		h.log.Warn("invalid article id format", zap.String("id_param", idStr))
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID format; must be a positive integer"})
+		log.WithTrace(c.Request.Context(), l).Warn("invalid id format", zap.String("id_param", idStr))
+		problem.Write(c, http.StatusBadRequest, problem.CodeInvalidRequest, "invalid ID format; must be a positive integer")
+		return 0, false
+	}
+
+	return uint(idInt), true
+}
+
+// fail responds with the problem describing err. Errors that are not the
+// caller's fault are logged with msg and fields.
+func fail(c *gin.Context, l *zap.Logger, msg string, err error, fields ...zap.Field) {
+	if problem.Status(err) >= http.StatusInternalServerError {
+		log.WithTrace(c.Request.Context(), l).Error(msg, append(fields, zap.Error(err))...)
+	}
+	problem.Error(c, err)
+}
+
+// List handles GET requests to enumerate articles.
+// Supported query parameters: limit, offset, cursor, title, author_id, created_from,
+// created_to (RFC 3339), sort (created_at, -created_at, id, -id), tag (repeated
+// or comma separated), tag_match (any, all), category and status (published
+// by default).
+// Returns 200 OK with items and pagination metadata, 400 Bad Request for
+// invalid parameters, or 500 Internal Server Error if the lookup fails.
+func (h *ArticleHandler) List(c *gin.Context) {
+	var req dto.ListArticlesRequest
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.WithTrace(c.Request.Context(), h.log).Warn("invalid list query", zap.Error(err))
+		problem.Invalid(c, http.StatusBadRequest, err)
 		return
 	}
 
-	idUint := uint(idInt)
+	resp, err := h.service.List(c.Request.Context(), req)
+	if err != nil {
+		fail(c, h.log, "failed to list articles", err)
+		return
+	}
 
-	// Fetch article from service layer
-	resp, err := h.service.GetByID(c.Request.Context(), idUint)
+	c.JSON(http.StatusOK, resp)
+}
+
+// ListByAuthor handles GET requests to enumerate the articles of an author.
+// It accepts the same query parameters as List.
+// Returns 200 OK, 400 Bad Request for invalid parameters,
+// or 404 Not Found if the author doesn't exist.
+func (h *ArticleHandler) ListByAuthor(c *gin.Context) {
+	authorID, ok := parseID(c, h.log)
+	if !ok {
+		return
+	}
+
+	var req dto.ListArticlesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.WithTrace(c.Request.Context(), h.log).Warn("invalid list query", zap.Error(err))
+		problem.Invalid(c, http.StatusBadRequest, err)
+		return
+	}
+
+	resp, err := h.service.ListByAuthor(c.Request.Context(), authorID, req)
 	if err != nil {
-		h.log.Error("failed to fetch article", zap.Uint("id", idUint), zap.Error(err))
-		c.JSON(http.StatusNotFound, gin.H{"error": "article not found"})
+		fail(c, h.log, "failed to list articles of author", err, zap.Uint("author_id", authorID))
 		return
 	}
 
 	c.JSON(http.StatusOK, resp)
 }
+
+// ListTags handles GET requests to enumerate tags with the number of
+// articles using them, most used first.
+// Returns 200 OK or 500 Internal Server Error if the lookup fails.
+func (h *ArticleHandler) ListTags(c *gin.Context) {
+	resp, err := h.service.ListTags(c.Request.Context())
+	if err != nil {
+		fail(c, h.log, "failed to list tags", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Search returns a handler for full-text queries over articles.
+// Supported query parameters: q (required, web search syntax), lang, limit and offset.
+// Queries without lang are parsed with defaultLanguage.
+// Returns 200 OK with ranked items and highlighted headlines,
+// or 400 Bad Request for a missing query or an unsupported language.
+func (h *ArticleHandler) Search(defaultLanguage string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req dto.SearchArticlesRequest
+		if err := c.ShouldBindQuery(&req); err != nil {
+			log.WithTrace(c.Request.Context(), h.log).Warn("invalid search query", zap.Error(err))
+			problem.Invalid(c, http.StatusBadRequest, err)
+			return
+		}
+
+		resp, err := h.service.Search(c.Request.Context(), req, defaultLanguage)
+		if err != nil {
+			fail(c, h.log, "failed to search articles", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, resp)
+	}
+}
@@ -7,15 +7,19 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/antonchaban/articles-go/internal/auth"
 	"github.com/antonchaban/articles-go/internal/dto"
+	"github.com/antonchaban/articles-go/internal/entities"
+	"github.com/antonchaban/articles-go/internal/repository"
+	"github.com/antonchaban/articles-go/internal/services"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"go.uber.org/zap"
-	"gorm.io/gorm"
 )
 
 type MockArticleService struct {
@@ -30,14 +34,155 @@ func (m *MockArticleService) Create(ctx context.Context, req dto.CreateArticleRe
 	return args.Get(0).(*dto.CreateArticleResponse), args.Error(1)
 }
 
-func (m *MockArticleService) GetByID(ctx context.Context, id uint) (*dto.ArticleResponse, error) {
-	args := m.Called(ctx, id)
+func (m *MockArticleService) GetByID(ctx context.Context, id uint, includeDeleted bool) (*dto.ArticleResponse, error) {
+	args := m.Called(ctx, id, includeDeleted)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*dto.ArticleResponse), args.Error(1)
 }
 
+func (m *MockArticleService) List(ctx context.Context, req dto.ListArticlesRequest) (*dto.ListArticlesResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.ListArticlesResponse), args.Error(1)
+}
+
+func (m *MockArticleService) Update(ctx context.Context, id uint, version uint, req dto.UpdateArticleRequest) (*dto.ArticleResponse, error) {
+	args := m.Called(ctx, id, version, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.ArticleResponse), args.Error(1)
+}
+
+func (m *MockArticleService) Patch(ctx context.Context, id uint, version uint, contentType string, patch []byte) (*dto.ArticleResponse, error) {
+	args := m.Called(ctx, id, version, contentType, patch)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.ArticleResponse), args.Error(1)
+}
+
+func (m *MockArticleService) Delete(ctx context.Context, id uint, version uint) error {
+	args := m.Called(ctx, id, version)
+	return args.Error(0)
+}
+
+func (m *MockArticleService) ListByAuthor(ctx context.Context, authorID uint, req dto.ListArticlesRequest) (*dto.ListArticlesResponse, error) {
+	args := m.Called(ctx, authorID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.ListArticlesResponse), args.Error(1)
+}
+
+func (m *MockArticleService) ListTags(ctx context.Context) (*dto.ListTagsResponse, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.ListTagsResponse), args.Error(1)
+}
+
+func (m *MockArticleService) Search(ctx context.Context, req dto.SearchArticlesRequest, defaultLanguage string) (*dto.SearchArticlesResponse, error) {
+	args := m.Called(ctx, req, defaultLanguage)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.SearchArticlesResponse), args.Error(1)
+}
+
+func (m *MockArticleService) Transition(ctx context.Context, id uint, version uint, to entities.ArticleStatus) (*dto.ArticleResponse, error) {
+	args := m.Called(ctx, id, version, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.ArticleResponse), args.Error(1)
+}
+
+func (m *MockArticleService) Schedule(ctx context.Context, id uint, version uint, publishAt time.Time) (*dto.ArticleResponse, error) {
+	args := m.Called(ctx, id, version, publishAt)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.ArticleResponse), args.Error(1)
+}
+
+func (m *MockArticleService) Unschedule(ctx context.Context, id uint, version uint) (*dto.ArticleResponse, error) {
+	args := m.Called(ctx, id, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.ArticleResponse), args.Error(1)
+}
+
+func (m *MockArticleService) GetBySlug(ctx context.Context, slug string) (*dto.ArticleResponse, error) {
+	args := m.Called(ctx, slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.ArticleResponse), args.Error(1)
+}
+
+func (m *MockArticleService) ListRevisions(ctx context.Context, id uint, req dto.ListRevisionsRequest) (*dto.ListRevisionsResponse, error) {
+	args := m.Called(ctx, id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.ListRevisionsResponse), args.Error(1)
+}
+
+func (m *MockArticleService) GetRevision(ctx context.Context, id uint, version uint) (*dto.RevisionResponse, error) {
+	args := m.Called(ctx, id, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.RevisionResponse), args.Error(1)
+}
+
+func (m *MockArticleService) DiffRevisions(ctx context.Context, id uint, from, to uint) (*dto.RevisionDiffResponse, error) {
+	args := m.Called(ctx, id, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.RevisionDiffResponse), args.Error(1)
+}
+
+func (m *MockArticleService) RestoreRevision(ctx context.Context, id uint, revision uint, version uint) (*dto.ArticleResponse, error) {
+	args := m.Called(ctx, id, revision, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.ArticleResponse), args.Error(1)
+}
+
+func (m *MockArticleService) Restore(ctx context.Context, id uint, version uint) (*dto.ArticleResponse, error) {
+	args := m.Called(ctx, id, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.ArticleResponse), args.Error(1)
+}
+
+func (m *MockArticleService) Purge(ctx context.Context, retention time.Duration) (*dto.PurgeArticlesResponse, error) {
+	args := m.Called(ctx, retention)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.PurgeArticlesResponse), args.Error(1)
+}
+
+func (m *MockArticleService) Batch(ctx context.Context, req dto.BatchRequest) (*dto.BatchResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.BatchResponse), args.Error(1)
+}
+
 func setupTestRouter() *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	return gin.New()
@@ -113,7 +258,41 @@ func TestCreateHandlerWithMissingRequiredField(t *testing.T) {
 
 	router.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	mockService.AssertNotCalled(t, "Create")
+}
+
+func TestCreateHandlerListsEveryInvalidField(t *testing.T) {
+	mockService := new(MockArticleService)
+	handler := NewArticleHandler(mockService, zap.NewNop())
+
+	router := setupTestRouter()
+	router.POST("/articles", handler.Create)
+
+	body := `{"title":"  ","summary":"` + strings.Repeat("s", 501) + `","tags":["go","c++","<script>"]}`
+	req := httptest.NewRequest(http.MethodPost, "/articles", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+
+	var resp struct {
+		Code   string `json:"code"`
+		Errors []struct {
+			Field string `json:"field"`
+			Rule  string `json:"rule"`
+		} `json:"errors"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "validation_failed", resp.Code)
+	assert.Len(t, resp.Errors, 3)
+	for i, want := range [][2]string{{"title", "notblank"}, {"summary", "max"}, {"tags[2]", "label"}} {
+		assert.Equal(t, want[0], resp.Errors[i].Field)
+		assert.Equal(t, want[1], resp.Errors[i].Rule)
+	}
 	mockService.AssertNotCalled(t, "Create")
 }
 
@@ -157,7 +336,7 @@ func TestGetHandlerReturnsArticleSuccessfully(t *testing.T) {
 		CreatedAt: time.Now().UTC(),
 	}
 
-	mockService.On("GetByID", mock.Anything, uint(1)).Return(expectedResp, nil)
+	mockService.On("GetByID", mock.Anything, uint(1), false).Return(expectedResp, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/articles/1", nil)
 	w := httptest.NewRecorder()
@@ -216,7 +395,7 @@ func TestGetHandlerWithNonExistentArticle(t *testing.T) {
 	router := setupTestRouter()
 	router.GET("/articles/:id", handler.Get)
 
-	mockService.On("GetByID", mock.Anything, uint(999)).Return(nil, gorm.ErrRecordNotFound)
+	mockService.On("GetByID", mock.Anything, uint(999), false).Return(nil, services.ErrArticleNotFound)
 
 	req := httptest.NewRequest(http.MethodGet, "/articles/999", nil)
 	w := httptest.NewRecorder()
@@ -224,6 +403,15 @@ func TestGetHandlerWithNonExistentArticle(t *testing.T) {
 	router.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{
+		"type": "about:blank",
+		"title": "Not Found",
+		"status": 404,
+		"detail": "article not found",
+		"instance": "/articles/999",
+		"code": "article_not_found"
+	}`, w.Body.String())
 	mockService.AssertExpectations(t)
 }
 
@@ -236,13 +424,946 @@ func TestGetHandlerWithServiceError(t *testing.T) {
 	router.GET("/articles/:id", handler.Get)
 
 	expectedError := errors.New("service error")
-	mockService.On("GetByID", mock.Anything, uint(1)).Return(nil, expectedError)
+	mockService.On("GetByID", mock.Anything, uint(1), false).Return(nil, expectedError)
 
 	req := httptest.NewRequest(http.MethodGet, "/articles/1", nil)
 	w := httptest.NewRecorder()
 
 	router.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NotContains(t, w.Body.String(), "service error")
+	mockService.AssertExpectations(t)
+}
+
+func TestListHandlerReturnsArticlesSuccessfully(t *testing.T) {
+	mockService := new(MockArticleService)
+	logger := zap.NewNop()
+	handler := NewArticleHandler(mockService, logger)
+
+	router := setupTestRouter()
+	router.GET("/articles", handler.List)
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	expectedReq := dto.ListArticlesRequest{
+		Limit:       10,
+		Title:       "go",
+		CreatedFrom: from,
+		Sort:        "id",
+	}
+	expectedResp := &dto.ListArticlesResponse{
+		Items: []dto.ArticleResponse{{ID: 1, Title: "Go"}},
+		Meta:  dto.ListMeta{Total: 1, Limit: 10, NextCursor: "abc"},
+	}
+
+	mockService.On("List", mock.Anything, mock.MatchedBy(func(r dto.ListArticlesRequest) bool {
+		return r.Limit == expectedReq.Limit && r.Title == expectedReq.Title &&
+			r.CreatedFrom.Equal(from) && r.Sort == expectedReq.Sort
+	})).Return(expectedResp, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/articles?limit=10&title=go&created_from=2025-01-01T00:00:00Z&sort=id", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response dto.ListArticlesResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Len(t, response.Items, 1)
+	assert.Equal(t, "abc", response.Meta.NextCursor)
+	mockService.AssertExpectations(t)
+}
+
+func TestListHandlerWithInvalidQuery(t *testing.T) {
+	mockService := new(MockArticleService)
+	logger := zap.NewNop()
+	handler := NewArticleHandler(mockService, logger)
+
+	router := setupTestRouter()
+	router.GET("/articles", handler.List)
+
+	for _, query := range []string{"limit=-1", "limit=1000", "sort=title", "created_to=yesterday", "tag_match=some"} {
+		req := httptest.NewRequest(http.MethodGet, "/articles?"+query, nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+	mockService.AssertNotCalled(t, "List")
+}
+
+func TestListHandlerWithInvalidCursor(t *testing.T) {
+	mockService := new(MockArticleService)
+	logger := zap.NewNop()
+	handler := NewArticleHandler(mockService, logger)
+
+	router := setupTestRouter()
+	router.GET("/articles", handler.List)
+
+	mockService.On("List", mock.Anything, mock.Anything).Return(nil, services.ErrInvalidCursor)
+
+	req := httptest.NewRequest(http.MethodGet, "/articles?cursor=bogus", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestListHandlerWithServiceError(t *testing.T) {
+	mockService := new(MockArticleService)
+	logger := zap.NewNop()
+	handler := NewArticleHandler(mockService, logger)
+
+	router := setupTestRouter()
+	router.GET("/articles", handler.List)
+
+	mockService.On("List", mock.Anything, mock.Anything).Return(nil, errors.New("service error"))
+
+	req := httptest.NewRequest(http.MethodGet, "/articles", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestUpdateHandlerWithValidRequest(t *testing.T) {
+	mockService := new(MockArticleService)
+	logger := zap.NewNop()
+	handler := NewArticleHandler(mockService, logger)
+
+	router := setupTestRouter()
+	router.PUT("/articles/:id", handler.Update)
+
+	reqBody := dto.UpdateArticleRequest{Title: "Fixed"}
+	expectedResp := &dto.ArticleResponse{ID: 1, Title: "Fixed", Version: 4}
+
+	mockService.On("Update", mock.Anything, uint(1), uint(3), reqBody).Return(expectedResp, nil)
+
+	body, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest(http.MethodPut, "/articles/1", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", `"3"`)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"4"`, w.Header().Get("ETag"))
+
+	var response dto.ArticleResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "Fixed", response.Title)
+	mockService.AssertExpectations(t)
+}
+
+func TestUpdateHandlerWithMissingRequiredField(t *testing.T) {
+	mockService := new(MockArticleService)
+	logger := zap.NewNop()
+	handler := NewArticleHandler(mockService, logger)
+
+	router := setupTestRouter()
+	router.PUT("/articles/:id", handler.Update)
+
+	req := httptest.NewRequest(http.MethodPut, "/articles/1", bytes.NewBufferString(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", `"1"`)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	mockService.AssertNotCalled(t, "Update")
+}
+
+func TestUpdateHandlerMapsServiceErrors(t *testing.T) {
+	cases := map[error]int{
+		services.ErrArticleNotFound: http.StatusNotFound,
+		services.ErrInvalidArticle:  http.StatusUnprocessableEntity,
+		services.ErrVersionConflict: http.StatusPreconditionFailed,
+		errors.New("service error"): http.StatusInternalServerError,
+	}
+
+	for serviceErr, expectedStatus := range cases {
+		mockService := new(MockArticleService)
+		logger := zap.NewNop()
+		handler := NewArticleHandler(mockService, logger)
+
+		router := setupTestRouter()
+		router.PUT("/articles/:id", handler.Update)
+
+		mockService.On("Update", mock.Anything, uint(1), uint(1), mock.Anything).Return(nil, serviceErr)
+
+		req := httptest.NewRequest(http.MethodPut, "/articles/1", bytes.NewBufferString(`{"title":"x"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"1"`)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, expectedStatus, w.Code, serviceErr.Error())
+		mockService.AssertExpectations(t)
+	}
+}
+
+func TestPatchHandlerPassesContentTypeAndBody(t *testing.T) {
+	mockService := new(MockArticleService)
+	logger := zap.NewNop()
+	handler := NewArticleHandler(mockService, logger)
+
+	router := setupTestRouter()
+	router.PATCH("/articles/:id", handler.Patch)
+
+	patch := []byte(`[{"op":"replace","path":"/title","value":"New"}]`)
+	expectedResp := &dto.ArticleResponse{ID: 1, Title: "New"}
+
+	mockService.On("Patch", mock.Anything, uint(1), services.AnyVersion, dto.JSONPatchContentType, patch).Return(expectedResp, nil)
+
+	req := httptest.NewRequest(http.MethodPatch, "/articles/1", bytes.NewBuffer(patch))
+	req.Header.Set("Content-Type", dto.JSONPatchContentType)
+	req.Header.Set("If-Match", "*")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
 	mockService.AssertExpectations(t)
 }
+
+func TestPatchHandlerWithUnsupportedContentType(t *testing.T) {
+	mockService := new(MockArticleService)
+	logger := zap.NewNop()
+	handler := NewArticleHandler(mockService, logger)
+
+	router := setupTestRouter()
+	router.PATCH("/articles/:id", handler.Patch)
+
+	req := httptest.NewRequest(http.MethodPatch, "/articles/1", bytes.NewBufferString(`title=New`))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("If-Match", `"1"`)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+	mockService.AssertNotCalled(t, "Patch")
+}
+
+func TestPatchHandlerWithInvalidPatch(t *testing.T) {
+	mockService := new(MockArticleService)
+	logger := zap.NewNop()
+	handler := NewArticleHandler(mockService, logger)
+
+	router := setupTestRouter()
+	router.PATCH("/articles/:id", handler.Patch)
+
+	mockService.On("Patch", mock.Anything, uint(1), uint(1), dto.MergePatchContentType, mock.Anything).
+		Return(nil, services.ErrInvalidPatch)
+
+	req := httptest.NewRequest(http.MethodPatch, "/articles/1", bytes.NewBufferString(`{"title":`))
+	req.Header.Set("Content-Type", dto.MergePatchContentType)
+	req.Header.Set("If-Match", `"1"`)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetHandlerIncludingDeleted(t *testing.T) {
+	mockService := new(MockArticleService)
+	logger := zap.NewNop()
+	handler := NewArticleHandler(mockService, logger)
+
+	router := setupTestRouter()
+	router.GET("/articles/:id", handler.Get)
+
+	deletedAt := time.Now().UTC()
+	expectedResp := &dto.ArticleResponse{ID: 1, Title: "Gone", DeletedAt: &deletedAt}
+	mockService.On("GetByID", mock.Anything, uint(1), true).Return(expectedResp, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/articles/1?include_deleted=true", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "deleted_at")
+	mockService.AssertExpectations(t)
+}
+
+func TestIncludeDeletedIgnoredForAnonymousCallers(t *testing.T) {
+	ctx := context.Background()
+	articles := repository.NewMemoryRepo(zap.NewNop())
+	authors := repository.NewMemoryAuthorRepo(articles, zap.NewNop())
+
+	subject := "alice"
+	alice := &entities.Author{Name: "Alice", Email: "alice@example.com", Subject: &subject}
+	assert.NoError(t, authors.Create(ctx, alice))
+	article := &entities.Article{Title: "Gone", Slug: "gone", Status: entities.StatusPublished, AuthorID: &alice.ID}
+	assert.NoError(t, articles.Create(ctx, article))
+	assert.NoError(t, articles.Delete(ctx, article.ID, article.Version))
+
+	handler := NewArticleHandler(services.NewArticleService(articles, authors, zap.NewNop()), zap.NewNop())
+	router := setupTestRouter()
+	router.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), auth.Anonymous))
+	})
+	router.GET("/articles", handler.List)
+	router.GET("/articles/:id", handler.Get)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/articles/1?include_deleted=true", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/articles?include_deleted=true", nil))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.NotContains(t, w.Body.String(), "Gone")
+}
+
+func TestDeleteHandlerSuccessfully(t *testing.T) {
+	mockService := new(MockArticleService)
+	logger := zap.NewNop()
+	handler := NewArticleHandler(mockService, logger)
+
+	router := setupTestRouter()
+	router.DELETE("/articles/:id", handler.Delete)
+
+	mockService.On("Delete", mock.Anything, uint(1), uint(2)).Return(nil)
+
+	req := httptest.NewRequest(http.MethodDelete, "/articles/1", nil)
+	req.Header.Set("If-Match", `"2"`)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestDeleteHandlerWithNonExistentArticle(t *testing.T) {
+	mockService := new(MockArticleService)
+	logger := zap.NewNop()
+	handler := NewArticleHandler(mockService, logger)
+
+	router := setupTestRouter()
+	router.DELETE("/articles/:id", handler.Delete)
+
+	mockService.On("Delete", mock.Anything, uint(999), services.AnyVersion).Return(services.ErrArticleNotFound)
+
+	req := httptest.NewRequest(http.MethodDelete, "/articles/999", nil)
+	req.Header.Set("If-Match", "*")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestRestoreHandlerSuccessfully(t *testing.T) {
+	mockService := new(MockArticleService)
+	logger := zap.NewNop()
+	handler := NewArticleHandler(mockService, logger)
+
+	router := setupTestRouter()
+	router.POST("/articles/:id/restore", handler.Restore)
+
+	mockService.On("Restore", mock.Anything, uint(1), uint(2)).Return(&dto.ArticleResponse{ID: 1, Title: "Back", Version: 3}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/articles/1/restore", nil)
+	req.Header.Set("If-Match", `"2"`)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestPurgeHandlerUsesConfiguredRetention(t *testing.T) {
+	mockService := new(MockArticleService)
+	logger := zap.NewNop()
+	handler := NewArticleHandler(mockService, logger)
+
+	router := setupTestRouter()
+	router.POST("/admin/articles/purge", handler.Purge(72*time.Hour))
+
+	mockService.On("Purge", mock.Anything, 72*time.Hour).Return(&dto.PurgeArticlesResponse{Purged: 2}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/articles/purge", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response dto.PurgeArticlesResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), response.Purged)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetHandlerSetsETagAndHonorsIfNoneMatch(t *testing.T) {
+	mockService := new(MockArticleService)
+	logger := zap.NewNop()
+	handler := NewArticleHandler(mockService, logger)
+
+	router := setupTestRouter()
+	router.GET("/articles/:id", handler.Get)
+
+	mockService.On("GetByID", mock.Anything, uint(1), false).Return(&dto.ArticleResponse{ID: 1, Version: 7}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/articles/1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"7"`, w.Header().Get("ETag"))
+
+	req = httptest.NewRequest(http.MethodGet, "/articles/1", nil)
+	req.Header.Set("If-None-Match", `"6", W/"7"`)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/articles/1", nil)
+	req.Header.Set("If-None-Match", `"6"`)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestMutatingHandlersRequireIfMatch(t *testing.T) {
+	mockService := new(MockArticleService)
+	logger := zap.NewNop()
+	handler := NewArticleHandler(mockService, logger)
+
+	router := setupTestRouter()
+	router.PUT("/articles/:id", handler.Update)
+	router.PATCH("/articles/:id", handler.Patch)
+	router.DELETE("/articles/:id", handler.Delete)
+	router.POST("/articles/:id/restore", handler.Restore)
+
+	requests := []*http.Request{
+		httptest.NewRequest(http.MethodPut, "/articles/1", bytes.NewBufferString(`{"title":"x"}`)),
+		httptest.NewRequest(http.MethodPatch, "/articles/1", bytes.NewBufferString(`{"title":"x"}`)),
+		httptest.NewRequest(http.MethodDelete, "/articles/1", nil),
+		httptest.NewRequest(http.MethodPost, "/articles/1/restore", nil),
+	}
+
+	for _, req := range requests {
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusPreconditionRequired, w.Code, req.Method)
+	}
+	mockService.AssertNotCalled(t, "Update")
+	mockService.AssertNotCalled(t, "Patch")
+	mockService.AssertNotCalled(t, "Delete")
+	mockService.AssertNotCalled(t, "Restore")
+}
+
+func TestUpdateHandlerWithWeakOrMalformedIfMatch(t *testing.T) {
+	mockService := new(MockArticleService)
+	logger := zap.NewNop()
+	handler := NewArticleHandler(mockService, logger)
+
+	router := setupTestRouter()
+	router.PUT("/articles/:id", handler.Update)
+
+	for _, header := range []string{`W/"1"`, `1`, `"abc"`, `"0"`} {
+		req := httptest.NewRequest(http.MethodPut, "/articles/1", bytes.NewBufferString(`{"title":"x"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", header)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusPreconditionFailed, w.Code, header)
+	}
+	mockService.AssertNotCalled(t, "Update")
+}
+
+func TestGetHandlerSelectsBodyFormat(t *testing.T) {
+	mockService := new(MockArticleService)
+	logger := zap.NewNop()
+	handler := NewArticleHandler(mockService, logger)
+
+	router := setupTestRouter()
+	router.GET("/articles/:id", handler.Get)
+
+	article := &dto.ArticleResponse{ID: 1, Title: "Rich", Body: "**hi**", BodyHTML: "<p><strong>hi</strong></p>"}
+	mockService.On("GetByID", mock.Anything, uint(1), false).Return(article, nil)
+
+	cases := map[string]func(dto.ArticleResponse){
+		"raw": func(r dto.ArticleResponse) {
+			assert.Equal(t, "**hi**", r.Body)
+			assert.Empty(t, r.BodyHTML)
+		},
+		"html": func(r dto.ArticleResponse) {
+			assert.Empty(t, r.Body)
+			assert.Equal(t, article.BodyHTML, r.BodyHTML)
+		},
+		"both": func(r dto.ArticleResponse) {
+			assert.Equal(t, "**hi**", r.Body)
+			assert.Equal(t, article.BodyHTML, r.BodyHTML)
+		},
+	}
+
+	for format, check := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/articles/1?format="+format, nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code, format)
+
+		var response dto.ArticleResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		check(response)
+	}
+}
+
+func TestGetHandlerNegotiatesBodyMediaType(t *testing.T) {
+	mockService := new(MockArticleService)
+	logger := zap.NewNop()
+	handler := NewArticleHandler(mockService, logger)
+
+	router := setupTestRouter()
+	router.GET("/articles/:id", handler.Get)
+
+	article := &dto.ArticleResponse{ID: 1, Title: "Rich", Body: "**hi**", BodyHTML: "<p><strong>hi</strong></p>"}
+	mockService.On("GetByID", mock.Anything, uint(1), false).Return(article, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/articles/1", nil)
+	req.Header.Set("Accept", "text/html")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/html")
+	assert.Equal(t, article.BodyHTML, w.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/articles/1", nil)
+	req.Header.Set("Accept", "text/markdown")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/markdown")
+	assert.Equal(t, article.Body, w.Body.String())
+}
+
+func TestGetHandlerWithInvalidFormat(t *testing.T) {
+	mockService := new(MockArticleService)
+	logger := zap.NewNop()
+	handler := NewArticleHandler(mockService, logger)
+
+	router := setupTestRouter()
+	router.GET("/articles/:id", handler.Get)
+
+	req := httptest.NewRequest(http.MethodGet, "/articles/1?format=pdf", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "GetByID")
+}
+
+func TestListHandlerBindsTagFilters(t *testing.T) {
+	mockService := new(MockArticleService)
+	handler := NewArticleHandler(mockService, zap.NewNop())
+
+	router := setupTestRouter()
+	router.GET("/articles", handler.List)
+
+	mockService.On("List", mock.Anything, dto.ListArticlesRequest{
+		Tags:     []string{"go", "sql"},
+		TagMatch: "all",
+		Category: "backend",
+	}).Return(&dto.ListArticlesResponse{}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/articles?tag=go&tag=sql&tag_match=all&category=backend", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestListTagsHandler(t *testing.T) {
+	mockService := new(MockArticleService)
+	handler := NewArticleHandler(mockService, zap.NewNop())
+
+	router := setupTestRouter()
+	router.GET("/tags", handler.ListTags)
+
+	mockService.On("ListTags", mock.Anything).Return(&dto.ListTagsResponse{
+		Items: []dto.TagCountResponse{{Name: "go", Count: 3}},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/tags", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"items":[{"name":"go","count":3}]}`, w.Body.String())
+}
+
+func TestSearchHandlerUsesDefaultLanguage(t *testing.T) {
+	mockService := new(MockArticleService)
+	handler := NewArticleHandler(mockService, zap.NewNop())
+
+	router := setupTestRouter()
+	router.GET("/articles/search", handler.Search("english"))
+
+	expected := dto.SearchArticlesRequest{Query: "go generics", Limit: 5}
+	mockService.On("Search", mock.Anything, expected, "english").Return(&dto.SearchArticlesResponse{
+		Items: []dto.SearchHitResponse{{
+			ArticleResponse: dto.ArticleResponse{ID: 1, Title: "Generics"},
+			Rank:            0.5,
+			Headline:        "<mark>generics</mark> in go",
+		}},
+		Meta: dto.ListMeta{Total: 1, Limit: 5},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/articles/search?q=go+generics&limit=5", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp dto.SearchArticlesResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Len(t, resp.Items, 1)
+	assert.Equal(t, "Generics", resp.Items[0].Title)
+	assert.Equal(t, "<mark>generics</mark> in go", resp.Items[0].Headline)
+	mockService.AssertExpectations(t)
+}
+
+func TestSearchHandlerRequiresQuery(t *testing.T) {
+	mockService := new(MockArticleService)
+	handler := NewArticleHandler(mockService, zap.NewNop())
+
+	router := setupTestRouter()
+	router.GET("/articles/search", handler.Search("english"))
+
+	req := httptest.NewRequest(http.MethodGet, "/articles/search", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "Search", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSearchHandlerRejectsUnsupportedLanguage(t *testing.T) {
+	mockService := new(MockArticleService)
+	handler := NewArticleHandler(mockService, zap.NewNop())
+
+	router := setupTestRouter()
+	router.GET("/articles/search", handler.Search("english"))
+
+	expected := dto.SearchArticlesRequest{Query: "go", Language: "klingon"}
+	mockService.On("Search", mock.Anything, expected, "english").Return(nil, services.ErrInvalidSearch)
+
+	req := httptest.NewRequest(http.MethodGet, "/articles/search?q=go&lang=klingon", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestTransitionHandlerPublishes(t *testing.T) {
+	mockService := new(MockArticleService)
+	handler := NewArticleHandler(mockService, zap.NewNop())
+
+	router := setupTestRouter()
+	router.POST("/articles/:id/publish", handler.Transition(entities.StatusPublished))
+
+	mockService.On("Transition", mock.Anything, uint(1), uint(2), entities.StatusPublished).
+		Return(&dto.ArticleResponse{ID: 1, Status: "published", Version: 3}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/articles/1/publish", nil)
+	req.Header.Set("If-Match", `"2"`)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+	mockService.AssertExpectations(t)
+}
+
+func TestTransitionHandlerInvalidMove(t *testing.T) {
+	mockService := new(MockArticleService)
+	handler := NewArticleHandler(mockService, zap.NewNop())
+
+	router := setupTestRouter()
+	router.POST("/articles/:id/archive", handler.Transition(entities.StatusArchived))
+
+	mockService.On("Transition", mock.Anything, uint(1), uint(2), entities.StatusArchived).
+		Return(nil, services.ErrInvalidTransition)
+
+	req := httptest.NewRequest(http.MethodPost, "/articles/1/archive", nil)
+	req.Header.Set("If-Match", `"2"`)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestScheduleHandler(t *testing.T) {
+	mockService := new(MockArticleService)
+	handler := NewArticleHandler(mockService, zap.NewNop())
+
+	router := setupTestRouter()
+	router.PUT("/articles/:id/schedule", handler.Schedule)
+
+	publishAt := time.Date(2030, 1, 2, 9, 0, 0, 0, time.UTC)
+	mockService.On("Schedule", mock.Anything, uint(1), uint(2), publishAt).
+		Return(&dto.ArticleResponse{ID: 1, Status: "in_review", PublishAt: &publishAt, Version: 3}, nil)
+
+	req := httptest.NewRequest(http.MethodPut, "/articles/1/schedule", bytes.NewBufferString(`{"publish_at":"2030-01-02T09:00:00Z"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", `"2"`)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"publish_at":"2030-01-02T09:00:00Z"`)
+	mockService.AssertExpectations(t)
+}
+
+func TestScheduleHandlerRequiresPublishAt(t *testing.T) {
+	mockService := new(MockArticleService)
+	handler := NewArticleHandler(mockService, zap.NewNop())
+
+	router := setupTestRouter()
+	router.PUT("/articles/:id/schedule", handler.Schedule)
+
+	req := httptest.NewRequest(http.MethodPut, "/articles/1/schedule", bytes.NewBufferString(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", `"2"`)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	mockService.AssertNotCalled(t, "Schedule", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestListRevisionsHandler(t *testing.T) {
+	mockService := new(MockArticleService)
+	handler := NewArticleHandler(mockService, zap.NewNop())
+
+	router := setupTestRouter()
+	router.GET("/articles/:id/revisions", handler.ListRevisions)
+
+	mockService.On("ListRevisions", mock.Anything, uint(1), dto.ListRevisionsRequest{Limit: 5}).
+		Return(&dto.ListRevisionsResponse{
+			Items: []dto.RevisionResponse{{Version: 2, Title: "New"}},
+			Meta:  dto.ListMeta{Total: 1, Limit: 5},
+		}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/articles/1/revisions?limit=5", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"version":2`)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetRevisionHandlerNotFound(t *testing.T) {
+	mockService := new(MockArticleService)
+	handler := NewArticleHandler(mockService, zap.NewNop())
+
+	router := setupTestRouter()
+	router.GET("/articles/:id/revisions/:rev", handler.GetRevision)
+
+	mockService.On("GetRevision", mock.Anything, uint(1), uint(9)).Return(nil, services.ErrRevisionNotFound)
+
+	req := httptest.NewRequest(http.MethodGet, "/articles/1/revisions/9", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "revision not found")
+}
+
+func TestGetRevisionHandlerInvalidRevision(t *testing.T) {
+	mockService := new(MockArticleService)
+	handler := NewArticleHandler(mockService, zap.NewNop())
+
+	router := setupTestRouter()
+	router.GET("/articles/:id/revisions/:rev", handler.GetRevision)
+
+	req := httptest.NewRequest(http.MethodGet, "/articles/1/revisions/0", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "GetRevision", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDiffRevisionsHandler(t *testing.T) {
+	mockService := new(MockArticleService)
+	handler := NewArticleHandler(mockService, zap.NewNop())
+
+	router := setupTestRouter()
+	router.GET("/articles/:id/revisions/diff", handler.DiffRevisions)
+
+	diff := "--- revision 1\n+++ revision 2\n@@ -1 +1 @@\n-Title: Old\n+Title: New\n"
+	mockService.On("DiffRevisions", mock.Anything, uint(1), uint(1), uint(2)).
+		Return(&dto.RevisionDiffResponse{From: 1, To: 2, Diff: diff}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/articles/1/revisions/diff?from=1&to=2", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"from":1`)
+
+	req = httptest.NewRequest(http.MethodGet, "/articles/1/revisions/diff?from=1&to=2", nil)
+	req.Header.Set("Accept", "text/x-diff")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/x-diff; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, diff, w.Body.String())
+}
+
+func TestDiffRevisionsHandlerRequiresBounds(t *testing.T) {
+	mockService := new(MockArticleService)
+	handler := NewArticleHandler(mockService, zap.NewNop())
+
+	router := setupTestRouter()
+	router.GET("/articles/:id/revisions/diff", handler.DiffRevisions)
+
+	req := httptest.NewRequest(http.MethodGet, "/articles/1/revisions/diff?from=1", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestRestoreRevisionHandler(t *testing.T) {
+	mockService := new(MockArticleService)
+	handler := NewArticleHandler(mockService, zap.NewNop())
+
+	router := setupTestRouter()
+	router.POST("/articles/:id/revisions/:rev/restore", handler.RestoreRevision)
+
+	mockService.On("RestoreRevision", mock.Anything, uint(1), uint(2), uint(4)).
+		Return(&dto.ArticleResponse{ID: 1, Title: "Old", Version: 5}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/articles/1/revisions/2/restore", nil)
+	req.Header.Set("If-Match", `"4"`)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"5"`, w.Header().Get("ETag"))
+	mockService.AssertExpectations(t)
+}
+
+func TestRestoreRevisionHandlerRequiresIfMatch(t *testing.T) {
+	mockService := new(MockArticleService)
+	handler := NewArticleHandler(mockService, zap.NewNop())
+
+	router := setupTestRouter()
+	router.POST("/articles/:id/revisions/:rev/restore", handler.RestoreRevision)
+
+	req := httptest.NewRequest(http.MethodPost, "/articles/1/revisions/2/restore", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusPreconditionRequired, w.Code)
+}
+
+func TestGetBySlugHandler(t *testing.T) {
+	mockService := new(MockArticleService)
+	handler := NewArticleHandler(mockService, zap.NewNop())
+
+	router := setupTestRouter()
+	router.GET("/articles/by-slug/:slug", handler.GetBySlug)
+
+	mockService.On("GetBySlug", mock.Anything, "hello-world").
+		Return(&dto.ArticleResponse{ID: 1, Title: "Hello, World", Slug: "hello-world", Version: 2}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/articles/by-slug/hello-world", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"2"`, w.Header().Get("ETag"))
+	assert.Contains(t, w.Body.String(), `"slug":"hello-world"`)
+}
+
+func TestGetBySlugHandlerRedirectsFormerSlug(t *testing.T) {
+	mockService := new(MockArticleService)
+	handler := NewArticleHandler(mockService, zap.NewNop())
+
+	router := setupTestRouter()
+	router.GET("/api/v1/articles/by-slug/:slug", handler.GetBySlug)
+
+	mockService.On("GetBySlug", mock.Anything, "hello").
+		Return(&dto.ArticleResponse{ID: 1, Slug: "hello-world"}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/articles/by-slug/hello?format=html", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "/api/v1/articles/by-slug/hello-world?format=html", w.Header().Get("Location"))
+}
+
+func TestGetBySlugHandlerNotFound(t *testing.T) {
+	mockService := new(MockArticleService)
+	handler := NewArticleHandler(mockService, zap.NewNop())
+
+	router := setupTestRouter()
+	router.GET("/articles/by-slug/:slug", handler.GetBySlug)
+
+	mockService.On("GetBySlug", mock.Anything, "missing").Return(nil, services.ErrArticleNotFound)
+
+	req := httptest.NewRequest(http.MethodGet, "/articles/by-slug/missing", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
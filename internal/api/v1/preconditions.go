@@ -0,0 +1,64 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/antonchaban/articles-go/internal/api/problem"
+	"github.com/antonchaban/articles-go/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// etag formats an article version as a strong entity tag.
+func etag(version uint) string {
+	return `"` + strconv.FormatUint(uint64(version), 10) + `"`
+}
+
+// setETag exposes the article version to clients so they can send it back in If-Match.
+func setETag(c *gin.Context, version uint) {
+	c.Header("ETag", etag(version))
+}
+
+// notModified reports whether the If-None-Match header matches the current tag.
+// Comparison is weak, as required for If-None-Match (RFC 9110, section 13.1.2).
+func notModified(c *gin.Context, current string) bool {
+	header := c.GetHeader("If-None-Match")
+	if header == "" {
+		return false
+	}
+
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == current {
+			return true
+		}
+	}
+	return false
+}
+
+// ifMatchVersion extracts the version a mutating request is conditioned on.
+// "If-Match: *" yields services.AnyVersion. It writes 428 Precondition Required
+// when the header is missing and 412 Precondition Failed when it cannot match
+// any version (weak or malformed tags), returning false in both cases.
+func ifMatchVersion(c *gin.Context) (uint, bool) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" {
+		problem.Write(c, http.StatusPreconditionRequired, problem.CodePreconditionRequired, "If-Match header is required")
+		return 0, false
+	}
+	if header == "*" {
+		return services.AnyVersion, true
+	}
+
+	// If-Match uses strong comparison, so weak tags never match
+	unquoted, err := strconv.Unquote(header)
+	if err == nil {
+		if version, err := strconv.ParseUint(unquoted, 10, 0); err == nil && version > 0 {
+			return uint(version), true
+		}
+	}
+
+	problem.Write(c, http.StatusPreconditionFailed, services.ErrVersionConflict.Code, "If-Match does not match the current article version")
+	return 0, false
+}
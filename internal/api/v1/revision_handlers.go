@@ -0,0 +1,136 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/antonchaban/articles-go/internal/api/problem"
+	"github.com/antonchaban/articles-go/internal/dto"
+	"github.com/antonchaban/articles-go/internal/log"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// mimeDiff is the media type of unified diffs.
+const mimeDiff = "text/x-diff"
+
+// ListRevisions handles GET requests to enumerate the revisions of an article,
+// newest first. Supported query parameters: limit and offset.
+// Returns 200 OK, 400 Bad Request, 403 Forbidden unless the caller owns the
+// article, or 404 Not Found.
+func (h *ArticleHandler) ListRevisions(c *gin.Context) {
+	id, ok := parseID(c, h.log)
+	if !ok {
+		return
+	}
+
+	var req dto.ListRevisionsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.WithTrace(c.Request.Context(), h.log).Warn("invalid list query", zap.Error(err))
+		problem.Invalid(c, http.StatusBadRequest, err)
+		return
+	}
+
+	resp, err := h.service.ListRevisions(c.Request.Context(), id, req)
+	if err != nil {
+		fail(c, h.log, "failed to read article history", err, zap.Uint("id", id))
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetRevision handles GET requests to retrieve an article as of a revision.
+// Returns 200 OK, 400 Bad Request, 403 Forbidden or 404 Not Found.
+func (h *ArticleHandler) GetRevision(c *gin.Context) {
+	id, ok := parseID(c, h.log)
+	if !ok {
+		return
+	}
+	rev, ok := parseRevision(c, h.log)
+	if !ok {
+		return
+	}
+
+	resp, err := h.service.GetRevision(c.Request.Context(), id, rev)
+	if err != nil {
+		fail(c, h.log, "failed to read article history", err, zap.Uint("id", id))
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// DiffRevisions handles GET requests comparing two revisions given by the
+// from and to query parameters. The unified diff is wrapped in JSON, or
+// returned as is when the Accept header prefers text/x-diff.
+// Returns 200 OK, 400 Bad Request, 403 Forbidden or 404 Not Found.
+func (h *ArticleHandler) DiffRevisions(c *gin.Context) {
+	id, ok := parseID(c, h.log)
+	if !ok {
+		return
+	}
+
+	var req dto.RevisionDiffRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.WithTrace(c.Request.Context(), h.log).Warn("invalid diff query", zap.Error(err))
+		problem.Invalid(c, http.StatusBadRequest, err)
+		return
+	}
+
+	resp, err := h.service.DiffRevisions(c.Request.Context(), id, req.From, req.To)
+	if err != nil {
+		fail(c, h.log, "failed to read article history", err, zap.Uint("id", id))
+		return
+	}
+
+	if c.NegotiateFormat(gin.MIMEJSON, mimeDiff) == mimeDiff {
+		c.Data(http.StatusOK, mimeDiff+"; charset=utf-8", []byte(resp.Diff))
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// RestoreRevision handles POST requests bringing the content of an article
+// back to a revision. If-Match is required as for Update.
+// Returns 200 OK with the article, 403 Forbidden, 404 Not Found for an
+// unknown article or revision, or 412/428 for precondition failures.
+func (h *ArticleHandler) RestoreRevision(c *gin.Context) {
+	id, ok := parseID(c, h.log)
+	if !ok {
+		return
+	}
+	rev, ok := parseRevision(c, h.log)
+	if !ok {
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.service.RestoreRevision(c.Request.Context(), id, rev, version)
+	if err != nil {
+		fail(c, h.log, "failed to modify article", err, zap.Uint("id", id))
+		return
+	}
+
+	setETag(c, resp.Version)
+	c.JSON(http.StatusOK, resp)
+}
+
+// parseRevision extracts the rev URL parameter, the version of a revision.
+// It writes a 400 Bad Request response and returns false on failure.
+func parseRevision(c *gin.Context, l *zap.Logger) (uint, bool) {
+	revStr := c.Param("rev")
+
+	rev, err := strconv.ParseUint(revStr, 10, 0)
+	if err != nil || rev == 0 {
+		log.WithTrace(c.Request.Context(), l).Warn("invalid revision format", zap.String("rev_param", revStr))
+		problem.Write(c, http.StatusBadRequest, problem.CodeInvalidRequest, "invalid revision; must be a positive integer")
+		return 0, false
+	}
+
+	return uint(rev), true
+}
@@ -1,19 +1,106 @@
 package v1
 
 import (
+	"time"
+
+	"github.com/antonchaban/articles-go/internal/entities"
+	"github.com/antonchaban/articles-go/pkg/validation"
+
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
 )
 
+func init() {
+	// requests are bound with the rules the services validate against
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		validation.Register(v)
+	}
+}
+
 // RegisterRoutes sets up the routing for the Article feature.
 // It accepts a RouterGroup so we can version the API (e.g., /api/v1) easily.
 // Routes registered:
+//   - GET  /articles - List articles with filtering and pagination
+//   - GET  /articles/search - Full-text search, ranked by relevance
 //   - POST /articles - Create a new article
+//   - POST /articles:batch - Create, update and delete several articles at once
+//   - GET  /articles/by-slug/:slug - Get an article by slug; former slugs redirect
 //   - GET  /articles/:id - Get an article by ID
-func RegisterRoutes(router *gin.RouterGroup, handler *ArticleHandler) {
+//   - PUT  /articles/:id - Replace an article
+//   - PATCH /articles/:id - Partially update an article (merge patch or JSON patch)
+//   - DELETE /articles/:id - Soft-delete an article
+//   - POST /articles/:id/restore - Restore a soft-deleted article
+//   - POST /articles/:id/submit - Submit a draft for review
+//   - POST /articles/:id/reject - Send an article under review back to draft
+//   - POST /articles/:id/publish - Publish an article under review (admins only)
+//   - POST /articles/:id/archive - Archive a published article
+//   - PUT  /articles/:id/schedule - Schedule an article in review for publication (admins only)
+//   - DELETE /articles/:id/schedule - Cancel a scheduled publication
+//   - GET  /articles/:id/revisions - List the revisions of an article
+//   - GET  /articles/:id/revisions/diff?from=&to= - Unified diff between two revisions
+//   - GET  /articles/:id/revisions/:rev - Get an article as of a revision
+//   - POST /articles/:id/revisions/:rev/restore - Bring an article back to a revision
+//   - GET  /tags - List tags with usage counts
+//
+// searchLanguage is the text search configuration used for queries that
+// don't name one.
+func RegisterRoutes(router *gin.RouterGroup, handler *ArticleHandler, searchLanguage string) {
 	// Group routes under /articles
 	articles := router.Group("/articles")
 	{
+		articles.GET("", handler.List)
 		articles.POST("", handler.Create)
+		articles.GET("/search", handler.Search(searchLanguage))
+		articles.GET("/by-slug/:slug", handler.GetBySlug)
 		articles.GET("/:id", handler.Get)
+		articles.PUT("/:id", handler.Update)
+		articles.PATCH("/:id", handler.Patch)
+		articles.DELETE("/:id", handler.Delete)
+		articles.POST("/:id/restore", handler.Restore)
+		articles.POST("/:id/submit", handler.Transition(entities.StatusInReview))
+		articles.POST("/:id/reject", handler.Transition(entities.StatusDraft))
+		articles.POST("/:id/publish", handler.Transition(entities.StatusPublished))
+		articles.POST("/:id/archive", handler.Transition(entities.StatusArchived))
+		articles.PUT("/:id/schedule", handler.Schedule)
+		articles.DELETE("/:id/schedule", handler.Unschedule)
+		articles.GET("/:id/revisions", handler.ListRevisions)
+		articles.GET("/:id/revisions/diff", handler.DiffRevisions)
+		articles.GET("/:id/revisions/:rev", handler.GetRevision)
+		articles.POST("/:id/revisions/:rev/restore", handler.RestoreRevision)
 	}
+
+	router.POST("/articles:method", customMethods(map[string]gin.HandlerFunc{
+		":batch": handler.Batch,
+	}))
+
+	router.GET("/tags", handler.ListTags)
+}
+
+// RegisterAuthorRoutes sets up the routing for the Author feature.
+// Routes registered:
+//   - GET    /authors - List authors
+//   - POST   /authors - Register an author
+//   - GET    /authors/:id - Get an author by ID
+//   - PUT    /authors/:id - Replace an author profile
+//   - DELETE /authors/:id - Delete an author without articles
+//   - GET    /authors/:id/articles - List the articles of an author
+func RegisterAuthorRoutes(router *gin.RouterGroup, handler *AuthorHandler, articleHandler *ArticleHandler) {
+	authors := router.Group("/authors")
+	{
+		authors.GET("", handler.List)
+		authors.POST("", handler.Create)
+		authors.GET("/:id", handler.Get)
+		authors.PUT("/:id", handler.Update)
+		authors.DELETE("/:id", handler.Delete)
+		authors.GET("/:id/articles", articleHandler.ListByAuthor)
+	}
+}
+
+// RegisterAdminRoutes sets up administrative article routes.
+// The caller is responsible for protecting the group.
+// Routes registered:
+//   - POST /articles/purge - Hard-delete articles soft-deleted longer than retention
+func RegisterAdminRoutes(router *gin.RouterGroup, handler *ArticleHandler, retention time.Duration) {
+	router.POST("/articles/purge", handler.Purge(retention))
 }
@@ -0,0 +1,52 @@
+// Package auth carries the authenticated caller through request contexts.
+package auth
+
+import (
+	"context"
+	"slices"
+)
+
+// RoleAdmin grants access to every article regardless of ownership.
+const RoleAdmin = "admin"
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	// Subject is the "sub" claim of the caller's token.
+	// It is empty for anonymous callers.
+	Subject string
+	Roles   []string
+}
+
+// Anonymous is the principal of unauthenticated reads while authentication
+// is enabled. It owns nothing and has no roles.
+var Anonymous = Principal{}
+
+// IsAnonymous reports whether the caller did not authenticate.
+func (p Principal) IsAnonymous() bool {
+	return p.Subject == ""
+}
+
+// HasRole reports whether the principal was granted role.
+func (p Principal) HasRole(role string) bool {
+	return slices.Contains(p.Roles, role)
+}
+
+// IsAdmin reports whether the principal has the admin role.
+func (p Principal) IsAdmin() bool {
+	return p.HasRole(RoleAdmin)
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying p.
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns the principal stored in ctx.
+// The second result is false when authentication is disabled; anonymous
+// callers of a deployment with authentication are represented by Anonymous.
+func FromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -17,6 +18,28 @@ type Config struct {
 	// HTTPPort is the port number on which the HTTP server will listen.
 	HTTPPort string `mapstructure:"HTTP_PORT"`
 
+	// ShutdownDelay is how long the server keeps serving after it starts failing
+	// readiness checks, giving load balancers time to stop routing to it.
+	ShutdownDelay time.Duration `mapstructure:"SHUTDOWN_DELAY"`
+
+	// ShutdownTimeout bounds how long in-flight requests may take to drain.
+	ShutdownTimeout time.Duration `mapstructure:"SHUTDOWN_TIMEOUT"`
+
+	// HealthCheckTimeout bounds how long a single readiness dependency check may take.
+	HealthCheckTimeout time.Duration `mapstructure:"HEALTH_CHECK_TIMEOUT"`
+
+	// HealthCacheTTL is how long readiness check results are reused
+	// before the dependencies are checked again.
+	HealthCacheTTL time.Duration `mapstructure:"HEALTH_CACHE_TTL"`
+
+	// StorageDriver selects where data is kept: "postgres", "sqlite" for
+	// installations without a database server, or "memory" for tests and
+	// local development, which loses everything on exit.
+	StorageDriver string `mapstructure:"STORAGE_DRIVER"`
+
+	// SQLitePath is the database file used by the "sqlite" storage driver.
+	SQLitePath string `mapstructure:"SQLITE_PATH"`
+
 	// DBHost is the hostname or IP address of the database server.
 	DBHost string `mapstructure:"DB_HOST"`
 
@@ -31,6 +54,101 @@ type Config struct {
 
 	// DBName is the name of the database to connect to.
 	DBName string `mapstructure:"DB_NAME"`
+
+	// DBAutoMigrate applies pending schema migrations on startup.
+	DBAutoMigrate bool `mapstructure:"DB_AUTO_MIGRATE"`
+
+	// DBMaxOpenConns limits the open connections to the database, 0 means no limit.
+	DBMaxOpenConns int `mapstructure:"DB_MAX_OPEN_CONNS"`
+
+	// DBMaxIdleConns limits the connections kept open while idle.
+	DBMaxIdleConns int `mapstructure:"DB_MAX_IDLE_CONNS"`
+
+	// DBConnMaxLifetime closes connections once they are this old, 0 keeps them.
+	DBConnMaxLifetime time.Duration `mapstructure:"DB_CONN_MAX_LIFETIME"`
+
+	// DBConnMaxIdleTime closes connections idle for this long, 0 keeps them.
+	DBConnMaxIdleTime time.Duration `mapstructure:"DB_CONN_MAX_IDLE_TIME"`
+
+	// DBConnectRetryInterval is the delay before retrying a failed connection
+	// on startup. It doubles, with jitter, after every failed attempt up to
+	// DBConnectRetryMaxInterval.
+	DBConnectRetryInterval time.Duration `mapstructure:"DB_CONNECT_RETRY_INTERVAL"`
+
+	// DBConnectRetryMaxInterval caps the delay between connection attempts.
+	DBConnectRetryMaxInterval time.Duration `mapstructure:"DB_CONNECT_RETRY_MAX_INTERVAL"`
+
+	// DBConnectTimeout bounds how long connecting is retried before the
+	// application gives up, 0 retries until it is stopped.
+	DBConnectTimeout time.Duration `mapstructure:"DB_CONNECT_TIMEOUT"`
+
+	// AdminToken is the shared secret required by admin-only endpoints.
+	// Admin endpoints are disabled when it is empty.
+	AdminToken string `mapstructure:"ADMIN_TOKEN"`
+
+	// AuthEnabled requires a valid JWT bearer token for requests that modify articles.
+	// It is on by default; the application then refuses to start without
+	// JWTSecret, JWTPublicKeyFile or JWTJWKSFile.
+	AuthEnabled bool `mapstructure:"AUTH_ENABLED"`
+
+	// JWTSecret is the shared secret verifying HS256 tokens.
+	JWTSecret string `mapstructure:"JWT_SECRET"`
+
+	// JWTPublicKeyFile is the path of a PEM encoded RSA public key verifying RS256 tokens.
+	JWTPublicKeyFile string `mapstructure:"JWT_PUBLIC_KEY_FILE"`
+
+	// JWTJWKSFile is the path of a local JWKS document with verification keys.
+	JWTJWKSFile string `mapstructure:"JWT_JWKS_FILE"`
+
+	// JWTIssuer and JWTAudience are required token claims when set.
+	JWTIssuer   string `mapstructure:"JWT_ISSUER"`
+	JWTAudience string `mapstructure:"JWT_AUDIENCE"`
+
+	// JWTWriteRole is a role tokens must carry to modify articles.
+	// Any authenticated subject may write when it is empty.
+	JWTWriteRole string `mapstructure:"JWT_WRITE_ROLE"`
+
+	// SoftDeleteRetention is how long soft-deleted articles are kept before
+	// they become eligible for purging.
+	SoftDeleteRetention time.Duration `mapstructure:"SOFT_DELETE_RETENTION"`
+
+	// SchedulerEnabled runs the background publisher of scheduled articles.
+	// Every replica may run it; due articles are claimed with row locks.
+	SchedulerEnabled bool `mapstructure:"SCHEDULER_ENABLED"`
+
+	// SchedulerInterval is how often the scheduler looks for due articles.
+	SchedulerInterval time.Duration `mapstructure:"SCHEDULER_INTERVAL"`
+
+	// SchedulerBatchSize caps how many articles are published per transaction.
+	SchedulerBatchSize int `mapstructure:"SCHEDULER_BATCH_SIZE"`
+
+	// IdempotencyTTL is how long responses to requests sent with an
+	// Idempotency-Key are kept for replaying them to retries.
+	IdempotencyTTL time.Duration `mapstructure:"IDEMPOTENCY_TTL"`
+
+	// IdempotencySweepInterval is how often expired idempotency keys are deleted.
+	IdempotencySweepInterval time.Duration `mapstructure:"IDEMPOTENCY_SWEEP_INTERVAL"`
+
+	// SearchLanguage is the Postgres text search configuration used for
+	// search queries that don't name a language, e.g. "english" or "simple".
+	SearchLanguage string `mapstructure:"SEARCH_LANGUAGE"`
+
+	// TracingExporter selects where spans are sent: "none", "otlp",
+	// "stdout" or "file".
+	TracingExporter string `mapstructure:"TRACING_EXPORTER"`
+
+	// TracingServiceName is the service name spans are reported under.
+	TracingServiceName string `mapstructure:"TRACING_SERVICE_NAME"`
+
+	// TracingOTLPEndpoint is the URL the "otlp" exporter posts spans to.
+	// The OTEL_EXPORTER_OTLP_* environment variables apply when it is empty.
+	TracingOTLPEndpoint string `mapstructure:"TRACING_OTLP_ENDPOINT"`
+
+	// TracingFile is the file the "file" exporter appends spans to.
+	TracingFile string `mapstructure:"TRACING_FILE"`
+
+	// TracingSampleRatio is the share of new traces recorded, from 0 to 1.
+	TracingSampleRatio float64 `mapstructure:"TRACING_SAMPLE_RATIO"`
 }
 
 // Load reads configuration from file or environment variables.
@@ -39,7 +157,41 @@ func Load() (*Config, error) {
 
 	v.SetDefault("APP_ENV", "development")
 	v.SetDefault("HTTP_PORT", "8080")
+	v.SetDefault("SHUTDOWN_DELAY", "5s")
+	v.SetDefault("SHUTDOWN_TIMEOUT", "15s")
+	v.SetDefault("HEALTH_CHECK_TIMEOUT", "2s")
+	v.SetDefault("HEALTH_CACHE_TTL", "5s")
+	v.SetDefault("STORAGE_DRIVER", "postgres")
+	v.SetDefault("SQLITE_PATH", "articles.db")
 	v.SetDefault("DB_PORT", 5432)
+	v.SetDefault("DB_AUTO_MIGRATE", true)
+	v.SetDefault("DB_MAX_OPEN_CONNS", 25)
+	v.SetDefault("DB_MAX_IDLE_CONNS", 10)
+	v.SetDefault("DB_CONN_MAX_LIFETIME", "30m")
+	v.SetDefault("DB_CONN_MAX_IDLE_TIME", "5m")
+	v.SetDefault("DB_CONNECT_RETRY_INTERVAL", "500ms")
+	v.SetDefault("DB_CONNECT_RETRY_MAX_INTERVAL", "10s")
+	v.SetDefault("DB_CONNECT_TIMEOUT", "1m")
+	v.SetDefault("ADMIN_TOKEN", "")
+	v.SetDefault("SOFT_DELETE_RETENTION", "720h")
+	v.SetDefault("SEARCH_LANGUAGE", "english")
+	v.SetDefault("SCHEDULER_ENABLED", true)
+	v.SetDefault("SCHEDULER_INTERVAL", "30s")
+	v.SetDefault("SCHEDULER_BATCH_SIZE", 50)
+	v.SetDefault("IDEMPOTENCY_TTL", "24h")
+	v.SetDefault("IDEMPOTENCY_SWEEP_INTERVAL", "1h")
+	v.SetDefault("AUTH_ENABLED", true)
+	v.SetDefault("JWT_SECRET", "")
+	v.SetDefault("JWT_PUBLIC_KEY_FILE", "")
+	v.SetDefault("JWT_JWKS_FILE", "")
+	v.SetDefault("JWT_ISSUER", "")
+	v.SetDefault("JWT_AUDIENCE", "")
+	v.SetDefault("JWT_WRITE_ROLE", "")
+	v.SetDefault("TRACING_EXPORTER", "none")
+	v.SetDefault("TRACING_SERVICE_NAME", "articles-go")
+	v.SetDefault("TRACING_OTLP_ENDPOINT", "")
+	v.SetDefault("TRACING_FILE", "traces.jsonl")
+	v.SetDefault("TRACING_SAMPLE_RATIO", 1.0)
 
 	// load from config/default.yaml
 	v.AddConfigPath("config")
@@ -61,6 +213,29 @@ func Load() (*Config, error) {
 	if err := v.Unmarshal(&cfg); err != nil {
 		return nil, fmt.Errorf("unable to decode into struct: %w", err)
 	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
 
 	return &cfg, nil
 }
+
+// validate rejects settings the application can't run with.
+func (c *Config) validate() error {
+	if c.AuthEnabled && c.JWTSecret == "" && c.JWTPublicKeyFile == "" && c.JWTJWKSFile == "" {
+		return errors.New("AUTH_ENABLED requires JWT_SECRET, JWT_PUBLIC_KEY_FILE or JWT_JWKS_FILE; " +
+			"set AUTH_ENABLED=false to run without authentication")
+	}
+	if c.SchedulerEnabled {
+		if c.SchedulerInterval <= 0 {
+			return fmt.Errorf("SCHEDULER_INTERVAL must be positive, got %s", c.SchedulerInterval)
+		}
+		if c.SchedulerBatchSize <= 0 {
+			return fmt.Errorf("SCHEDULER_BATCH_SIZE must be positive, got %d", c.SchedulerBatchSize)
+		}
+	}
+	if c.IdempotencySweepInterval <= 0 {
+		return fmt.Errorf("IDEMPOTENCY_SWEEP_INTERVAL must be positive, got %s", c.IdempotencySweepInterval)
+	}
+	return nil
+}
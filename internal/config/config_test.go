@@ -3,11 +3,20 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+// testJWTSecret satisfies the key authentication requires by default.
+const testJWTSecret = "test-secret"
+
+func TestMain(m *testing.M) {
+	_ = os.Setenv("JWT_SECRET", testJWTSecret)
+	os.Exit(m.Run())
+}
+
 func TestLoadConfigSuccessfully(t *testing.T) {
 	cfg, err := Load()
 
@@ -46,3 +55,140 @@ func TestLoadConfigUsesDefaultsWhenNoConfigFile(t *testing.T) {
 	assert.Equal(t, "production", cfg.AppEnv)
 	assert.Equal(t, "3000", cfg.HTTPPort)
 }
+
+func TestLoadConfigParsesSoftDeleteRetention(t *testing.T) {
+	_ = os.Setenv("SOFT_DELETE_RETENTION", "48h")
+	defer func() {
+		_ = os.Unsetenv("SOFT_DELETE_RETENTION")
+	}()
+
+	cfg, err := Load()
+
+	require.NoError(t, err)
+	assert.Equal(t, 48*time.Hour, cfg.SoftDeleteRetention)
+}
+
+func TestLoadConfigParsesIdempotencyTTL(t *testing.T) {
+	_ = os.Setenv("IDEMPOTENCY_TTL", "2h")
+	defer func() {
+		_ = os.Unsetenv("IDEMPOTENCY_TTL")
+	}()
+
+	cfg, err := Load()
+
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Hour, cfg.IdempotencyTTL)
+}
+
+func TestLoadConfigSelectsSQLiteStorage(t *testing.T) {
+	_ = os.Setenv("STORAGE_DRIVER", "sqlite")
+	_ = os.Setenv("SQLITE_PATH", "/var/lib/articles/articles.db")
+	defer func() {
+		_ = os.Unsetenv("STORAGE_DRIVER")
+		_ = os.Unsetenv("SQLITE_PATH")
+	}()
+
+	cfg, err := Load()
+
+	require.NoError(t, err)
+	assert.Equal(t, "sqlite", cfg.StorageDriver)
+	assert.Equal(t, "/var/lib/articles/articles.db", cfg.SQLitePath)
+}
+
+func TestLoadConfigSelectsStorageDriver(t *testing.T) {
+	_ = os.Setenv("STORAGE_DRIVER", "memory")
+	defer func() {
+		_ = os.Unsetenv("STORAGE_DRIVER")
+	}()
+
+	cfg, err := Load()
+
+	require.NoError(t, err)
+	assert.Equal(t, "memory", cfg.StorageDriver)
+}
+
+func TestLoadConfigSelectsTracingExporter(t *testing.T) {
+	_ = os.Setenv("TRACING_EXPORTER", "otlp")
+	_ = os.Setenv("TRACING_OTLP_ENDPOINT", "http://otel-collector:4318/v1/traces")
+	_ = os.Setenv("TRACING_SAMPLE_RATIO", "0.25")
+	defer func() {
+		_ = os.Unsetenv("TRACING_EXPORTER")
+		_ = os.Unsetenv("TRACING_OTLP_ENDPOINT")
+		_ = os.Unsetenv("TRACING_SAMPLE_RATIO")
+	}()
+
+	cfg, err := Load()
+
+	require.NoError(t, err)
+	assert.Equal(t, "otlp", cfg.TracingExporter)
+	assert.Equal(t, "http://otel-collector:4318/v1/traces", cfg.TracingOTLPEndpoint)
+	assert.Equal(t, 0.25, cfg.TracingSampleRatio)
+	assert.Equal(t, "articles-go", cfg.TracingServiceName)
+}
+
+func TestLoadConfigEnablesAuthByDefault(t *testing.T) {
+	cfg, err := Load()
+
+	require.NoError(t, err)
+	assert.True(t, cfg.AuthEnabled)
+}
+
+func TestLoadConfigRequiresJWTKeyWhenAuthIsEnabled(t *testing.T) {
+	_ = os.Unsetenv("JWT_SECRET")
+	defer func() {
+		_ = os.Setenv("JWT_SECRET", testJWTSecret)
+	}()
+
+	_, err := Load()
+	assert.ErrorContains(t, err, "AUTH_ENABLED requires")
+
+	_ = os.Setenv("AUTH_ENABLED", "false")
+	defer func() {
+		_ = os.Unsetenv("AUTH_ENABLED")
+	}()
+
+	_, err = Load()
+	assert.NoError(t, err)
+}
+
+func TestLoadConfigRejectsNonPositiveSchedulerSettings(t *testing.T) {
+	for name, value := range map[string]string{
+		"SCHEDULER_INTERVAL":   "0s",
+		"SCHEDULER_BATCH_SIZE": "-1",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_ = os.Setenv(name, value)
+			defer func() {
+				_ = os.Unsetenv(name)
+			}()
+
+			_, err := Load()
+
+			assert.ErrorContains(t, err, name)
+		})
+	}
+}
+
+func TestLoadConfigIgnoresSchedulerSettingsWhenDisabled(t *testing.T) {
+	_ = os.Setenv("SCHEDULER_ENABLED", "false")
+	_ = os.Setenv("SCHEDULER_INTERVAL", "0s")
+	defer func() {
+		_ = os.Unsetenv("SCHEDULER_ENABLED")
+		_ = os.Unsetenv("SCHEDULER_INTERVAL")
+	}()
+
+	_, err := Load()
+
+	assert.NoError(t, err)
+}
+
+func TestLoadConfigRejectsNonPositiveSweepInterval(t *testing.T) {
+	_ = os.Setenv("IDEMPOTENCY_SWEEP_INTERVAL", "0s")
+	defer func() {
+		_ = os.Unsetenv("IDEMPOTENCY_SWEEP_INTERVAL")
+	}()
+
+	_, err := Load()
+
+	assert.ErrorContains(t, err, "IDEMPOTENCY_SWEEP_INTERVAL")
+}
@@ -2,17 +2,166 @@ package dto
 
 import "time"
 
+// CreateArticleRequest registers a new article. Titles and summaries are
+// trimmed before they are stored; the rules apply to the text as sent.
 type CreateArticleRequest struct {
-	Title string `json:"title" binding:"required"`
+	Title   string `json:"title" binding:"required,notblank,max=200,singleline"`
+	Body    string `json:"body" binding:"max=100000"`
+	Summary string `json:"summary" binding:"max=500"`
+	// AuthorID defaults to the caller's author profile.
+	// Only admins may create articles on behalf of another author.
+	AuthorID *uint `json:"author_id" binding:"omitempty,min=1"`
+	// Tags and Categories are created on first use.
+	Tags       []string `json:"tags" binding:"max=20,dive,notblank,max=50,label"`
+	Categories []string `json:"categories" binding:"max=20,dive,notblank,max=50,label"`
+	// Language is the text search configuration the article is indexed
+	// with, e.g. "english" or "german". It defaults to english.
+	Language string `json:"language" binding:"omitempty,max=32,singleline"`
 }
 
+// UpdateArticleRequest is the full representation accepted by PUT and
+// the document JSON patches are applied to.
+type UpdateArticleRequest struct {
+	Title      string   `json:"title" binding:"required,notblank,max=200,singleline"`
+	Body       string   `json:"body" binding:"max=100000"`
+	Summary    string   `json:"summary" binding:"max=500"`
+	Tags       []string `json:"tags" binding:"max=20,dive,notblank,max=50,label"`
+	Categories []string `json:"categories" binding:"max=20,dive,notblank,max=50,label"`
+}
+
+// ScheduleArticleRequest queues an article under review for publication.
+type ScheduleArticleRequest struct {
+	PublishAt time.Time `json:"publish_at" binding:"required"`
+}
+
+// Content types accepted by the PATCH endpoint.
+const (
+	MergePatchContentType = "application/merge-patch+json"
+	JSONPatchContentType  = "application/json-patch+json"
+)
+
 type CreateArticleResponse struct {
 	ID        uint      `json:"id"`
+	Slug      string    `json:"slug"`
 	CreatedAt time.Time `json:"created_at"`
+	Version   uint      `json:"version"`
 }
 
+// ArticleFormat selects which renderings of the body an ArticleResponse carries.
+type ArticleFormat string
+
+const (
+	// FormatRaw returns only the Markdown source.
+	FormatRaw ArticleFormat = "raw"
+	// FormatHTML returns only the sanitized HTML rendering.
+	FormatHTML ArticleFormat = "html"
+	// FormatBoth returns the Markdown source and the HTML rendering.
+	FormatBoth ArticleFormat = "both"
+)
+
 type ArticleResponse struct {
-	ID        uint      `json:"id"`
-	Title     string    `json:"title"`
-	CreatedAt time.Time `json:"created_at"`
+	ID          uint       `json:"id"`
+	Title       string     `json:"title"`
+	Slug        string     `json:"slug"`
+	AuthorID    *uint      `json:"author_id,omitempty"`
+	Summary     string     `json:"summary"`
+	Excerpt     string     `json:"excerpt"`
+	Body        string     `json:"body,omitempty"`
+	BodyHTML    string     `json:"body_html,omitempty"`
+	Tags        []string   `json:"tags"`
+	Categories  []string   `json:"categories"`
+	Language    string     `json:"language,omitempty"`
+	Status      string     `json:"status"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+	PublishAt   *time.Time `json:"publish_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+	Version     uint       `json:"version"`
+}
+
+// PurgeArticlesResponse reports the outcome of a hard purge.
+type PurgeArticlesResponse struct {
+	Purged        int64     `json:"purged"`
+	DeletedBefore time.Time `json:"deleted_before"`
+}
+
+// ListArticlesRequest holds the query parameters accepted by the article listing endpoint.
+type ListArticlesRequest struct {
+	Limit       int       `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset      int       `form:"offset" binding:"omitempty,min=0"`
+	Cursor      string    `form:"cursor"`
+	Title       string    `form:"title"`
+	AuthorID    uint      `form:"author_id" binding:"omitempty,min=1"`
+	CreatedFrom time.Time `form:"created_from" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedTo   time.Time `form:"created_to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Sort        string    `form:"sort" binding:"omitempty,oneof=created_at -created_at id -id"`
+
+	// Tags may be repeated or comma separated; TagMatch combines them.
+	Tags     []string `form:"tag"`
+	TagMatch string   `form:"tag_match" binding:"omitempty,oneof=any all"`
+	Category string   `form:"category"`
+
+	// Status defaults to published; other states are limited to the caller's
+	// own articles unless it is an admin.
+	Status string `form:"status" binding:"omitempty,oneof=draft in_review published archived"`
+
+	// IncludeDeleted also lists soft-deleted articles, with the same
+	// restriction as Status.
+	IncludeDeleted bool `form:"include_deleted"`
+}
+
+// ListMeta describes the page returned by a listing endpoint.
+type ListMeta struct {
+	Total      int64  `json:"total"`
+	Limit      int    `json:"limit"`
+	Offset     int    `json:"offset"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+type ListArticlesResponse struct {
+	Items []ArticleResponse `json:"items"`
+	Meta  ListMeta          `json:"meta"`
+}
+
+// WithFormat returns a copy of the response stripped down to the requested body format.
+func (r ArticleResponse) WithFormat(f ArticleFormat) ArticleResponse {
+	switch f {
+	case FormatRaw:
+		r.BodyHTML = ""
+	case FormatHTML:
+		r.Body = ""
+	}
+	return r
+}
+
+// TagCountResponse is a tag with the number of articles using it.
+type TagCountResponse struct {
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+type ListTagsResponse struct {
+	Items []TagCountResponse `json:"items"`
+}
+
+// SearchArticlesRequest holds the query parameters accepted by the search endpoint.
+type SearchArticlesRequest struct {
+	Query    string `form:"q" binding:"required"`
+	Language string `form:"lang"`
+	Limit    int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset   int    `form:"offset" binding:"omitempty,min=0"`
+}
+
+// SearchHitResponse is an article matching a search. Headline is an HTML
+// snippet of the body with the matches wrapped in <mark> elements.
+type SearchHitResponse struct {
+	ArticleResponse
+	Rank     float64 `json:"rank"`
+	Headline string  `json:"headline"`
+}
+
+type SearchArticlesResponse struct {
+	Items []SearchHitResponse `json:"items"`
+	Meta  ListMeta            `json:"meta"`
 }
@@ -0,0 +1,41 @@
+package dto
+
+import "time"
+
+// CreateAuthorRequest registers a new author. Subject links the author to a
+// token subject; it defaults to the caller's own subject and only admins may
+// register authors for someone else.
+type CreateAuthorRequest struct {
+	Name    string `json:"name" binding:"required,notblank,max=100,singleline"`
+	Email   string `json:"email" binding:"required,email,max=254"`
+	Bio     string `json:"bio" binding:"max=2000"`
+	Subject string `json:"subject" binding:"max=255,singleline"`
+}
+
+type UpdateAuthorRequest struct {
+	Name  string `json:"name" binding:"required,notblank,max=100,singleline"`
+	Email string `json:"email" binding:"required,email,max=254"`
+	Bio   string `json:"bio" binding:"max=2000"`
+}
+
+type AuthorResponse struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+	// Email is only shown to the author themself and to admins.
+	Email     string    `json:"email,omitempty"`
+	Bio       string    `json:"bio"`
+	Subject   string    `json:"subject,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ListAuthorsRequest holds the query parameters accepted by the author list endpoint.
+type ListAuthorsRequest struct {
+	Limit  int `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset int `form:"offset" binding:"omitempty,min=0"`
+}
+
+type ListAuthorsResponse struct {
+	Items []AuthorResponse `json:"items"`
+	Meta  ListMeta         `json:"meta"`
+}
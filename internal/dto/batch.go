@@ -0,0 +1,44 @@
+package dto
+
+import "encoding/json"
+
+// Operations of a batch.
+const (
+	BatchCreate = "create"
+	BatchUpdate = "update"
+	BatchDelete = "delete"
+)
+
+// BatchRequest applies several article operations at once. Atomic batches
+// either apply every operation or none of them.
+type BatchRequest struct {
+	Atomic     bool             `json:"atomic"`
+	Operations []BatchOperation `json:"operations" binding:"required,min=1,max=500,dive"`
+}
+
+// BatchOperation is one operation of a batch. Article holds a
+// CreateArticleRequest for creates and an UpdateArticleRequest for updates.
+// Updates and deletes name the article and the version they are based on,
+// like the If-Match header of the single article endpoints.
+type BatchOperation struct {
+	Op      string          `json:"op" binding:"required,oneof=create update delete"`
+	ID      uint            `json:"id" binding:"required_unless=Op create"`
+	Version uint            `json:"version" binding:"required_unless=Op create"`
+	Article json.RawMessage `json:"article" binding:"required_unless=Op delete"`
+}
+
+// BatchResult is the outcome of the operation at Index of a batch.
+// ID, Slug and Version describe the article after a successful operation.
+type BatchResult struct {
+	Index   int    `json:"index"`
+	Op      string `json:"op"`
+	ID      uint   `json:"id,omitempty"`
+	Slug    string `json:"slug,omitempty"`
+	Version uint   `json:"version,omitempty"`
+	// Err is the reason the operation failed, nil on success.
+	Err error `json:"-"`
+}
+
+type BatchResponse struct {
+	Items []BatchResult `json:"items"`
+}
@@ -0,0 +1,40 @@
+package dto
+
+import "time"
+
+// RevisionResponse is an article as of one of its versions.
+// Listings leave out the body.
+type RevisionResponse struct {
+	Version    uint      `json:"version"`
+	Title      string    `json:"title"`
+	Summary    string    `json:"summary"`
+	Body       string    `json:"body,omitempty"`
+	Status     string    `json:"status"`
+	Tags       []string  `json:"tags"`
+	Categories []string  `json:"categories"`
+	CreatedBy  string    `json:"created_by,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type ListRevisionsRequest struct {
+	Limit  int `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset int `form:"offset" binding:"omitempty,min=0"`
+}
+
+type ListRevisionsResponse struct {
+	Items []RevisionResponse `json:"items"`
+	Meta  ListMeta           `json:"meta"`
+}
+
+// RevisionDiffRequest selects the two revisions to compare.
+type RevisionDiffRequest struct {
+	From uint `form:"from" binding:"required,min=1"`
+	To   uint `form:"to" binding:"required,min=1"`
+}
+
+// RevisionDiffResponse is a unified diff turning revision From into revision To.
+type RevisionDiffResponse struct {
+	From uint   `json:"from"`
+	To   uint   `json:"to"`
+	Diff string `json:"diff"`
+}
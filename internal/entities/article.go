@@ -2,11 +2,47 @@ package entities
 
 import (
 	"time"
+
+	"gorm.io/gorm"
 )
 
 // Article represents simple article entity.
 type Article struct {
-	ID        uint      `gorm:"primaryKey" json:"id"`
-	Title     string    `gorm:"not null" json:"title"`
-	CreatedAt time.Time `json:"created_at"`
+	ID    uint   `gorm:"primaryKey" json:"id"`
+	Title string `gorm:"not null" json:"title"`
+	// Slug identifies the article in URLs. It is derived from the title and
+	// unique among current and former slugs of all articles.
+	Slug string `gorm:"not null;uniqueIndex" json:"slug"`
+	// AuthorID references the owning Author. Articles created before
+	// authors existed have no owner and can only be modified by admins.
+	AuthorID *uint `gorm:"index" json:"author_id"`
+
+	// Body is the Markdown source of the article.
+	Body    string `gorm:"type:text" json:"body"`
+	Summary string `json:"summary"`
+	// Excerpt is a plain-text preview generated from Body.
+	Excerpt string `json:"excerpt"`
+	// BodyHTML caches the sanitized HTML rendering of Body.
+	BodyHTML string `gorm:"column:body_html;type:text" json:"body_html"`
+	// Language is the Postgres text search configuration the article is indexed with.
+	Language string `gorm:"type:regconfig;default:english" json:"language"`
+
+	// Status is the publication state; only published articles are public.
+	Status ArticleStatus `gorm:"type:varchar(16);not null;default:draft;index" json:"status"`
+	// PublishedAt is set when the article is published.
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+	// PublishAt schedules an article under review to be published
+	// automatically once the time has come.
+	PublishAt *time.Time `json:"publish_at,omitempty"`
+
+	Tags       []Tag      `gorm:"many2many:article_tags" json:"tags,omitempty"`
+	Categories []Category `gorm:"many2many:article_categories" json:"categories,omitempty"`
+
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// DeletedAt marks the article as soft-deleted; such rows are hidden from
+	// regular queries until restored or purged.
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+	// Version is incremented on every modification and backs optimistic locking.
+	Version uint `gorm:"not null;default:1" json:"version"`
 }
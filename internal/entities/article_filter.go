@@ -0,0 +1,81 @@
+package entities
+
+import (
+	"time"
+)
+
+// ArticleSort defines the ordering applied when listing articles.
+// A leading "-" means descending order.
+type ArticleSort string
+
+const (
+	SortCreatedAtAsc  ArticleSort = "created_at"
+	SortCreatedAtDesc ArticleSort = "-created_at"
+	SortIDAsc         ArticleSort = "id"
+	SortIDDesc        ArticleSort = "-id"
+)
+
+// Descending reports whether the sort order is descending.
+func (s ArticleSort) Descending() bool {
+	return len(s) > 0 && s[0] == '-'
+}
+
+// Field returns the column the sort order applies to.
+func (s ArticleSort) Field() string {
+	if s.Descending() {
+		return string(s[1:])
+	}
+	return string(s)
+}
+
+// TagMatch selects how multiple tags in a filter are combined.
+type TagMatch string
+
+const (
+	// TagMatchAny matches articles carrying at least one of the tags.
+	TagMatchAny TagMatch = "any"
+	// TagMatchAll matches articles carrying every one of the tags.
+	TagMatchAll TagMatch = "all"
+)
+
+// ArticleCursor points at the last article of a previously returned page.
+// Listing continues strictly after this position in the requested sort order.
+type ArticleCursor struct {
+	ID        uint
+	CreatedAt time.Time
+}
+
+// ArticleFilter describes which articles to list and how to page through them.
+type ArticleFilter struct {
+	// Title matches articles whose title contains the value, case-insensitively.
+	Title string
+
+	// AuthorID restricts the list to articles owned by an author. Zero is ignored.
+	AuthorID uint
+
+	// Status restricts the list to articles in the given publication state.
+	// An empty status matches every state.
+	Status ArticleStatus
+
+	// Tags restricts the list to articles carrying the given tags,
+	// combined according to TagMatch (any by default).
+	Tags     []string
+	TagMatch TagMatch
+
+	// Category restricts the list to articles in the given category.
+	Category string
+
+	// CreatedFrom and CreatedTo bound created_at (inclusive). Zero values are ignored.
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+
+	Sort   ArticleSort
+	Limit  int
+	Offset int
+
+	// After enables keyset pagination. When set, Offset is ignored.
+	After *ArticleCursor
+
+	// IncludeDeleted also returns soft-deleted articles.
+	IncludeDeleted bool
+}
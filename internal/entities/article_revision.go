@@ -0,0 +1,25 @@
+package entities
+
+import "time"
+
+// ArticleRevision is a snapshot of an article as of one of its versions.
+// A revision is recorded in the same transaction as every change that
+// increments the article version, so Version identifies the revision.
+type ArticleRevision struct {
+	ID        uint `gorm:"primaryKey" json:"id"`
+	ArticleID uint `gorm:"not null;uniqueIndex:idx_article_revisions_version" json:"article_id"`
+	Version   uint `gorm:"not null;uniqueIndex:idx_article_revisions_version" json:"version"`
+
+	Title   string        `gorm:"not null" json:"title"`
+	Summary string        `json:"summary"`
+	Body    string        `gorm:"type:text" json:"body"`
+	Status  ArticleStatus `gorm:"type:varchar(16)" json:"status"`
+	// Tags and Categories hold the label names at the time of the revision.
+	Tags       []string `gorm:"type:jsonb;serializer:json" json:"tags"`
+	Categories []string `gorm:"type:jsonb;serializer:json" json:"categories"`
+
+	// CreatedBy is the subject that made the change; empty for changes made
+	// by the system or while authentication is disabled.
+	CreatedBy string    `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
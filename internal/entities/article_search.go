@@ -0,0 +1,30 @@
+package entities
+
+// ArticleSearch describes a full-text search over articles.
+type ArticleSearch struct {
+	// Query uses web search syntax: quoted phrases, "or" and -exclusions.
+	Query string
+	// Language is the text search configuration the query is parsed with.
+	Language string
+	// Status restricts the matches to articles in the given publication state.
+	Status ArticleStatus
+
+	Limit  int
+	Offset int
+}
+
+// ArticleSearchHit is an article matching a search, with its relevance and
+// a snippet of the body around the matches.
+type ArticleSearchHit struct {
+	Article Article
+	Rank    float64
+	// Headline marks matches with HeadlineStart and HeadlineStop.
+	// It is raw article text and must be escaped before rendering.
+	Headline string
+}
+
+// Markers ts_headline wraps around matched words.
+const (
+	HeadlineStart = "@@hl@@"
+	HeadlineStop  = "@@/hl@@"
+)
@@ -0,0 +1,11 @@
+package entities
+
+import "time"
+
+// ArticleSlug is a former slug of an article. It is kept when the slug
+// changes so that old links can be redirected to the current one.
+type ArticleSlug struct {
+	Slug      string    `gorm:"primaryKey" json:"slug"`
+	ArticleID uint      `gorm:"not null;index" json:"article_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,42 @@
+package entities
+
+// ArticleStatus is the publication state of an article.
+type ArticleStatus string
+
+const (
+	// StatusDraft is the state of new articles; only the author and admins see them.
+	StatusDraft ArticleStatus = "draft"
+	// StatusInReview marks an article submitted for publication.
+	StatusInReview ArticleStatus = "in_review"
+	// StatusPublished articles are visible to everyone.
+	StatusPublished ArticleStatus = "published"
+	// StatusArchived articles are withdrawn from public listings for good.
+	StatusArchived ArticleStatus = "archived"
+)
+
+// articleTransitions lists the states an article may move to from each state.
+// A review either publishes the article or sends it back to draft.
+var articleTransitions = map[ArticleStatus][]ArticleStatus{
+	StatusDraft:     {StatusInReview},
+	StatusInReview:  {StatusDraft, StatusPublished},
+	StatusPublished: {StatusArchived},
+}
+
+// Valid reports whether s is a known status.
+func (s ArticleStatus) Valid() bool {
+	switch s {
+	case StatusDraft, StatusInReview, StatusPublished, StatusArchived:
+		return true
+	}
+	return false
+}
+
+// CanTransitionTo reports whether the workflow allows moving from s to next.
+func (s ArticleStatus) CanTransitionTo(next ArticleStatus) bool {
+	for _, allowed := range articleTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,18 @@
+package entities
+
+import (
+	"time"
+)
+
+// Author is a person who writes and owns articles.
+type Author struct {
+	ID    uint   `gorm:"primaryKey" json:"id"`
+	Name  string `gorm:"not null" json:"name"`
+	Email string `gorm:"not null;uniqueIndex" json:"email"`
+	Bio   string `json:"bio"`
+	// Subject links the author to the "sub" claim of their access tokens.
+	Subject *string `gorm:"uniqueIndex" json:"subject,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
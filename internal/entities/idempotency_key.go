@@ -0,0 +1,27 @@
+package entities
+
+import "time"
+
+// IdempotencyKey records a request sent with an Idempotency-Key header and,
+// once it is handled, the response, so that retries get the same answer.
+type IdempotencyKey struct {
+	// Subject scopes keys to the authenticated caller that chose them. It is
+	// empty while authentication is disabled.
+	Subject string `gorm:"primaryKey"`
+	Key     string `gorm:"primaryKey"`
+	// RequestHash identifies the method, path and body of the request.
+	RequestHash string `gorm:"not null"`
+	// Status is the status of the stored response, 0 while the request is
+	// still being handled. CreatedAt tells such a reservation apart from
+	// the ones of retries taking it over.
+	Status    int
+	Header    map[string]string `gorm:"serializer:json"`
+	Body      []byte
+	CreatedAt time.Time
+	ExpiresAt time.Time `gorm:"not null;index"`
+}
+
+// Completed reports whether the response of the request was stored.
+func (k *IdempotencyKey) Completed() bool {
+	return k.Status != 0
+}
@@ -0,0 +1,21 @@
+package entities
+
+// Tag is a free-form keyword attached to articles.
+// Names are normalized to lower case and unique.
+type Tag struct {
+	ID   uint   `gorm:"primaryKey" json:"id"`
+	Name string `gorm:"not null;uniqueIndex" json:"name"`
+}
+
+// Category is a broad topic grouping articles.
+// Names are normalized to lower case and unique.
+type Category struct {
+	ID   uint   `gorm:"primaryKey" json:"id"`
+	Name string `gorm:"not null;uniqueIndex" json:"name"`
+}
+
+// TagCount is a tag together with the number of live articles using it.
+type TagCount struct {
+	Name  string
+	Count int64
+}
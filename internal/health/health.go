@@ -0,0 +1,162 @@
+// Package health runs dependency checks for readiness and health probes.
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// Messages reported for failed checks. Probes are unauthenticated, so the
+// underlying error is logged rather than returned to the caller.
+const (
+	errCheckFailed   = "check failed"
+	errCheckTimedOut = "check timed out"
+)
+
+// CheckFunc reports the health of a single dependency.
+// It must honor ctx cancellation; a nil error means healthy.
+type CheckFunc func(ctx context.Context) error
+
+// Result is the outcome of one check.
+type Result struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Report aggregates the results of all registered checks.
+type Report struct {
+	Status    string    `json:"status"`
+	CheckedAt time.Time `json:"checked_at"`
+	Checks    []Result  `json:"checks"`
+}
+
+// Healthy reports whether every check passed.
+func (r Report) Healthy() bool {
+	return r.Status == StatusOK
+}
+
+type namedCheck struct {
+	name  string
+	check CheckFunc
+}
+
+// Registry holds named checks and caches their combined report so frequent
+// probes from several sources don't hammer the dependencies.
+type Registry struct {
+	timeout time.Duration
+	ttl     time.Duration
+	now     func() time.Time
+	log     *zap.Logger
+
+	mu     sync.Mutex
+	checks []namedCheck
+	cached *Report
+}
+
+// NewRegistry creates a Registry that gives every check at most timeout to
+// complete and reuses a report for ttl. Failed checks are logged to log.
+func NewRegistry(timeout, ttl time.Duration, log *zap.Logger) *Registry {
+	return &Registry{
+		timeout: timeout,
+		ttl:     ttl,
+		now:     time.Now,
+		log:     log,
+	}
+}
+
+// Register adds a named check. Checks run in registration order.
+func (r *Registry) Register(name string, check CheckFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.checks = append(r.checks, namedCheck{name: name, check: check})
+	r.cached = nil
+}
+
+// Run returns the current report, executing the checks only if the cached
+// report is older than the configured TTL. Concurrent callers share one run.
+//
+// Checks run detached from ctx, so a caller that goes away does not fail the
+// report shared with everyone else. A report whose only failures stem from
+// ctx is returned but not cached.
+func (r *Registry) Run(ctx context.Context) Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.cached != nil && r.now().Sub(r.cached.CheckedAt) < r.ttl {
+		return *r.cached
+	}
+
+	report := Report{
+		Status:    StatusOK,
+		CheckedAt: r.now(),
+		Checks:    make([]Result, 0, len(r.checks)),
+	}
+
+	callerOnly := true
+	for _, c := range r.checks {
+		res, err := r.run(ctx, c)
+		if err != nil {
+			report.Status = StatusFail
+			if ctx.Err() == nil || !errors.Is(err, ctx.Err()) {
+				callerOnly = false
+			}
+		}
+		report.Checks = append(report.Checks, res)
+	}
+
+	if report.Healthy() || !callerOnly {
+		r.cached = &report
+	}
+	return report
+}
+
+func (r *Registry) run(parent context.Context, c namedCheck) (Result, error) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(parent), r.timeout)
+	defer cancel()
+
+	start := r.now()
+	err := c.check(ctx)
+	if err == nil && ctx.Err() != nil {
+		err = ctx.Err()
+	}
+
+	res := Result{
+		Name:      c.name,
+		Status:    StatusOK,
+		LatencyMS: float64(r.now().Sub(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		res.Status = StatusFail
+		res.Error = errCheckFailed
+		if errors.Is(err, context.DeadlineExceeded) {
+			res.Error = errCheckTimedOut
+		}
+		r.log.Warn("health check failed", zap.String("check", c.name), zap.Error(err))
+	}
+	return res, err
+}
+
+// DBCheck pings the database behind db.
+func DBCheck(db *gorm.DB) CheckFunc {
+	return func(ctx context.Context) error {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return fmt.Errorf("failed to get db handle: %w", err)
+		}
+		return sqlDB.PingContext(ctx)
+	}
+}
@@ -0,0 +1,155 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestRunReportsEveryCheck(t *testing.T) {
+	registry := NewRegistry(time.Second, 0, zap.NewNop())
+	registry.Register("ok", func(ctx context.Context) error { return nil })
+	registry.Register("broken", func(ctx context.Context) error { return errors.New("boom") })
+
+	report := registry.Run(context.Background())
+
+	assert.False(t, report.Healthy())
+	require.Len(t, report.Checks, 2)
+	assert.Equal(t, Result{Name: "ok", Status: StatusOK, LatencyMS: report.Checks[0].LatencyMS}, report.Checks[0])
+	assert.Equal(t, StatusFail, report.Checks[1].Status)
+	assert.Equal(t, "check failed", report.Checks[1].Error)
+}
+
+func TestRunLogsFailureDetailInsteadOfReturningIt(t *testing.T) {
+	core, logs := observer.New(zap.WarnLevel)
+	registry := NewRegistry(time.Second, 0, zap.New(core))
+	registry.Register("database", func(ctx context.Context) error {
+		return errors.New("password authentication failed for user \"articles\"")
+	})
+
+	report := registry.Run(context.Background())
+
+	assert.Equal(t, "check failed", report.Checks[0].Error)
+	require.Equal(t, 1, logs.Len())
+	entry := logs.All()[0]
+	assert.Equal(t, "database", entry.ContextMap()["check"])
+	assert.Contains(t, entry.ContextMap()["error"], "password authentication failed")
+}
+
+func TestRunWithoutChecksIsHealthy(t *testing.T) {
+	registry := NewRegistry(time.Second, 0, zap.NewNop())
+
+	report := registry.Run(context.Background())
+
+	assert.True(t, report.Healthy())
+	assert.Empty(t, report.Checks)
+}
+
+func TestRunTimesOutSlowChecks(t *testing.T) {
+	registry := NewRegistry(10*time.Millisecond, 0, zap.NewNop())
+	registry.Register("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	report := registry.Run(context.Background())
+
+	assert.False(t, report.Healthy())
+	assert.Equal(t, "check timed out", report.Checks[0].Error)
+}
+
+func TestRunIgnoresCallerCancellation(t *testing.T) {
+	registry := NewRegistry(time.Second, 0, zap.NewNop())
+	registry.Register("db", func(ctx context.Context) error { return ctx.Err() })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	report := registry.Run(ctx)
+
+	assert.True(t, report.Healthy())
+}
+
+func TestRunDoesNotCacheCallerContextFailures(t *testing.T) {
+	calls := 0
+	registry := NewRegistry(time.Second, time.Minute, zap.NewNop())
+	registry.Register("db", func(ctx context.Context) error {
+		calls++
+		if calls == 1 {
+			return context.Canceled
+		}
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	first := registry.Run(ctx)
+	second := registry.Run(context.Background())
+
+	assert.False(t, first.Healthy())
+	assert.True(t, second.Healthy())
+	assert.Equal(t, 2, calls)
+}
+
+func TestRunCachesGenuineFailures(t *testing.T) {
+	calls := 0
+	registry := NewRegistry(time.Second, time.Minute, zap.NewNop())
+	registry.Register("db", func(ctx context.Context) error {
+		calls++
+		return errors.New("down")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	registry.Run(ctx)
+	registry.Run(context.Background())
+
+	assert.Equal(t, 1, calls)
+}
+
+func TestRunCachesReportForTTL(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	calls := 0
+
+	registry := NewRegistry(time.Second, 5*time.Second, zap.NewNop())
+	registry.now = func() time.Time { return now }
+	registry.Register("counted", func(ctx context.Context) error {
+		calls++
+		return nil
+	})
+
+	registry.Run(context.Background())
+	now = now.Add(4 * time.Second)
+	registry.Run(context.Background())
+
+	assert.Equal(t, 1, calls)
+
+	now = now.Add(2 * time.Second)
+	registry.Run(context.Background())
+
+	assert.Equal(t, 2, calls)
+}
+
+func TestDBCheckPingsDatabase(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	require.NoError(t, err)
+	defer db.Close()
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{DisableAutomaticPing: true})
+	require.NoError(t, err)
+
+	mock.ExpectPing().WillReturnError(errors.New("connection refused"))
+
+	err = DBCheck(gormDB)(context.Background())
+
+	assert.EqualError(t, err, "connection refused")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
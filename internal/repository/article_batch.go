@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/antonchaban/articles-go/internal/entities"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// txKey is the context key under which Transaction stores its transaction.
+type txKey struct{}
+
+// Transaction runs fn in a database transaction, committed if fn returns nil
+// and rolled back otherwise. Repository calls made with the context passed
+// to fn join the transaction.
+func (r *PostgresRepo) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return translate(r.conn(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	}))
+}
+
+// conn returns the transaction started by Transaction for ctx, if any,
+// and the connection pool otherwise.
+func (r *PostgresRepo) conn(ctx context.Context) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return r.db.WithContext(ctx)
+}
+
+// CreateInBatches inserts articles like Create, batchSize rows per INSERT,
+// all in one transaction. Slugs are made unique among the new articles too.
+func (r *PostgresRepo) CreateInBatches(ctx context.Context, articles []*entities.Article, batchSize int) error {
+	if len(articles) == 0 {
+		return nil
+	}
+
+	// a failed attempt may have assigned ids to the rows of earlier batches
+	bases := make([]string, len(articles))
+	ids := make([]uint, len(articles))
+	for i, a := range articles {
+		bases[i], ids[i] = a.Slug, a.ID
+	}
+
+	err := retrySlugs(func() error {
+		return r.conn(ctx).Transaction(func(tx *gorm.DB) error {
+			reserved := make(map[string]bool, len(articles))
+			for i, a := range articles {
+				a.ID = ids[i]
+				slug, err := uniqueSlug(tx, bases[i], 0, reserved)
+				if err != nil {
+					return err
+				}
+				a.Slug = slug
+				reserved[slug] = true
+			}
+
+			if err := tx.Omit(clause.Associations).CreateInBatches(articles, batchSize).Error; err != nil {
+				return slugConflict(err)
+			}
+			for _, a := range articles {
+				if err := saveLabels(tx, a); err != nil {
+					return err
+				}
+				if err := recordRevision(ctx, tx, a); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		r.logger(ctx).Error("failed to create articles", zap.Int("count", len(articles)), zap.Error(err))
+		return translate(err)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/antonchaban/articles-go/internal/entities"
+	"github.com/antonchaban/articles-go/internal/services"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestCreateInBatchesKeepsSlugsUnique(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewPostgresRepo(db, zap.NewNop())
+
+	now := time.Now().UTC()
+	articles := []*entities.Article{
+		{Title: "Same", Slug: "same", CreatedAt: now, Version: 1},
+		{Title: "Same", Slug: "same", CreatedAt: now, Version: 1},
+	}
+
+	mock.ExpectBegin()
+	expectSlugLookup(mock)
+	expectSlugLookup(mock)
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "articles"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+	expectRevision(mock)
+	expectRevision(mock)
+	mock.ExpectCommit()
+
+	err := repo.CreateInBatches(context.Background(), articles, 100)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "same", articles[0].Slug)
+	assert.Equal(t, "same-2", articles[1].Slug)
+	assert.Equal(t, uint(2), articles[1].ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransactionSharesTransactionWithRepositoryCalls(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewPostgresRepo(db, zap.NewNop())
+
+	// the deletes run in savepoints of the one transaction
+	mock.ExpectBegin()
+	mock.ExpectExec(`SAVEPOINT`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "articles" SET "deleted_at"=$1`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "articles" WHERE "articles"."id" = $1`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "version"}).AddRow(1, "Gone", 2))
+	expectNoLabels(mock)
+	expectRevision(mock)
+	mock.ExpectExec(`SAVEPOINT`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "articles" SET "deleted_at"=$1`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`ROLLBACK TO SAVEPOINT`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err := repo.Transaction(context.Background(), func(ctx context.Context) error {
+		if err := repo.Delete(ctx, 1, 1); err != nil {
+			return err
+		}
+		return repo.Delete(ctx, 2, 1)
+	})
+
+	assert.True(t, errors.Is(err, services.ErrNotFound))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
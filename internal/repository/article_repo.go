@@ -3,11 +3,16 @@ package repository
 import (
 	"context"
 	"errors"
+	"strings"
+	"time"
 
 	"github.com/antonchaban/articles-go/internal/entities"
+	"github.com/antonchaban/articles-go/internal/log"
+	"github.com/antonchaban/articles-go/internal/services"
 
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // PostgresRepo implements the ArticleRepository interface using PostgreSQL as the data store.
@@ -23,25 +28,501 @@ func NewPostgresRepo(db *gorm.DB, logger *zap.Logger) *PostgresRepo {
 	}
 }
 
-// Create inserts a new article into the database.
+// logger returns the logger of r annotated with the trace of ctx.
+func (r *PostgresRepo) logger(ctx context.Context) *zap.Logger {
+	return log.WithTrace(ctx, r.log)
+}
+
+// Create inserts a new article into the database together with its tags
+// and categories and records its first revision. Unknown tags and
+// categories are created on the fly. a.Slug is suffixed with a number
+// when another article already uses it, or takes it concurrently.
 func (r *PostgresRepo) Create(ctx context.Context, a *entities.Article) error {
-	if err := r.db.WithContext(ctx).Create(a).Error; err != nil {
-		r.log.Error("failed to create article", zap.Error(err))
-		return err
+	base := a.Slug
+	err := retrySlugs(func() error {
+		return r.conn(ctx).Transaction(func(tx *gorm.DB) error {
+			slug, err := uniqueSlug(tx, base, 0, nil)
+			if err != nil {
+				return err
+			}
+			a.Slug = slug
+
+			if err := tx.Omit(clause.Associations).Create(a).Error; err != nil {
+				return slugConflict(err)
+			}
+			if err := saveLabels(tx, a); err != nil {
+				return err
+			}
+			return recordRevision(ctx, tx, a)
+		})
+	})
+	if err != nil {
+		r.logger(ctx).Error("failed to create article", zap.Error(err))
+		return translate(err)
 	}
 	return nil
 }
 
 // GetByID retrieves an article by its ID from the database.
-func (r *PostgresRepo) GetByID(ctx context.Context, id uint) (*entities.Article, error) {
+// Soft-deleted articles are only returned when includeDeleted is set.
+func (r *PostgresRepo) GetByID(ctx context.Context, id uint, includeDeleted bool) (*entities.Article, error) {
 	var a entities.Article
-	if err := r.db.WithContext(ctx).First(&a, id).Error; err != nil {
+	q := r.conn(ctx)
+	if includeDeleted {
+		q = q.Unscoped()
+	}
+	if err := withLabels(q).First(&a, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			r.log.Warn("article not found", zap.Int("id", int(id)))
-			return nil, err
+			r.logger(ctx).Warn("article not found", zap.Int("id", int(id)))
+			return nil, services.ErrNotFound
 		}
-		r.log.Error("database query failed", zap.Int("id", int(id)), zap.Error(err))
-		return nil, err
+		r.logger(ctx).Error("database query failed", zap.Int("id", int(id)), zap.Error(err))
+		return nil, translate(err)
 	}
 	return &a, nil
 }
+
+// Update overwrites all mutable columns of an existing article, provided its
+// stored version still equals a.Version. On success a.Version is incremented.
+// Ownership (author_id) and the publication state are not changed by
+// updates, see UpdateStatus; tags and categories are replaced by the ones set on a.
+// A changed slug is made unique as on Create and the previous one is kept
+// for redirects. The new state is recorded as a revision.
+// Returns services.ErrNotFound if no article with the given ID and version exists.
+func (r *PostgresRepo) Update(ctx context.Context, a *entities.Article) error {
+	expected := a.Version
+	a.Version++
+
+	err := r.conn(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := renameSlug(tx, a); err != nil {
+			return err
+		}
+
+		res := tx.Model(a).
+			Where("version = ?", expected).
+			Select("*").Omit("id", "author_id", "status", "published_at", "publish_at", "created_at", "deleted_at", clause.Associations).
+			Updates(a)
+		if res.Error != nil {
+			r.logger(ctx).Error("failed to update article", zap.Uint("id", a.ID), zap.Error(res.Error))
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			r.logger(ctx).Warn("article not found for update", zap.Uint("id", a.ID), zap.Uint("version", expected))
+			return services.ErrNotFound
+		}
+
+		if err := tx.Exec("DELETE FROM article_tags WHERE article_id = ?", a.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM article_categories WHERE article_id = ?", a.ID).Error; err != nil {
+			return err
+		}
+		if err := saveLabels(tx, a); err != nil {
+			return err
+		}
+		return recordRevision(ctx, tx, a)
+	})
+	if err != nil {
+		a.Version = expected
+		return translate(err)
+	}
+	return nil
+}
+
+// Delete soft-deletes an article with the given version, increments its
+// version and records the deleted state as a revision.
+// Returns services.ErrNotFound if no such article exists or it is already deleted.
+func (r *PostgresRepo) Delete(ctx context.Context, id uint, version uint) error {
+	err := r.conn(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&entities.Article{}).
+			Where("id = ? AND version = ?", id, version).
+			Updates(map[string]any{
+				"deleted_at": tx.NowFunc(),
+				"version":    gorm.Expr("version + 1"),
+			})
+		if res.Error != nil {
+			r.logger(ctx).Error("failed to delete article", zap.Uint("id", id), zap.Error(res.Error))
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			r.logger(ctx).Warn("article not found for delete", zap.Uint("id", id))
+			return services.ErrNotFound
+		}
+
+		var a entities.Article
+		if err := withLabels(tx.Unscoped()).First(&a, id).Error; err != nil {
+			return err
+		}
+		return recordRevision(ctx, tx, &a)
+	})
+	return translate(err)
+}
+
+// Restore clears the deletion mark of a soft-deleted article with the given
+// version, increments its version and records the restored state as a revision.
+// Returns services.ErrNotFound if there is no such deleted article.
+func (r *PostgresRepo) Restore(ctx context.Context, id uint, version uint) error {
+	err := r.conn(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Unscoped().Model(&entities.Article{}).
+			Where("id = ? AND version = ? AND deleted_at IS NOT NULL", id, version).
+			Updates(map[string]any{
+				"deleted_at": nil,
+				"version":    gorm.Expr("version + 1"),
+			})
+		if res.Error != nil {
+			r.logger(ctx).Error("failed to restore article", zap.Uint("id", id), zap.Error(res.Error))
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			r.logger(ctx).Warn("deleted article not found for restore", zap.Uint("id", id))
+			return services.ErrNotFound
+		}
+
+		var a entities.Article
+		if err := withLabels(tx).First(&a, id).Error; err != nil {
+			return err
+		}
+		return recordRevision(ctx, tx, &a)
+	})
+	return translate(err)
+}
+
+// UpdateStatus persists the publication state of an article (status,
+// published_at and publish_at), provided its stored version still equals
+// a.Version, and records it as a revision; a must carry its tags and categories.
+// On success a.Version is incremented.
+// Returns services.ErrNotFound if no article with the given ID and version exists.
+func (r *PostgresRepo) UpdateStatus(ctx context.Context, a *entities.Article) error {
+	expected := a.Version
+	a.Version++
+
+	err := r.conn(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&entities.Article{}).
+			Where("id = ? AND version = ?", a.ID, expected).
+			Updates(map[string]any{
+				"status":       a.Status,
+				"published_at": a.PublishedAt,
+				"publish_at":   a.PublishAt,
+				"version":      gorm.Expr("version + 1"),
+			})
+		if res.Error != nil {
+			r.logger(ctx).Error("failed to update article status", zap.Uint("id", a.ID), zap.Error(res.Error))
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			r.logger(ctx).Warn("article not found for status update", zap.Uint("id", a.ID), zap.Uint("version", expected))
+			return services.ErrNotFound
+		}
+		return recordRevision(ctx, tx, a)
+	})
+	if err != nil {
+		a.Version = expected
+		return translate(err)
+	}
+	return nil
+}
+
+// PublishDue publishes up to limit articles under review whose publish_at is
+// not after now, records a revision for each and returns their IDs. Due rows are claimed with
+// FOR UPDATE SKIP LOCKED, so concurrent callers never publish the same article.
+func (r *PostgresRepo) PublishDue(ctx context.Context, now time.Time, limit int) ([]uint, error) {
+	var ids []uint
+	err := r.conn(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&entities.Article{}).
+			Where("status = ? AND publish_at <= ?", entities.StatusInReview, now).
+			Order("publish_at").
+			Limit(limit).
+			Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate, Options: clause.LockingOptionsSkipLocked}).
+			Pluck("id", &ids).Error
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+
+		err = tx.Model(&entities.Article{}).
+			Where("id IN ?", ids).
+			Updates(map[string]any{
+				"status":       entities.StatusPublished,
+				"published_at": now,
+				"publish_at":   nil,
+				"version":      gorm.Expr("version + 1"),
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		var published []entities.Article
+		if err := withLabels(tx).Find(&published, ids).Error; err != nil {
+			return err
+		}
+		for i := range published {
+			if err := recordRevision(ctx, tx, &published[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		r.logger(ctx).Error("failed to publish due articles", zap.Error(err))
+		return nil, translate(err)
+	}
+	return ids, nil
+}
+
+// CountScheduled returns the number of live articles waiting for their publish_at.
+func (r *PostgresRepo) CountScheduled(ctx context.Context) (int64, error) {
+	var n int64
+	err := r.conn(ctx).Model(&entities.Article{}).
+		Where("status = ? AND publish_at IS NOT NULL", entities.StatusInReview).
+		Count(&n).Error
+	if err != nil {
+		r.logger(ctx).Error("failed to count scheduled articles", zap.Error(err))
+		return 0, translate(err)
+	}
+	return n, nil
+}
+
+// Purge permanently removes articles soft-deleted before the given time
+// and returns how many rows were removed.
+func (r *PostgresRepo) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	res := r.conn(ctx).Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", deletedBefore).
+		Delete(&entities.Article{})
+	if res.Error != nil {
+		r.logger(ctx).Error("failed to purge articles", zap.Error(res.Error))
+		return 0, translate(res.Error)
+	}
+	return res.RowsAffected, nil
+}
+
+// List returns a page of articles matching the filter together with the
+// total number of matching articles, ignoring pagination.
+func (r *PostgresRepo) List(ctx context.Context, f entities.ArticleFilter) ([]entities.Article, int64, error) {
+	var total int64
+	if err := r.filtered(ctx, f).Count(&total).Error; err != nil {
+		r.logger(ctx).Error("failed to count articles", zap.Error(err))
+		return nil, 0, translate(err)
+	}
+
+	q := r.filtered(ctx, f)
+
+	if f.Sort == "" {
+		f.Sort = entities.SortCreatedAtDesc
+	}
+	field := f.Sort.Field()
+	dir, cmp := "ASC", ">"
+	if f.Sort.Descending() {
+		dir, cmp = "DESC", "<"
+	}
+
+	if f.After != nil {
+		if field == "id" {
+			q = q.Where("id "+cmp+" ?", f.After.ID)
+		} else {
+			q = q.Where("(created_at, id) "+cmp+" (?, ?)", f.After.CreatedAt, f.After.ID)
+		}
+	} else if f.Offset > 0 {
+		q = q.Offset(f.Offset)
+	}
+
+	if field != "id" {
+		q = q.Order(field + " " + dir)
+	}
+	q = q.Order("id " + dir)
+
+	if f.Limit > 0 {
+		q = q.Limit(f.Limit)
+	}
+
+	var articles []entities.Article
+	if err := withLabels(q).Find(&articles).Error; err != nil {
+		r.logger(ctx).Error("failed to list articles", zap.Error(err))
+		return nil, 0, translate(err)
+	}
+	return articles, total, nil
+}
+
+// filtered builds the base query shared by List and its count.
+func (r *PostgresRepo) filtered(ctx context.Context, f entities.ArticleFilter) *gorm.DB {
+	q := r.conn(ctx).Model(&entities.Article{})
+	if f.IncludeDeleted {
+		q = q.Unscoped()
+	}
+	if f.Title != "" {
+		q = q.Where(`LOWER(title) LIKE ? ESCAPE '\'`, "%"+escapeLike(strings.ToLower(f.Title))+"%")
+	}
+	if f.AuthorID != 0 {
+		q = q.Where("author_id = ?", f.AuthorID)
+	}
+	if f.Status != "" {
+		q = q.Where("status = ?", f.Status)
+	}
+	if len(f.Tags) > 0 {
+		sub := r.db.Table("article_tags").
+			Select("article_tags.article_id").
+			Joins("JOIN tags ON tags.id = article_tags.tag_id").
+			Where("tags.name IN ?", f.Tags)
+		if f.TagMatch == entities.TagMatchAll {
+			sub = sub.Group("article_tags.article_id").
+				Having("COUNT(DISTINCT tags.id) = ?", len(f.Tags))
+		}
+		q = q.Where("articles.id IN (?)", sub)
+	}
+	if f.Category != "" {
+		sub := r.db.Table("article_categories").
+			Select("article_categories.article_id").
+			Joins("JOIN categories ON categories.id = article_categories.category_id").
+			Where("categories.name = ?", f.Category)
+		q = q.Where("articles.id IN (?)", sub)
+	}
+	if !f.CreatedFrom.IsZero() {
+		q = q.Where("created_at >= ?", f.CreatedFrom)
+	}
+	if !f.CreatedTo.IsZero() {
+		q = q.Where("created_at <= ?", f.CreatedTo)
+	}
+	return q
+}
+
+// headlineOptions configures the body snippets returned by Search.
+var headlineOptions = "StartSel=" + entities.HeadlineStart + ", StopSel=" + entities.HeadlineStop +
+	", MaxFragments=2, MaxWords=30, MinWords=10, FragmentDelimiter=\" … \""
+
+// Search returns live articles matching the query, most relevant first,
+// together with the total number of matches.
+func (r *PostgresRepo) Search(ctx context.Context, s entities.ArticleSearch) ([]entities.ArticleSearchHit, int64, error) {
+	tsquery := gorm.Expr("websearch_to_tsquery(?::regconfig, ?)", s.Language, s.Query)
+	matching := func() *gorm.DB {
+		q := r.conn(ctx).Model(&entities.Article{}).Where("search_vector @@ ?", tsquery)
+		if s.Status != "" {
+			q = q.Where("status = ?", s.Status)
+		}
+		return q
+	}
+
+	var total int64
+	if err := matching().Count(&total).Error; err != nil {
+		r.logger(ctx).Error("failed to count search results", zap.Error(err))
+		return nil, 0, translate(err)
+	}
+
+	var ranked []rankedHit
+	err := matching().
+		Select("id, ts_rank(search_vector, ?) AS rank, ts_headline(language, coalesce(body, ''), ?, ?) AS headline",
+			tsquery, tsquery, headlineOptions).
+		Order("rank DESC, id DESC").
+		Limit(s.Limit).Offset(s.Offset).
+		Scan(&ranked).Error
+	if err != nil {
+		r.logger(ctx).Error("failed to search articles", zap.Error(err))
+		return nil, 0, translate(err)
+	}
+	hits, err := r.loadHits(ctx, ranked)
+	if err != nil {
+		return nil, 0, err
+	}
+	return hits, total, nil
+}
+
+// rankedHit is a search match whose article is yet to be loaded.
+type rankedHit struct {
+	ID       uint
+	Rank     float64
+	Headline string
+}
+
+// loadHits loads the articles of the ranked matches, keeping their order.
+func (r *PostgresRepo) loadHits(ctx context.Context, ranked []rankedHit) ([]entities.ArticleSearchHit, error) {
+	if len(ranked) == 0 {
+		return []entities.ArticleSearchHit{}, nil
+	}
+
+	ids := make([]uint, 0, len(ranked))
+	for _, h := range ranked {
+		ids = append(ids, h.ID)
+	}
+	var articles []entities.Article
+	if err := withLabels(r.conn(ctx)).Find(&articles, ids).Error; err != nil {
+		r.logger(ctx).Error("failed to load search results", zap.Error(err))
+		return nil, translate(err)
+	}
+	byID := make(map[uint]entities.Article, len(articles))
+	for _, a := range articles {
+		byID[a.ID] = a
+	}
+
+	hits := make([]entities.ArticleSearchHit, 0, len(ranked))
+	for _, h := range ranked {
+		// an article deleted between both queries is simply left out
+		if a, ok := byID[h.ID]; ok {
+			hits = append(hits, entities.ArticleSearchHit{Article: a, Rank: h.Rank, Headline: h.Headline})
+		}
+	}
+	return hits, nil
+}
+
+// TagCounts returns every tag with the number of published, live articles
+// using it, most used first.
+func (r *PostgresRepo) TagCounts(ctx context.Context) ([]entities.TagCount, error) {
+	var counts []entities.TagCount
+	err := r.conn(ctx).Table("tags").
+		Select("tags.name AS name, COUNT(articles.id) AS count").
+		Joins("LEFT JOIN article_tags ON article_tags.tag_id = tags.id").
+		Joins("LEFT JOIN articles ON articles.id = article_tags.article_id AND articles.deleted_at IS NULL AND articles.status = ?",
+			entities.StatusPublished).
+		Group("tags.id, tags.name").
+		Order("count DESC, tags.name ASC").
+		Scan(&counts).Error
+	if err != nil {
+		r.logger(ctx).Error("failed to count tags", zap.Error(err))
+		return nil, translate(err)
+	}
+	return counts, nil
+}
+
+// withLabels preloads the tags and categories of the queried articles.
+func withLabels(q *gorm.DB) *gorm.DB {
+	byName := func(db *gorm.DB) *gorm.DB { return db.Order("name") }
+	return q.Preload("Tags", byName).Preload("Categories", byName)
+}
+
+// saveLabels makes sure the tags and categories of a exist and links them to it.
+func saveLabels(tx *gorm.DB, a *entities.Article) error {
+	// upserting by name also fills in the IDs of existing labels
+	byName := clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name"}),
+	}
+
+	if len(a.Tags) > 0 {
+		if err := tx.Clauses(byName).Create(&a.Tags).Error; err != nil {
+			return err
+		}
+		links := make([]map[string]any, 0, len(a.Tags))
+		for _, t := range a.Tags {
+			links = append(links, map[string]any{"article_id": a.ID, "tag_id": t.ID})
+		}
+		if err := tx.Table("article_tags").Create(links).Error; err != nil {
+			return err
+		}
+	}
+
+	if len(a.Categories) > 0 {
+		if err := tx.Clauses(byName).Create(&a.Categories).Error; err != nil {
+			return err
+		}
+		links := make([]map[string]any, 0, len(a.Categories))
+		for _, c := range a.Categories {
+			links = append(links, map[string]any{"article_id": a.ID, "category_id": c.ID})
+		}
+		if err := tx.Table("article_categories").Create(links).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// escapeLike escapes LIKE wildcards so user input is matched literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/antonchaban/articles-go/internal/entities"
+	"github.com/antonchaban/articles-go/internal/services"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
@@ -35,6 +36,41 @@ func setupTestDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock, func()) {
 	return gormDB, mock, cleanup
 }
 
+// expectNoLabels expects the preload queries for the tags and categories
+// of an article and answers them with empty results.
+func expectNoLabels(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "article_categories" WHERE "article_categories"."article_id"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"article_id", "category_id"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "article_tags" WHERE "article_tags"."article_id"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"article_id", "tag_id"}))
+}
+
+// expectRevision expects the revision recorded alongside every versioned change.
+func expectRevision(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "article_revisions"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+}
+
+// expectSlugLookup expects the lookup of slugs taken by other articles,
+// returning taken.
+func expectSlugLookup(mock sqlmock.Sqlmock, taken ...string) {
+	rows := sqlmock.NewRows([]string{"slug"})
+	for _, s := range taken {
+		rows.AddRow(s)
+	}
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT slug FROM articles WHERE id <> $1 AND (slug = $2 OR slug LIKE $3)`)).
+		WillReturnRows(rows)
+}
+
+// expectCurrentSlug expects Update to read the stored slug of an article.
+func expectCurrentSlug(mock sqlmock.Sqlmock, slug string) {
+	rows := sqlmock.NewRows([]string{"slug"})
+	if slug != "" {
+		rows.AddRow(slug)
+	}
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT slug FROM articles WHERE id = $1`)).WillReturnRows(rows)
+}
+
 func TestCreateArticleSuccessfully(t *testing.T) {
 	db, mock, cleanup := setupTestDB(t)
 	defer cleanup()
@@ -44,13 +80,21 @@ func TestCreateArticleSuccessfully(t *testing.T) {
 
 	article := &entities.Article{
 		Title:     "Test Article",
+		Slug:      "test-article",
+		Body:      "Some *body*",
+		BodyHTML:  "<p>Some <em>body</em></p>",
+		Excerpt:   "Some body",
 		CreatedAt: time.Now().UTC(),
+		Version:   1,
 	}
 
 	mock.ExpectBegin()
+	expectSlugLookup(mock)
 	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "articles"`)).
-		WithArgs(article.Title, sqlmock.AnyArg()).
+		WithArgs(article.Title, article.Slug, article.AuthorID, article.Body, article.Summary, article.Excerpt, article.BodyHTML,
+			"english", "draft", nil, nil, sqlmock.AnyArg(), sqlmock.AnyArg(), nil, article.Version).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	expectRevision(mock)
 	mock.ExpectCommit()
 
 	err := repo.Create(context.Background(), article)
@@ -68,14 +112,21 @@ func TestCreateArticleWithDatabaseError(t *testing.T) {
 
 	article := &entities.Article{
 		Title:     "Test Article",
+		Slug:      "test-article",
+		Body:      "Some *body*",
+		BodyHTML:  "<p>Some <em>body</em></p>",
+		Excerpt:   "Some body",
 		CreatedAt: time.Now().UTC(),
+		Version:   1,
 	}
 
 	expectedError := errors.New("database connection failed")
 
 	mock.ExpectBegin()
+	expectSlugLookup(mock)
 	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "articles"`)).
-		WithArgs(article.Title, sqlmock.AnyArg()).
+		WithArgs(article.Title, article.Slug, article.AuthorID, article.Body, article.Summary, article.Excerpt, article.BodyHTML,
+			"english", "draft", nil, nil, sqlmock.AnyArg(), sqlmock.AnyArg(), nil, article.Version).
 		WillReturnError(expectedError)
 	mock.ExpectRollback()
 
@@ -101,11 +152,12 @@ func TestGetByIDReturnsArticleSuccessfully(t *testing.T) {
 	rows := sqlmock.NewRows([]string{"id", "title", "created_at"}).
 		AddRow(expectedArticle.ID, expectedArticle.Title, expectedArticle.CreatedAt)
 
-	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "articles" WHERE "articles"."id" = $1 ORDER BY "articles"."id" LIMIT $2`)).
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "articles" WHERE "articles"."id" = $1 AND "articles"."deleted_at" IS NULL ORDER BY "articles"."id" LIMIT $2`)).
 		WithArgs(1, 1).
 		WillReturnRows(rows)
+	expectNoLabels(mock)
 
-	article, err := repo.GetByID(context.Background(), 1)
+	article, err := repo.GetByID(context.Background(), 1, false)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, article)
@@ -121,15 +173,15 @@ func TestGetByIDReturnsNotFoundError(t *testing.T) {
 	logger := zap.NewNop()
 	repo := NewPostgresRepo(db, logger)
 
-	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "articles" WHERE "articles"."id" = $1 ORDER BY "articles"."id" LIMIT $2`)).
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "articles" WHERE "articles"."id" = $1 AND "articles"."deleted_at" IS NULL ORDER BY "articles"."id" LIMIT $2`)).
 		WithArgs(999, 1).
 		WillReturnError(gorm.ErrRecordNotFound)
 
-	article, err := repo.GetByID(context.Background(), 999)
+	article, err := repo.GetByID(context.Background(), 999, false)
 
 	assert.Error(t, err)
 	assert.Nil(t, article)
-	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.ErrorIs(t, err, services.ErrNotFound)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -142,13 +194,507 @@ func TestGetByIDWithDatabaseError(t *testing.T) {
 
 	expectedError := errors.New("database query failed")
 
-	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "articles" WHERE "articles"."id" = $1 ORDER BY "articles"."id" LIMIT $2`)).
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "articles" WHERE "articles"."id" = $1 AND "articles"."deleted_at" IS NULL ORDER BY "articles"."id" LIMIT $2`)).
 		WithArgs(1, 1).
 		WillReturnError(expectedError)
 
-	article, err := repo.GetByID(context.Background(), 1)
+	article, err := repo.GetByID(context.Background(), 1, false)
 
 	assert.Error(t, err)
 	assert.Nil(t, article)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestListAppliesFiltersAndOrdering(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zap.NewNop()
+	repo := NewPostgresRepo(db, logger)
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	filter := entities.ArticleFilter{
+		Title:       "50%",
+		CreatedFrom: from,
+		Sort:        entities.SortCreatedAtAsc,
+		Limit:       11,
+		Offset:      20,
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "articles" WHERE LOWER(title) LIKE $1 ESCAPE '\' AND created_at >= $2 AND "articles"."deleted_at" IS NULL`)).
+		WithArgs(`%50\%%`, from).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(42))
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "articles" WHERE LOWER(title) LIKE $1 ESCAPE '\' AND created_at >= $2 AND "articles"."deleted_at" IS NULL ORDER BY created_at ASC,id ASC LIMIT $3 OFFSET $4`)).
+		WithArgs(`%50\%%`, from, 11, 20).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "created_at"}).
+			AddRow(1, "50% off", from))
+	expectNoLabels(mock)
+
+	articles, total, err := repo.List(context.Background(), filter)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), total)
+	assert.Len(t, articles, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListWithCursorUsesKeyset(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zap.NewNop()
+	repo := NewPostgresRepo(db, logger)
+
+	after := &entities.ArticleCursor{ID: 7, CreatedAt: time.Now().UTC()}
+	filter := entities.ArticleFilter{
+		Sort:   entities.SortCreatedAtDesc,
+		Limit:  5,
+		Offset: 10,
+		After:  after,
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "articles"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "articles" WHERE (created_at, id) < ($1, $2) AND "articles"."deleted_at" IS NULL ORDER BY created_at DESC,id DESC LIMIT $3`)).
+		WithArgs(after.CreatedAt, after.ID, 5).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "created_at"}))
+
+	articles, total, err := repo.List(context.Background(), filter)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	assert.Empty(t, articles)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListWithDatabaseError(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zap.NewNop()
+	repo := NewPostgresRepo(db, logger)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "articles"`)).
+		WillReturnError(errors.New("database query failed"))
+
+	articles, total, err := repo.List(context.Background(), entities.ArticleFilter{})
+
+	assert.Error(t, err)
+	assert.Nil(t, articles)
+	assert.Zero(t, total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateArticleSuccessfully(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zap.NewNop()
+	repo := NewPostgresRepo(db, logger)
+
+	article := &entities.Article{ID: 1, Title: "Updated", Slug: "updated", Body: "Body", BodyHTML: "<p>Body</p>", Excerpt: "Body", Language: "english", Version: 2}
+
+	mock.ExpectBegin()
+	expectCurrentSlug(mock, "updated")
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "articles" SET "title"=$1,"slug"=$2,"body"=$3,"summary"=$4,"excerpt"=$5,"body_html"=$6,"language"=$7,"updated_at"=$8,"version"=$9 WHERE version = $10 AND "articles"."deleted_at" IS NULL AND "id" = $11`)).
+		WithArgs("Updated", "updated", "Body", "", "Body", "<p>Body</p>", "english", sqlmock.AnyArg(), 3, 2, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM article_tags WHERE article_id = $1`)).
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM article_categories WHERE article_id = $1`)).
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	expectRevision(mock)
+	mock.ExpectCommit()
+
+	err := repo.Update(context.Background(), article)
+
+	assert.NoError(t, err)
+	assert.NotZero(t, article.UpdatedAt)
+	assert.Equal(t, uint(3), article.Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateArticleNotFound(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zap.NewNop()
+	repo := NewPostgresRepo(db, logger)
+
+	mock.ExpectBegin()
+	expectCurrentSlug(mock, "")
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "articles"`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	article := &entities.Article{ID: 999, Title: "Updated", Version: 1}
+	err := repo.Update(context.Background(), article)
+
+	assert.ErrorIs(t, err, services.ErrNotFound)
+	assert.Equal(t, uint(1), article.Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateArticleWithDatabaseError(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zap.NewNop()
+	repo := NewPostgresRepo(db, logger)
+
+	mock.ExpectBegin()
+	expectCurrentSlug(mock, "updated")
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "articles"`)).
+		WillReturnError(errors.New("database connection failed"))
+	mock.ExpectRollback()
+
+	err := repo.Update(context.Background(), &entities.Article{ID: 1, Title: "Updated"})
+
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, services.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetByIDIncludingDeleted(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zap.NewNop()
+	repo := NewPostgresRepo(db, logger)
+
+	rows := sqlmock.NewRows([]string{"id", "title", "created_at", "deleted_at"}).
+		AddRow(1, "Deleted", time.Now().UTC(), time.Now().UTC())
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "articles" WHERE "articles"."id" = $1 ORDER BY "articles"."id" LIMIT $2`)).
+		WithArgs(1, 1).
+		WillReturnRows(rows)
+	expectNoLabels(mock)
+
+	article, err := repo.GetByID(context.Background(), 1, true)
+
+	assert.NoError(t, err)
+	assert.True(t, article.DeletedAt.Valid)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteArticleSoftDeletes(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zap.NewNop()
+	repo := NewPostgresRepo(db, logger)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "articles" SET "deleted_at"=$1,"version"=version + 1,"updated_at"=$2 WHERE (id = $3 AND version = $4) AND "articles"."deleted_at" IS NULL`)).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 1, 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "articles" WHERE "articles"."id" = $1 ORDER BY "articles"."id" LIMIT $2`)).
+		WithArgs(1, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "version", "deleted_at"}).AddRow(1, "Gone", 3, time.Now()))
+	expectNoLabels(mock)
+	expectRevision(mock)
+	mock.ExpectCommit()
+
+	err := repo.Delete(context.Background(), 1, 2)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteArticleNotFound(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zap.NewNop()
+	repo := NewPostgresRepo(db, logger)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "articles" SET "deleted_at"=$1`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err := repo.Delete(context.Background(), 999, 1)
+
+	assert.ErrorIs(t, err, services.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRestoreArticleClearsDeletedAt(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zap.NewNop()
+	repo := NewPostgresRepo(db, logger)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "articles" SET "deleted_at"=$1,"version"=version + 1,"updated_at"=$2 WHERE id = $3 AND version = $4 AND deleted_at IS NOT NULL`)).
+		WithArgs(nil, sqlmock.AnyArg(), 1, 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "articles" WHERE "articles"."id" = $1 AND "articles"."deleted_at" IS NULL ORDER BY "articles"."id" LIMIT $2`)).
+		WithArgs(1, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "version"}).AddRow(1, "Restored", 3))
+	expectNoLabels(mock)
+	expectRevision(mock)
+	mock.ExpectCommit()
+
+	err := repo.Restore(context.Background(), 1, 2)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRestoreArticleThatIsNotDeleted(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zap.NewNop()
+	repo := NewPostgresRepo(db, logger)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "articles" SET "deleted_at"=$1`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err := repo.Restore(context.Background(), 1, 1)
+
+	assert.ErrorIs(t, err, services.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPurgeHardDeletesOldArticles(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zap.NewNop()
+	repo := NewPostgresRepo(db, logger)
+
+	before := time.Now().UTC().Add(-24 * time.Hour)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "articles" WHERE deleted_at IS NOT NULL AND deleted_at < $1`)).
+		WithArgs(before).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+	mock.ExpectCommit()
+
+	purged, err := repo.Purge(context.Background(), before)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), purged)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateArticleWithTags(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewPostgresRepo(db, zap.NewNop())
+
+	article := &entities.Article{
+		Title:   "Tagged",
+		Slug:    "tagged",
+		Tags:    []entities.Tag{{Name: "go"}, {Name: "sql"}},
+		Version: 1,
+	}
+
+	mock.ExpectBegin()
+	expectSlugLookup(mock)
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "articles"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "tags" ("name") VALUES ($1),($2) ON CONFLICT ("name") DO UPDATE SET "name"="excluded"."name" RETURNING "id"`)).
+		WithArgs("go", "sql").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "article_tags" ("article_id","tag_id") VALUES ($1,$2),($3,$4)`)).
+		WithArgs(5, 1, 5, 2).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	expectRevision(mock)
+	mock.ExpectCommit()
+
+	err := repo.Create(context.Background(), article)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), article.Tags[1].ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListFiltersByAllTags(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewPostgresRepo(db, zap.NewNop())
+
+	filter := entities.ArticleFilter{
+		Tags:     []string{"go", "sql"},
+		TagMatch: entities.TagMatchAll,
+		Category: "backend",
+		Sort:     entities.SortIDAsc,
+		Limit:    10,
+	}
+
+	where := `WHERE articles.id IN (SELECT article_tags.article_id FROM "article_tags" JOIN tags ON tags.id = article_tags.tag_id WHERE tags.name IN ($1,$2) GROUP BY "article_tags"."article_id" HAVING COUNT(DISTINCT tags.id) = $3) ` +
+		`AND articles.id IN (SELECT article_categories.article_id FROM "article_categories" JOIN categories ON categories.id = article_categories.category_id WHERE categories.name = $4) ` +
+		`AND "articles"."deleted_at" IS NULL`
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "articles" `+where)).
+		WithArgs("go", "sql", 2, "backend").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "articles" `+where+` ORDER BY id ASC LIMIT $5`)).
+		WithArgs("go", "sql", 2, "backend", 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, total, err := repo.List(context.Background(), filter)
+
+	assert.NoError(t, err)
+	assert.Zero(t, total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTagCounts(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewPostgresRepo(db, zap.NewNop())
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT tags.name AS name, COUNT(articles.id) AS count FROM "tags" ` +
+		`LEFT JOIN article_tags ON article_tags.tag_id = tags.id ` +
+		`LEFT JOIN articles ON articles.id = article_tags.article_id AND articles.deleted_at IS NULL AND articles.status = $1 ` +
+		`GROUP BY tags.id, tags.name ORDER BY count DESC, tags.name ASC`)).
+		WithArgs("published").
+		WillReturnRows(sqlmock.NewRows([]string{"name", "count"}).AddRow("go", 3).AddRow("sql", 0))
+
+	counts, err := repo.TagCounts(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []entities.TagCount{{Name: "go", Count: 3}, {Name: "sql", Count: 0}}, counts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSearchRanksAndLoadsArticles(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewPostgresRepo(db, zap.NewNop())
+
+	search := entities.ArticleSearch{Query: "go generics", Language: "english", Limit: 10, Offset: 0}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "articles" WHERE search_vector @@ websearch_to_tsquery($1::regconfig, $2) AND "articles"."deleted_at" IS NULL`)).
+		WithArgs("english", "go generics").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, ts_rank(search_vector, websearch_to_tsquery($1::regconfig, $2)) AS rank, ts_headline(language, coalesce(body, ''), websearch_to_tsquery($3::regconfig, $4), $5) AS headline FROM "articles" WHERE search_vector @@ websearch_to_tsquery($6::regconfig, $7) AND "articles"."deleted_at" IS NULL ORDER BY rank DESC, id DESC LIMIT $8`)).
+		WithArgs("english", "go generics", "english", "go generics", headlineOptions, "english", "go generics", 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "rank", "headline"}).
+			AddRow(2, 0.9, "about @@hl@@generics@@/hl@@").
+			AddRow(1, 0.1, "@@hl@@go@@/hl@@ basics"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "articles" WHERE "articles"."id" IN ($1,$2) AND "articles"."deleted_at" IS NULL`)).
+		WithArgs(2, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title"}).AddRow(1, "Go basics").AddRow(2, "Generics"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "article_categories"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"article_id", "category_id"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "article_tags"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"article_id", "tag_id"}))
+
+	hits, total, err := repo.Search(context.Background(), search)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, hits, 2)
+	assert.Equal(t, "Generics", hits[0].Article.Title)
+	assert.Equal(t, 0.9, hits[0].Rank)
+	assert.Equal(t, "@@hl@@go@@/hl@@ basics", hits[1].Headline)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateStatusIncrementsVersion(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewPostgresRepo(db, zap.NewNop())
+
+	publishedAt := time.Now().UTC()
+	article := &entities.Article{ID: 1, Status: entities.StatusPublished, PublishedAt: &publishedAt, Version: 3}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "articles" SET "publish_at"=$1,"published_at"=$2,"status"=$3,"version"=version + 1,"updated_at"=$4 WHERE (id = $5 AND version = $6) AND "articles"."deleted_at" IS NULL`)).
+		WithArgs(nil, &publishedAt, "published", sqlmock.AnyArg(), 1, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectRevision(mock)
+	mock.ExpectCommit()
+
+	err := repo.UpdateStatus(context.Background(), article)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(4), article.Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateStatusVersionMismatch(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewPostgresRepo(db, zap.NewNop())
+
+	article := &entities.Article{ID: 1, Status: entities.StatusInReview, Version: 3}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "articles" SET`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err := repo.UpdateStatus(context.Background(), article)
+
+	assert.ErrorIs(t, err, services.ErrNotFound)
+	assert.Equal(t, uint(3), article.Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPublishDueClaimsRowsWithSkipLocked(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewPostgresRepo(db, zap.NewNop())
+	now := time.Now().UTC()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "articles" WHERE (status = $1 AND publish_at <= $2) AND "articles"."deleted_at" IS NULL ORDER BY publish_at LIMIT $3 FOR UPDATE SKIP LOCKED`)).
+		WithArgs("in_review", now, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4).AddRow(9))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "articles" SET "publish_at"=$1,"published_at"=$2,"status"=$3,"version"=version + 1,"updated_at"=$4 WHERE id IN ($5,$6) AND "articles"."deleted_at" IS NULL`)).
+		WithArgs(nil, now, "published", sqlmock.AnyArg(), 4, 9).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "articles" WHERE "articles"."id" IN ($1,$2) AND "articles"."deleted_at" IS NULL`)).
+		WithArgs(4, 9).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "status", "version"}).
+			AddRow(4, "Four", "published", 2).
+			AddRow(9, "Nine", "published", 5))
+	expectNoLabels(mock)
+	expectRevision(mock)
+	expectRevision(mock)
+	mock.ExpectCommit()
+
+	ids, err := repo.PublishDue(context.Background(), now, 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{4, 9}, ids)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPublishDueWithNothingDue(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewPostgresRepo(db, zap.NewNop())
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "articles"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectCommit()
+
+	ids, err := repo.PublishDue(context.Background(), time.Now(), 10)
+
+	assert.NoError(t, err)
+	assert.Empty(t, ids)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/antonchaban/articles-go/internal/auth"
+	"github.com/antonchaban/articles-go/internal/entities"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ListRevisions returns a page of the revisions of an article, newest first,
+// together with the total number of revisions.
+func (r *PostgresRepo) ListRevisions(ctx context.Context, articleID uint, limit, offset int) ([]entities.ArticleRevision, int64, error) {
+	q := func() *gorm.DB {
+		return r.conn(ctx).Model(&entities.ArticleRevision{}).Where("article_id = ?", articleID)
+	}
+
+	var total int64
+	if err := q().Count(&total).Error; err != nil {
+		r.logger(ctx).Error("failed to count revisions", zap.Uint("article_id", articleID), zap.Error(err))
+		return nil, 0, translate(err)
+	}
+
+	var revisions []entities.ArticleRevision
+	if err := q().Order("version DESC").Limit(limit).Offset(offset).Find(&revisions).Error; err != nil {
+		r.logger(ctx).Error("failed to list revisions", zap.Uint("article_id", articleID), zap.Error(err))
+		return nil, 0, translate(err)
+	}
+	return revisions, total, nil
+}
+
+// GetRevision retrieves the revision of an article at the given version.
+func (r *PostgresRepo) GetRevision(ctx context.Context, articleID uint, version uint) (*entities.ArticleRevision, error) {
+	var rev entities.ArticleRevision
+	err := r.conn(ctx).
+		Where("article_id = ? AND version = ?", articleID, version).
+		First(&rev).Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			r.logger(ctx).Error("database query failed",
+				zap.Uint("article_id", articleID), zap.Uint("version", version), zap.Error(err))
+		}
+		return nil, translate(err)
+	}
+	return &rev, nil
+}
+
+// recordRevision snapshots a as of its current version. It must run in the
+// transaction that changed the article; a must carry its tags and categories.
+func recordRevision(ctx context.Context, tx *gorm.DB, a *entities.Article) error {
+	rev := entities.ArticleRevision{
+		ArticleID:  a.ID,
+		Version:    a.Version,
+		Title:      a.Title,
+		Summary:    a.Summary,
+		Body:       a.Body,
+		Status:     a.Status,
+		Tags:       make([]string, 0, len(a.Tags)),
+		Categories: make([]string, 0, len(a.Categories)),
+	}
+	for _, t := range a.Tags {
+		rev.Tags = append(rev.Tags, t.Name)
+	}
+	for _, c := range a.Categories {
+		rev.Categories = append(rev.Categories, c.Name)
+	}
+	if p, ok := auth.FromContext(ctx); ok {
+		rev.CreatedBy = p.Subject
+	}
+	return tx.Create(&rev).Error
+}
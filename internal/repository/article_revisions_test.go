@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/antonchaban/articles-go/internal/auth"
+	"github.com/antonchaban/articles-go/internal/entities"
+	"github.com/antonchaban/articles-go/internal/services"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func TestListRevisionsNewestFirst(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewPostgresRepo(db, zap.NewNop())
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "article_revisions" WHERE article_id = $1`)).
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "article_revisions" WHERE article_id = $1 ORDER BY version DESC LIMIT $2 OFFSET $3`)).
+		WithArgs(7, 2, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "article_id", "version", "title", "tags"}).
+			AddRow(12, 7, 2, "Second", []byte(`["go"]`)).
+			AddRow(11, 7, 1, "First", []byte(`[]`)))
+
+	revisions, total, err := repo.ListRevisions(context.Background(), 7, 2, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	assert.Len(t, revisions, 2)
+	assert.Equal(t, uint(2), revisions[0].Version)
+	assert.Equal(t, []string{"go"}, revisions[0].Tags)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRevisionNotFound(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewPostgresRepo(db, zap.NewNop())
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "article_revisions" WHERE article_id = $1 AND version = $2 ORDER BY "article_revisions"."id" LIMIT $3`)).
+		WithArgs(7, 9, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := repo.GetRevision(context.Background(), 7, 9)
+
+	assert.ErrorIs(t, err, services.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRecordRevisionSnapshotsLabelsAndSubject(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	article := &entities.Article{
+		ID:         7,
+		Title:      "Title",
+		Body:       "Body",
+		Status:     entities.StatusDraft,
+		Tags:       []entities.Tag{{Name: "go"}, {Name: "sql"}},
+		Categories: []entities.Category{{Name: "backend"}},
+		Version:    4,
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "article_revisions" ("article_id","version","title","summary","body","status","tags","categories","created_by","created_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10) RETURNING "id"`)).
+		WithArgs(7, 4, "Title", "", "Body", "draft", `["go","sql"]`, `["backend"]`, "alice", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	ctx := auth.WithPrincipal(context.Background(), auth.Principal{Subject: "alice"})
+	err := db.Transaction(func(tx *gorm.DB) error {
+		return recordRevision(ctx, tx, article)
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/antonchaban/articles-go/internal/entities"
+	"github.com/antonchaban/articles-go/internal/services"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetBySlug retrieves the article whose current or former slug is slug.
+// Callers tell a former slug apart by comparing it with the Slug of the result.
+// Soft-deleted articles are not returned.
+func (r *PostgresRepo) GetBySlug(ctx context.Context, slug string) (*entities.Article, error) {
+	var a entities.Article
+	err := withLabels(r.conn(ctx)).
+		Where("slug = ? OR id = (SELECT article_id FROM article_slugs WHERE slug = ?)", slug, slug).
+		First(&a).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			r.logger(ctx).Warn("article not found", zap.String("slug", slug))
+			return nil, services.ErrNotFound
+		}
+		r.logger(ctx).Error("database query failed", zap.String("slug", slug), zap.Error(err))
+		return nil, translate(err)
+	}
+	return &a, nil
+}
+
+// maxSlugAttempts bounds how often an insert picks slugs again after a
+// concurrent transaction committed one of those it had chosen.
+const maxSlugAttempts = 3
+
+// errSlugTaken marks an insert that lost a slug to a concurrent transaction.
+var errSlugTaken = errors.New("slug taken concurrently")
+
+// slugConflict wraps a duplicate key error of an insert into articles with
+// errSlugTaken. The id comes from a sequence, so only the slug index can
+// have been violated.
+func slugConflict(err error) error {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return fmt.Errorf("%w: %w", errSlugTaken, err)
+	}
+	return err
+}
+
+// retrySlugs runs insert again while it fails with errSlugTaken, at most
+// maxSlugAttempts times in total. insert must run in a transaction of its
+// own and pick the slugs anew on every call.
+func retrySlugs(insert func() error) error {
+	var err error
+	for range maxSlugAttempts {
+		if err = insert(); !errors.Is(err, errSlugTaken) {
+			return err
+		}
+	}
+	return err
+}
+
+// uniqueSlug returns base, or base with the lowest numeric suffix from 2 on,
+// so that it is neither the current nor a former slug of any article but id.
+// Soft-deleted articles keep their slugs, restoring them can't clash.
+// The unique index on articles.slug settles races between transactions.
+// reserved holds slugs handed out to articles that aren't stored yet.
+func uniqueSlug(tx *gorm.DB, base string, id uint, reserved map[string]bool) (string, error) {
+	var taken []string
+	err := tx.Raw(`SELECT slug FROM articles WHERE id <> ? AND (slug = ? OR slug LIKE ?)
+UNION SELECT slug FROM article_slugs WHERE article_id <> ? AND (slug = ? OR slug LIKE ?)`,
+		id, base, base+"-%", id, base, base+"-%").
+		Scan(&taken).Error
+	if err != nil {
+		return "", err
+	}
+
+	used := make(map[string]bool, len(taken))
+	for _, s := range taken {
+		used[s] = true
+	}
+	slug := base
+	for n := 2; used[slug] || reserved[slug]; n++ {
+		slug = fmt.Sprintf("%s-%d", base, n)
+	}
+	return slug, nil
+}
+
+// renameSlug makes the slug set on a unique if it differs from the stored
+// one, which is then kept as a former slug of the article. An empty a.Slug
+// keeps the stored slug.
+func renameSlug(tx *gorm.DB, a *entities.Article) error {
+	var current string
+	if err := tx.Raw("SELECT slug FROM articles WHERE id = ?", a.ID).Scan(&current).Error; err != nil {
+		return err
+	}
+	if a.Slug == "" {
+		a.Slug = current
+	}
+	if current == "" || a.Slug == current {
+		return nil
+	}
+
+	slug, err := uniqueSlug(tx, a.Slug, a.ID, nil)
+	if err != nil {
+		return err
+	}
+	a.Slug = slug
+	if slug == current {
+		return nil
+	}
+
+	former := entities.ArticleSlug{Slug: current, ArticleID: a.ID}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&former).Error; err != nil {
+		return err
+	}
+	// the article may be taking back one of its own former slugs
+	return tx.Where("slug = ?", slug).Delete(&entities.ArticleSlug{}).Error
+}
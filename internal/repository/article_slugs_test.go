@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/antonchaban/articles-go/internal/entities"
+	"github.com/antonchaban/articles-go/internal/services"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func TestCreateArticleSuffixesTakenSlug(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewPostgresRepo(db, zap.NewNop())
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT slug FROM articles WHERE id <> $1 AND (slug = $2 OR slug LIKE $3)
+UNION SELECT slug FROM article_slugs WHERE article_id <> $4 AND (slug = $5 OR slug LIKE $6)`)).
+		WithArgs(0, "hello", "hello-%", 0, "hello", "hello-%").
+		WillReturnRows(sqlmock.NewRows([]string{"slug"}).AddRow("hello").AddRow("hello-2").AddRow("hello-world"))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "articles"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+	expectRevision(mock)
+	mock.ExpectCommit()
+
+	article := &entities.Article{Title: "Hello", Slug: "hello", Version: 1}
+	err := repo.Create(context.Background(), article)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "hello-3", article.Slug)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateArticleRetriesSlugTakenConcurrently(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewPostgresRepo(db, zap.NewNop())
+
+	// another transaction commits "hello" between the lookup and the insert
+	mock.ExpectBegin()
+	expectSlugLookup(mock)
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "articles"`)).WillReturnError(gorm.ErrDuplicatedKey)
+	mock.ExpectRollback()
+	mock.ExpectBegin()
+	expectSlugLookup(mock, "hello")
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "articles"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+	expectRevision(mock)
+	mock.ExpectCommit()
+
+	article := &entities.Article{Title: "Hello", Slug: "hello", Version: 1}
+	err := repo.Create(context.Background(), article)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "hello-2", article.Slug)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateArticleGivesUpOnSlugConflicts(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewPostgresRepo(db, zap.NewNop())
+
+	for range maxSlugAttempts {
+		mock.ExpectBegin()
+		expectSlugLookup(mock)
+		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "articles"`)).WillReturnError(gorm.ErrDuplicatedKey)
+		mock.ExpectRollback()
+	}
+
+	err := repo.Create(context.Background(), &entities.Article{Title: "Hello", Slug: "hello", Version: 1})
+
+	assert.ErrorIs(t, err, services.ErrDuplicate)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateArticleKeepsFormerSlug(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewPostgresRepo(db, zap.NewNop())
+
+	mock.ExpectBegin()
+	expectCurrentSlug(mock, "old-title")
+	expectSlugLookup(mock)
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "article_slugs" ("slug","article_id","created_at") VALUES ($1,$2,$3) ON CONFLICT DO NOTHING`)).
+		WithArgs("old-title", 1, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "article_slugs" WHERE slug = $1`)).
+		WithArgs("new-title").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "articles" SET "title"=$1,"slug"=$2`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM article_tags WHERE article_id = $1`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM article_categories WHERE article_id = $1`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	expectRevision(mock)
+	mock.ExpectCommit()
+
+	article := &entities.Article{ID: 1, Title: "New title", Slug: "new-title", Version: 2}
+	err := repo.Update(context.Background(), article)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "new-title", article.Slug)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetBySlugMatchesCurrentAndFormerSlugs(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewPostgresRepo(db, zap.NewNop())
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "articles" WHERE (slug = $1 OR id = (SELECT article_id FROM article_slugs WHERE slug = $2)) AND "articles"."deleted_at" IS NULL ORDER BY "articles"."id" LIMIT $3`)).
+		WithArgs("old-title", "old-title", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "slug"}).AddRow(1, "New title", "new-title"))
+	expectNoLabels(mock)
+
+	article, err := repo.GetBySlug(context.Background(), "old-title")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "new-title", article.Slug)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetBySlugNotFound(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewPostgresRepo(db, zap.NewNop())
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "articles" WHERE`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := repo.GetBySlug(context.Background(), "missing")
+
+	assert.ErrorIs(t, err, services.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
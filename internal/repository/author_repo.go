@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/antonchaban/articles-go/internal/entities"
+	"github.com/antonchaban/articles-go/internal/log"
+	"github.com/antonchaban/articles-go/internal/services"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// PostgresAuthorRepo implements the AuthorRepository interface using PostgreSQL as the data store.
+type PostgresAuthorRepo struct {
+	db  *gorm.DB
+	log *zap.Logger
+}
+
+func NewPostgresAuthorRepo(db *gorm.DB, logger *zap.Logger) *PostgresAuthorRepo {
+	return &PostgresAuthorRepo{
+		db:  db,
+		log: logger.With(zap.String("layer", "repository")),
+	}
+}
+
+// logger returns the logger of r annotated with the trace of ctx.
+func (r *PostgresAuthorRepo) logger(ctx context.Context) *zap.Logger {
+	return log.WithTrace(ctx, r.log)
+}
+
+// Create inserts a new author into the database.
+func (r *PostgresAuthorRepo) Create(ctx context.Context, a *entities.Author) error {
+	if err := r.db.WithContext(ctx).Create(a).Error; err != nil {
+		r.logger(ctx).Error("failed to create author", zap.Error(err))
+		return translate(err)
+	}
+	return nil
+}
+
+// GetByID retrieves an author by its ID from the database.
+func (r *PostgresAuthorRepo) GetByID(ctx context.Context, id uint) (*entities.Author, error) {
+	var a entities.Author
+	if err := r.db.WithContext(ctx).First(&a, id).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			r.logger(ctx).Error("database query failed", zap.Uint("id", id), zap.Error(err))
+		}
+		return nil, translate(err)
+	}
+	return &a, nil
+}
+
+// GetBySubject retrieves the author linked to the given token subject.
+func (r *PostgresAuthorRepo) GetBySubject(ctx context.Context, subject string) (*entities.Author, error) {
+	var a entities.Author
+	if err := r.db.WithContext(ctx).Where("subject = ?", subject).First(&a).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			r.logger(ctx).Error("database query failed", zap.String("subject", subject), zap.Error(err))
+		}
+		return nil, translate(err)
+	}
+	return &a, nil
+}
+
+// List returns a page of authors ordered by ID together with the total number of authors.
+func (r *PostgresAuthorRepo) List(ctx context.Context, limit, offset int) ([]entities.Author, int64, error) {
+	var total int64
+	if err := r.db.WithContext(ctx).Model(&entities.Author{}).Count(&total).Error; err != nil {
+		r.logger(ctx).Error("failed to count authors", zap.Error(err))
+		return nil, 0, translate(err)
+	}
+
+	var authors []entities.Author
+	err := r.db.WithContext(ctx).Order("id ASC").Limit(limit).Offset(offset).Find(&authors).Error
+	if err != nil {
+		r.logger(ctx).Error("failed to list authors", zap.Error(err))
+		return nil, 0, translate(err)
+	}
+	return authors, total, nil
+}
+
+// Update overwrites the profile fields of an existing author.
+// Returns services.ErrNotFound if no author with the given ID exists.
+func (r *PostgresAuthorRepo) Update(ctx context.Context, a *entities.Author) error {
+	res := r.db.WithContext(ctx).Model(a).
+		Select("name", "email", "bio", "updated_at").
+		Updates(a)
+	if res.Error != nil {
+		r.logger(ctx).Error("failed to update author", zap.Uint("id", a.ID), zap.Error(res.Error))
+		return translate(res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return services.ErrNotFound
+	}
+	return nil
+}
+
+// Delete removes an author. It fails with services.ErrReferenced
+// while the author still owns articles.
+// Returns services.ErrNotFound if no author with the given ID exists.
+func (r *PostgresAuthorRepo) Delete(ctx context.Context, id uint) error {
+	res := r.db.WithContext(ctx).Delete(&entities.Author{}, id)
+	if res.Error != nil {
+		r.logger(ctx).Error("failed to delete author", zap.Uint("id", id), zap.Error(res.Error))
+		return translate(res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return services.ErrNotFound
+	}
+	return nil
+}
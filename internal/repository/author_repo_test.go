@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/antonchaban/articles-go/internal/entities"
+	"github.com/antonchaban/articles-go/internal/services"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestCreateAuthorSuccessfully(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewPostgresAuthorRepo(db, zap.NewNop())
+
+	subject := "user-1"
+	author := &entities.Author{Name: "Jane", Email: "jane@example.com", Subject: &subject}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "authors" ("name","email","bio","subject","created_at","updated_at") VALUES ($1,$2,$3,$4,$5,$6) RETURNING "id"`)).
+		WithArgs("Jane", "jane@example.com", "", &subject, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	mock.ExpectCommit()
+
+	err := repo.Create(context.Background(), author)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(7), author.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetAuthorBySubject(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewPostgresAuthorRepo(db, zap.NewNop())
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "authors" WHERE subject = $1 ORDER BY "authors"."id" LIMIT $2`)).
+		WithArgs("user-1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "subject"}).AddRow(7, "Jane", "user-1"))
+
+	author, err := repo.GetBySubject(context.Background(), "user-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(7), author.ID)
+	assert.Equal(t, "user-1", *author.Subject)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListAuthorsPaginates(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewPostgresAuthorRepo(db, zap.NewNop())
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "authors"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "authors" ORDER BY id ASC LIMIT $1 OFFSET $2`)).
+		WithArgs(2, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(2, "B").AddRow(3, "C"))
+
+	authors, total, err := repo.List(context.Background(), 2, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	assert.Len(t, authors, 2)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateAuthorOnlyTouchesProfile(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewPostgresAuthorRepo(db, zap.NewNop())
+
+	author := &entities.Author{ID: 7, Name: "Jane Doe", Email: "jane@example.com", Bio: "Writes.", CreatedAt: time.Now()}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "authors" SET "name"=$1,"email"=$2,"bio"=$3,"updated_at"=$4 WHERE "id" = $5`)).
+		WithArgs("Jane Doe", "jane@example.com", "Writes.", sqlmock.AnyArg(), 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := repo.Update(context.Background(), author)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteAuthorNotFound(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewPostgresAuthorRepo(db, zap.NewNop())
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "authors" WHERE "authors"."id" = $1`)).
+		WithArgs(999).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	err := repo.Delete(context.Background(), 999)
+
+	assert.ErrorIs(t, err, services.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListArticlesByAuthor(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewPostgresRepo(db, zap.NewNop())
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "articles" WHERE author_id = $1 AND "articles"."deleted_at" IS NULL`)).
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "articles" WHERE author_id = $1 AND "articles"."deleted_at" IS NULL ORDER BY id DESC LIMIT $2`)).
+		WithArgs(7, 5).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "author_id"}).AddRow(1, 7))
+	expectNoLabels(mock)
+
+	articles, total, err := repo.List(context.Background(), entities.ArticleFilter{AuthorID: 7, Sort: entities.SortIDDesc, Limit: 5})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, uint(7), *articles[0].AuthorID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
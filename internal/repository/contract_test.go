@@ -0,0 +1,470 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/antonchaban/articles-go/internal/auth"
+	"github.com/antonchaban/articles-go/internal/entities"
+	"github.com/antonchaban/articles-go/internal/services"
+	"github.com/antonchaban/articles-go/pkg/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// articleStore is what every article repository provides: the
+// ArticleRepository of the services and the Store of the scheduler.
+type articleStore interface {
+	services.ArticleRepository
+	PublishDue(ctx context.Context, now time.Time, limit int) ([]uint, error)
+	CountScheduled(ctx context.Context) (int64, error)
+}
+
+func TestMemoryRepoContract(t *testing.T) {
+	testArticleRepositoryContract(t, func(t *testing.T) articleStore {
+		return NewMemoryRepo(zap.NewNop())
+	})
+}
+
+// TestPostgresRepoContract runs the contract against the database named by
+// TEST_DATABASE_DSN. Its tables are emptied before every test.
+func TestPostgresRepoContract(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN is not set")
+	}
+
+	db, err := database.NewPostgresConnection(context.Background(), dsn, database.Options{}, zap.NewNop())
+	require.NoError(t, err)
+	migrator, err := database.NewMigrator(db)
+	require.NoError(t, err)
+	_, err = migrator.Up(context.Background())
+	require.NoError(t, err)
+
+	testArticleRepositoryContract(t, func(t *testing.T) articleStore {
+		err := db.Exec("TRUNCATE articles, article_revisions, article_slugs, tags, categories RESTART IDENTITY CASCADE").Error
+		require.NoError(t, err)
+		return NewPostgresRepo(db, zap.NewNop())
+	})
+}
+
+// TestSQLiteRepoContract runs the contract against a fresh SQLite database
+// file per test.
+func TestSQLiteRepoContract(t *testing.T) {
+	testArticleRepositoryContract(t, func(t *testing.T) articleStore {
+		return NewSQLiteRepo(openSQLite(t), zap.NewNop())
+	})
+}
+
+// openSQLite returns a migrated SQLite database in a fresh file.
+func openSQLite(t *testing.T) *gorm.DB {
+	db, err := database.NewSQLiteConnection(context.Background(), filepath.Join(t.TempDir(), "articles.db"), database.Options{}, zap.NewNop())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	migrator, err := database.NewMigrator(db)
+	require.NoError(t, err)
+	_, err = migrator.Up(context.Background())
+	require.NoError(t, err)
+	return db
+}
+
+// testArticleRepositoryContract checks the behavior every article repository
+// must share. newRepo returns an empty repository.
+func testArticleRepositoryContract(t *testing.T, newRepo func(t *testing.T) articleStore) {
+	ctx := context.Background()
+
+	create := func(t *testing.T, repo articleStore, a *entities.Article) *entities.Article {
+		t.Helper()
+		require.NoError(t, repo.Create(ctx, a))
+		return a
+	}
+
+	t.Run("create assigns increasing IDs and defaults", func(t *testing.T) {
+		repo := newRepo(t)
+
+		first := create(t, repo, &entities.Article{Title: "First", Slug: "first"})
+		second := create(t, repo, &entities.Article{Title: "Second", Slug: "second"})
+
+		assert.Greater(t, second.ID, first.ID)
+		got, err := repo.GetByID(ctx, first.ID, false)
+		require.NoError(t, err)
+		assert.Equal(t, "First", got.Title)
+		assert.Equal(t, entities.StatusDraft, got.Status)
+		assert.Equal(t, uint(1), got.Version)
+		assert.False(t, got.CreatedAt.IsZero())
+	})
+
+	t.Run("get reports missing articles", func(t *testing.T) {
+		repo := newRepo(t)
+
+		_, err := repo.GetByID(ctx, 42, true)
+		assert.ErrorIs(t, err, services.ErrNotFound)
+		_, err = repo.GetBySlug(ctx, "missing")
+		assert.ErrorIs(t, err, services.ErrNotFound)
+	})
+
+	t.Run("labels are stored ordered by name", func(t *testing.T) {
+		repo := newRepo(t)
+
+		a := create(t, repo, &entities.Article{
+			Title: "Labels", Slug: "labels",
+			Tags:       []entities.Tag{{Name: "zig"}, {Name: "go"}},
+			Categories: []entities.Category{{Name: "news"}},
+		})
+
+		got, err := repo.GetByID(ctx, a.ID, false)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"go", "zig"}, tagNames(got.Tags))
+		assert.Equal(t, "news", got.Categories[0].Name)
+		assert.NotZero(t, got.Tags[0].ID)
+	})
+
+	t.Run("slugs are unique and former slugs resolve", func(t *testing.T) {
+		repo := newRepo(t)
+
+		first := create(t, repo, &entities.Article{Title: "Hello", Slug: "hello"})
+		second := create(t, repo, &entities.Article{Title: "Hello", Slug: "hello"})
+		assert.Equal(t, "hello-2", second.Slug)
+
+		first.Title, first.Slug = "Renamed", "renamed"
+		require.NoError(t, repo.Update(ctx, first))
+
+		got, err := repo.GetBySlug(ctx, "hello")
+		require.NoError(t, err)
+		assert.Equal(t, first.ID, got.ID)
+		assert.Equal(t, "renamed", got.Slug)
+
+		third := create(t, repo, &entities.Article{Title: "Hello", Slug: "hello"})
+		assert.Equal(t, "hello-3", third.Slug)
+	})
+
+	t.Run("update requires the stored version", func(t *testing.T) {
+		repo := newRepo(t)
+
+		a := create(t, repo, &entities.Article{Title: "Old", Slug: "old", Tags: []entities.Tag{{Name: "go"}}})
+
+		stale := *a
+		stale.Version = 7
+		assert.ErrorIs(t, repo.Update(ctx, &stale), services.ErrNotFound)
+		assert.Equal(t, uint(7), stale.Version)
+
+		a.Title = "New"
+		a.Tags = []entities.Tag{{Name: "rust"}}
+		require.NoError(t, repo.Update(ctx, a))
+		assert.Equal(t, uint(2), a.Version)
+
+		got, err := repo.GetByID(ctx, a.ID, false)
+		require.NoError(t, err)
+		assert.Equal(t, "New", got.Title)
+		assert.Equal(t, uint(2), got.Version)
+		assert.Equal(t, []string{"rust"}, tagNames(got.Tags))
+	})
+
+	t.Run("update keeps ownership and publication state", func(t *testing.T) {
+		repo := newRepo(t)
+
+		a := create(t, repo, &entities.Article{Title: "Mine", Slug: "mine"})
+		a.Status = entities.StatusInReview
+		require.NoError(t, repo.UpdateStatus(ctx, a))
+
+		a.Status = entities.StatusPublished
+		a.Title = "Still mine"
+		require.NoError(t, repo.Update(ctx, a))
+
+		got, err := repo.GetByID(ctx, a.ID, false)
+		require.NoError(t, err)
+		assert.Equal(t, entities.StatusInReview, got.Status)
+		assert.Equal(t, "Still mine", got.Title)
+		assert.Equal(t, uint(3), got.Version)
+	})
+
+	t.Run("delete and restore", func(t *testing.T) {
+		repo := newRepo(t)
+
+		a := create(t, repo, &entities.Article{Title: "Gone", Slug: "gone"})
+		assert.ErrorIs(t, repo.Delete(ctx, a.ID, 5), services.ErrNotFound)
+		require.NoError(t, repo.Delete(ctx, a.ID, 1))
+		assert.ErrorIs(t, repo.Delete(ctx, a.ID, 2), services.ErrNotFound)
+
+		_, err := repo.GetByID(ctx, a.ID, false)
+		assert.ErrorIs(t, err, services.ErrNotFound)
+		deleted, err := repo.GetByID(ctx, a.ID, true)
+		require.NoError(t, err)
+		assert.True(t, deleted.DeletedAt.Valid)
+		assert.Equal(t, uint(2), deleted.Version)
+		rev, err := repo.GetRevision(ctx, a.ID, 2)
+		require.NoError(t, err)
+		assert.Equal(t, "Gone", rev.Title)
+
+		assert.ErrorIs(t, repo.Restore(ctx, a.ID, 1), services.ErrNotFound)
+		require.NoError(t, repo.Restore(ctx, a.ID, 2))
+		got, err := repo.GetByID(ctx, a.ID, false)
+		require.NoError(t, err)
+		assert.Equal(t, uint(3), got.Version)
+	})
+
+	t.Run("purge removes articles deleted before the cut-off", func(t *testing.T) {
+		repo := newRepo(t)
+
+		a := create(t, repo, &entities.Article{Title: "Old", Slug: "old"})
+		require.NoError(t, repo.Delete(ctx, a.ID, 1))
+		create(t, repo, &entities.Article{Title: "Live", Slug: "live"})
+
+		n, err := repo.Purge(ctx, time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+		assert.Zero(t, n)
+
+		n, err = repo.Purge(ctx, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), n)
+		_, err = repo.GetByID(ctx, a.ID, true)
+		assert.ErrorIs(t, err, services.ErrNotFound)
+	})
+
+	t.Run("list filters, orders and pages", func(t *testing.T) {
+		repo := newRepo(t)
+
+		base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		for i, title := range []string{"Go basics", "Rust basics", "Go advanced", "Cooking"} {
+			a := &entities.Article{Title: title, Slug: title, CreatedAt: base.Add(time.Duration(i) * time.Hour)}
+			if i < 3 {
+				a.Tags = []entities.Tag{{Name: "code"}}
+			}
+			if i != 1 {
+				a.Tags = append(a.Tags, entities.Tag{Name: "go"})
+			}
+			create(t, repo, a)
+		}
+
+		all, total, err := repo.List(ctx, entities.ArticleFilter{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), total)
+		assert.Equal(t, []string{"Cooking", "Go advanced", "Rust basics", "Go basics"}, titles(all))
+
+		byTitle, total, err := repo.List(ctx, entities.ArticleFilter{Title: "GO", Sort: entities.SortIDAsc})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+		assert.Equal(t, []string{"Go basics", "Go advanced"}, titles(byTitle))
+
+		tagged, total, err := repo.List(ctx, entities.ArticleFilter{
+			Tags: []string{"code", "go"}, TagMatch: entities.TagMatchAll, Sort: entities.SortCreatedAtAsc,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+		assert.Equal(t, []string{"Go basics", "Go advanced"}, titles(tagged))
+
+		page, total, err := repo.List(ctx, entities.ArticleFilter{Sort: entities.SortCreatedAtAsc, Limit: 2, Offset: 1})
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), total)
+		assert.Equal(t, []string{"Rust basics", "Go advanced"}, titles(page))
+
+		last := page[1]
+		next, _, err := repo.List(ctx, entities.ArticleFilter{
+			Sort: entities.SortCreatedAtAsc, Limit: 2,
+			After: &entities.ArticleCursor{ID: last.ID, CreatedAt: last.CreatedAt},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Cooking"}, titles(next))
+
+		ranged, _, err := repo.List(ctx, entities.ArticleFilter{
+			CreatedFrom: base.Add(time.Hour), CreatedTo: base.Add(2 * time.Hour), Sort: entities.SortIDAsc,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Rust basics", "Go advanced"}, titles(ranged))
+	})
+
+	t.Run("list hides deleted articles unless asked", func(t *testing.T) {
+		repo := newRepo(t)
+
+		a := create(t, repo, &entities.Article{Title: "Gone", Slug: "gone"})
+		require.NoError(t, repo.Delete(ctx, a.ID, 1))
+
+		_, total, err := repo.List(ctx, entities.ArticleFilter{})
+		require.NoError(t, err)
+		assert.Zero(t, total)
+		_, total, err = repo.List(ctx, entities.ArticleFilter{IncludeDeleted: true})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+	})
+
+	t.Run("revisions record every version", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := auth.WithPrincipal(ctx, auth.Principal{Subject: "user-1"})
+
+		a := create(t, repo, &entities.Article{Title: "v1", Slug: "v1", Tags: []entities.Tag{{Name: "go"}}})
+		a.Title = "v2"
+		require.NoError(t, repo.Update(ctx, a))
+
+		revisions, total, err := repo.ListRevisions(ctx, a.ID, 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+		require.Len(t, revisions, 2)
+		assert.Equal(t, uint(2), revisions[0].Version)
+		assert.Equal(t, "v2", revisions[0].Title)
+		assert.Equal(t, "user-1", revisions[0].CreatedBy)
+
+		first, err := repo.GetRevision(ctx, a.ID, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "v1", first.Title)
+		assert.Equal(t, []string{"go"}, first.Tags)
+
+		_, err = repo.GetRevision(ctx, a.ID, 3)
+		assert.ErrorIs(t, err, services.ErrNotFound)
+	})
+
+	t.Run("transactions roll back on error", func(t *testing.T) {
+		repo := newRepo(t)
+
+		kept := create(t, repo, &entities.Article{Title: "Kept", Slug: "kept"})
+		failure := errors.New("abort")
+		var created uint
+		err := repo.Transaction(ctx, func(ctx context.Context) error {
+			a := &entities.Article{Title: "Rolled back", Slug: "rolled-back"}
+			if err := repo.Create(ctx, a); err != nil {
+				return err
+			}
+			created = a.ID
+			if err := repo.Delete(ctx, kept.ID, 1); err != nil {
+				return err
+			}
+			return failure
+		})
+
+		assert.ErrorIs(t, err, failure)
+		_, err = repo.GetByID(ctx, created, true)
+		assert.ErrorIs(t, err, services.ErrNotFound)
+		_, err = repo.GetByID(ctx, kept.ID, false)
+		assert.NoError(t, err)
+	})
+
+	t.Run("transactions commit", func(t *testing.T) {
+		repo := newRepo(t)
+
+		var created uint
+		err := repo.Transaction(ctx, func(ctx context.Context) error {
+			a := &entities.Article{Title: "Committed", Slug: "committed"}
+			if err := repo.Create(ctx, a); err != nil {
+				return err
+			}
+			created = a.ID
+			return nil
+		})
+
+		require.NoError(t, err)
+		_, err = repo.GetByID(ctx, created, false)
+		assert.NoError(t, err)
+	})
+
+	t.Run("create in batches", func(t *testing.T) {
+		repo := newRepo(t)
+
+		create(t, repo, &entities.Article{Title: "Same", Slug: "same"})
+		batch := []*entities.Article{
+			{Title: "Same", Slug: "same", Tags: []entities.Tag{{Name: "go"}}},
+			{Title: "Same", Slug: "same"},
+		}
+		require.NoError(t, repo.CreateInBatches(ctx, batch, 1))
+
+		assert.Equal(t, "same-2", batch[0].Slug)
+		assert.Equal(t, "same-3", batch[1].Slug)
+		got, err := repo.GetByID(ctx, batch[0].ID, false)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"go"}, tagNames(got.Tags))
+	})
+
+	t.Run("scheduled articles are published when due", func(t *testing.T) {
+		repo := newRepo(t)
+
+		now := time.Now().UTC().Truncate(time.Second)
+		due := create(t, repo, &entities.Article{Title: "Due", Slug: "due"})
+		later := create(t, repo, &entities.Article{Title: "Later", Slug: "later"})
+		for a, at := range map[*entities.Article]time.Time{due: now.Add(-time.Minute), later: now.Add(time.Hour)} {
+			a.Status, a.PublishAt = entities.StatusInReview, &at
+			require.NoError(t, repo.UpdateStatus(ctx, a))
+		}
+
+		n, err := repo.CountScheduled(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), n)
+
+		ids, err := repo.PublishDue(ctx, now, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []uint{due.ID}, ids)
+
+		got, err := repo.GetByID(ctx, due.ID, false)
+		require.NoError(t, err)
+		assert.Equal(t, entities.StatusPublished, got.Status)
+		assert.Nil(t, got.PublishAt)
+		require.NotNil(t, got.PublishedAt)
+		assert.Equal(t, uint(3), got.Version)
+	})
+
+	t.Run("tag counts include published articles only", func(t *testing.T) {
+		repo := newRepo(t)
+
+		published := create(t, repo, &entities.Article{Title: "One", Slug: "one", Tags: []entities.Tag{{Name: "go"}}})
+		published.Status = entities.StatusPublished
+		require.NoError(t, repo.UpdateStatus(ctx, published))
+		create(t, repo, &entities.Article{Title: "Two", Slug: "two", Tags: []entities.Tag{{Name: "go"}, {Name: "draft"}}})
+
+		counts, err := repo.TagCounts(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []entities.TagCount{{Name: "go", Count: 1}, {Name: "draft", Count: 0}}, counts)
+	})
+
+	t.Run("search matches words", func(t *testing.T) {
+		repo := newRepo(t)
+
+		match := create(t, repo, &entities.Article{Title: "Kubernetes", Slug: "kubernetes", Body: "Deploying with kubernetes and helm"})
+		cooking := create(t, repo, &entities.Article{Title: "Cooking", Slug: "cooking", Body: "Pasta"})
+
+		hits, total, err := repo.Search(ctx, entities.ArticleSearch{Query: "helm", Language: "simple", Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		require.Len(t, hits, 1)
+		assert.Equal(t, match.ID, hits[0].Article.ID)
+		assert.Contains(t, hits[0].Headline, entities.HeadlineStart+"helm"+entities.HeadlineStop)
+
+		hits, total, err = repo.Search(ctx, entities.ArticleSearch{Query: "helm -kubernetes", Language: "simple", Limit: 10})
+		require.NoError(t, err)
+		assert.Zero(t, total)
+		assert.Empty(t, hits)
+
+		hits, total, err = repo.Search(ctx, entities.ArticleSearch{Query: `pasta or "with kubernetes"`, Language: "simple", Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+		assert.Len(t, hits, 2)
+
+		require.NoError(t, repo.Delete(ctx, cooking.ID, cooking.Version))
+		_, total, err = repo.Search(ctx, entities.ArticleSearch{Query: "pasta", Language: "simple", Limit: 10})
+		require.NoError(t, err)
+		assert.Zero(t, total)
+	})
+}
+
+func tagNames(tags []entities.Tag) []string {
+	names := make([]string, 0, len(tags))
+	for _, t := range tags {
+		names = append(names, t.Name)
+	}
+	return names
+}
+
+func titles(articles []entities.Article) []string {
+	ts := make([]string, 0, len(articles))
+	for _, a := range articles {
+		ts = append(ts, a.Title)
+	}
+	return ts
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"strings"
+
+	"github.com/antonchaban/articles-go/internal/services"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// translate maps gorm and driver errors onto the domain errors of the
+// services package, so that callers never have to know about the storage.
+// Errors that are neither missing rows, constraint violations nor signs of
+// an unreachable database are returned unchanged.
+func translate(err error) error {
+	var domain *services.Error
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &domain):
+		return err
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return services.ErrNotFound
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return services.ErrDuplicate
+	case errors.Is(err, gorm.ErrForeignKeyViolated):
+		return services.ErrReferenced
+	case unavailable(err):
+		return services.Unavailable(err)
+	}
+	return err
+}
+
+// unavailable reports whether err means the database could not be reached
+// or refused to serve the query, as opposed to a failure of the query itself.
+func unavailable(err error) bool {
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) ||
+		errors.Is(err, context.DeadlineExceeded) || pgconn.Timeout(err) {
+		return true
+	}
+
+	var connectErr *pgconn.ConnectError
+	if errors.As(err, &connectErr) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		// connection exceptions, insufficient resources and shutdowns
+		return strings.HasPrefix(pgErr.Code, "08") || strings.HasPrefix(pgErr.Code, "53") ||
+			strings.HasPrefix(pgErr.Code, "57P")
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"testing"
+
+	"github.com/antonchaban/articles-go/internal/services"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func TestTranslate(t *testing.T) {
+	syntax := &pgconn.PgError{Code: "42601"}
+
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"nil", nil, nil},
+		{"not found", fmt.Errorf("query: %w", gorm.ErrRecordNotFound), services.ErrNotFound},
+		{"duplicate", gorm.ErrDuplicatedKey, services.ErrDuplicate},
+		{"foreign key", gorm.ErrForeignKeyViolated, services.ErrReferenced},
+		{"bad connection", driver.ErrBadConn, services.ErrUnavailable},
+		{"deadline", context.DeadlineExceeded, services.ErrUnavailable},
+		{"admin shutdown", &pgconn.PgError{Code: "57P01"}, services.ErrUnavailable},
+		{"too many connections", &pgconn.PgError{Code: "53300"}, services.ErrUnavailable},
+		{"domain error", services.ErrVersionConflict, services.ErrVersionConflict},
+		{"query error", syntax, syntax},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := translate(tt.err)
+			if tt.want == nil {
+				assert.NoError(t, got)
+				return
+			}
+			assert.ErrorIs(t, got, tt.want)
+		})
+	}
+}
+
+func TestGetByIDReportsUnavailableDatabase(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewPostgresRepo(db, zap.NewNop())
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "articles"`)).
+		WillReturnError(&net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset by peer")})
+
+	_, err := repo.GetByID(context.Background(), 1, false)
+
+	var domain *services.Error
+	require.ErrorAs(t, err, &domain)
+	assert.Equal(t, services.KindUnavailable, domain.Kind)
+	assert.NotErrorIs(t, err, services.ErrNotFound)
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/antonchaban/articles-go/internal/entities"
+	"github.com/antonchaban/articles-go/internal/log"
+	"github.com/antonchaban/articles-go/internal/services"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PostgresIdempotencyRepo stores idempotency keys and the responses recorded for them.
+type PostgresIdempotencyRepo struct {
+	db  *gorm.DB
+	log *zap.Logger
+}
+
+func NewPostgresIdempotencyRepo(db *gorm.DB, logger *zap.Logger) *PostgresIdempotencyRepo {
+	return &PostgresIdempotencyRepo{
+		db:  db,
+		log: logger.With(zap.String("layer", "repository")),
+	}
+}
+
+// logger returns the logger of r annotated with the trace of ctx.
+func (r *PostgresIdempotencyRepo) logger(ctx context.Context) *zap.Logger {
+	return log.WithTrace(ctx, r.log)
+}
+
+// reservationLease is how long a reservation without a response holds its
+// key. Requests are handled well within it, so an older reservation belongs
+// to a request that died with its process and is taken over by retries.
+const reservationLease = time.Minute
+
+// maxReserveAttempts bounds how often Reserve tries again to claim a key that
+// was released while it was looking at it.
+const maxReserveAttempts = 3
+
+// Reserve claims k.Key for k.Subject, taking over records that expired by
+// k.CreatedAt and reservations older than reservationLease. It returns nil
+// when the key was claimed; the caller then owns it and must Complete or
+// Release it. Otherwise the stored record is returned.
+func (r *PostgresIdempotencyRepo) Reserve(ctx context.Context, k *entities.IdempotencyKey) (*entities.IdempotencyKey, error) {
+	for range maxReserveAttempts {
+		res := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "subject"}, {Name: "key"}},
+			DoUpdates: clause.AssignmentColumns([]string{"request_hash", "status", "header", "body", "created_at", "expires_at"}),
+			Where: clause.Where{Exprs: []clause.Expression{clause.Or(
+				clause.Lte{Column: clause.Column{Table: "idempotency_keys", Name: "expires_at"}, Value: k.CreatedAt},
+				clause.And(
+					clause.Eq{Column: clause.Column{Table: "idempotency_keys", Name: "status"}, Value: 0},
+					clause.Lte{Column: clause.Column{Table: "idempotency_keys", Name: "created_at"}, Value: k.CreatedAt.Add(-reservationLease)},
+				),
+			)}},
+		}).Create(k)
+		if res.Error != nil {
+			r.logger(ctx).Error("failed to reserve idempotency key", zap.Error(res.Error))
+			return nil, translate(res.Error)
+		}
+		if res.RowsAffected > 0 {
+			return nil, nil
+		}
+
+		var stored entities.IdempotencyKey
+		err := r.db.WithContext(ctx).Where("subject = ? AND key = ?", k.Subject, k.Key).First(&stored).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// released in the meantime, so it is free again
+			continue
+		}
+		if err != nil {
+			r.logger(ctx).Error("failed to load idempotency key", zap.Error(err))
+			return nil, translate(err)
+		}
+		return &stored, nil
+	}
+	r.logger(ctx).Warn("idempotency key keeps being released and taken", zap.String("key", k.Key))
+	return nil, services.ErrDuplicate
+}
+
+// Complete stores the response of the request k was reserved for, unless
+// the reservation was taken over by a retry meanwhile.
+func (r *PostgresIdempotencyRepo) Complete(ctx context.Context, k *entities.IdempotencyKey) error {
+	res := r.db.WithContext(ctx).Model(k).
+		Where("created_at = ?", k.CreatedAt).
+		Select("status", "header", "body").Updates(k)
+	if res.Error != nil {
+		r.logger(ctx).Error("failed to store idempotent response", zap.Error(res.Error))
+		return translate(res.Error)
+	}
+	if res.RowsAffected == 0 {
+		r.logger(ctx).Warn("idempotency key was taken over before its response was stored", zap.String("key", k.Key))
+	}
+	return nil
+}
+
+// Release drops the reservation k whose request failed, so that it can be
+// retried with the same key. Completed keys and reservations taken over by a
+// retry are kept.
+func (r *PostgresIdempotencyRepo) Release(ctx context.Context, k *entities.IdempotencyKey) error {
+	err := r.db.WithContext(ctx).
+		Where("subject = ? AND key = ? AND created_at = ? AND status = 0", k.Subject, k.Key, k.CreatedAt).
+		Delete(&entities.IdempotencyKey{}).Error
+	if err != nil {
+		r.logger(ctx).Error("failed to release idempotency key", zap.Error(err))
+		return translate(err)
+	}
+	return nil
+}
+
+// DeleteExpired removes the keys that expired by now and returns how many were removed.
+func (r *PostgresIdempotencyRepo) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	res := r.db.WithContext(ctx).Where("expires_at <= ?", now).Delete(&entities.IdempotencyKey{})
+	if res.Error != nil {
+		r.logger(ctx).Error("failed to delete expired idempotency keys", zap.Error(res.Error))
+		return 0, translate(res.Error)
+	}
+	return res.RowsAffected, nil
+}
@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/antonchaban/articles-go/internal/entities"
+	"github.com/antonchaban/articles-go/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestReserveIdempotencyKeyClaimsFreeKey(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewPostgresIdempotencyRepo(db, zap.NewNop())
+	now := time.Now().UTC()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "idempotency_keys" ("subject","key","request_hash","status","header","body","created_at","expires_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8) ON CONFLICT ("subject","key") DO UPDATE SET "request_hash"="excluded"."request_hash","status"="excluded"."status","header"="excluded"."header","body"="excluded"."body","created_at"="excluded"."created_at","expires_at"="excluded"."expires_at" WHERE ("idempotency_keys"."expires_at" <= $9 OR ("idempotency_keys"."status" = $10 AND "idempotency_keys"."created_at" <= $11))`)).
+		WithArgs("user-1", "key-1", "hash", 0, nil, []byte(nil), now, now.Add(time.Hour), now, 0, now.Add(-reservationLease)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	stored, err := repo.Reserve(context.Background(), &entities.IdempotencyKey{
+		Subject: "user-1", Key: "key-1", RequestHash: "hash", CreatedAt: now, ExpiresAt: now.Add(time.Hour),
+	})
+
+	assert.NoError(t, err)
+	assert.Nil(t, stored)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReserveIdempotencyKeyReturnsTakenKey(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewPostgresIdempotencyRepo(db, zap.NewNop())
+	now := time.Now().UTC()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "idempotency_keys"`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "idempotency_keys" WHERE subject = $1 AND key = $2 ORDER BY "idempotency_keys"."subject" LIMIT $3`)).
+		WithArgs("user-1", "key-1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"subject", "key", "request_hash", "status", "header", "body"}).
+			AddRow("user-1", "key-1", "hash", 201, `{"Location":"/api/v1/articles/1"}`, []byte(`{"id":1}`)))
+
+	stored, err := repo.Reserve(context.Background(), &entities.IdempotencyKey{
+		Subject: "user-1", Key: "key-1", RequestHash: "hash", CreatedAt: now, ExpiresAt: now.Add(time.Hour),
+	})
+
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.True(t, stored.Completed())
+	assert.Equal(t, map[string]string{"Location": "/api/v1/articles/1"}, stored.Header)
+	assert.Equal(t, []byte(`{"id":1}`), stored.Body)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReserveIdempotencyKeyGivesUpOnKeyKeptBeingReleased(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewPostgresIdempotencyRepo(db, zap.NewNop())
+	now := time.Now().UTC()
+
+	for range maxReserveAttempts {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "idempotency_keys"`)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "idempotency_keys"`)).
+			WillReturnRows(sqlmock.NewRows([]string{"subject", "key"}))
+	}
+
+	_, err := repo.Reserve(context.Background(), &entities.IdempotencyKey{
+		Subject: "user-1", Key: "key-1", RequestHash: "hash", CreatedAt: now, ExpiresAt: now.Add(time.Hour),
+	})
+
+	assert.ErrorIs(t, err, services.ErrDuplicate)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCompleteIdempotencyKeyOnlyUpdatesOwnReservation(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewPostgresIdempotencyRepo(db, zap.NewNop())
+	now := time.Now().UTC()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "idempotency_keys" SET "status"=$1,"header"=$2,"body"=$3 WHERE created_at = $4 AND "subject" = $5 AND "key" = $6`)).
+		WithArgs(201, `{"Location":"/api/v1/articles/1"}`, []byte(`{"id":1}`), now, "user-1", "key-1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	err := repo.Complete(context.Background(), &entities.IdempotencyKey{
+		Subject: "user-1", Key: "key-1", Status: 201, CreatedAt: now,
+		Header: map[string]string{"Location": "/api/v1/articles/1"}, Body: []byte(`{"id":1}`),
+	})
+
+	assert.NoError(t, err, "a reservation taken over by a retry is left to it")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReserveIdempotencyKeyTakesOverAbandonedReservation(t *testing.T) {
+	repo := NewPostgresIdempotencyRepo(openSQLite(t), zap.NewNop())
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	reservation := func(at time.Time) *entities.IdempotencyKey {
+		return &entities.IdempotencyKey{Subject: "user-1", Key: "key-1", RequestHash: "hash", CreatedAt: at, ExpiresAt: at.Add(24 * time.Hour)}
+	}
+
+	abandoned := reservation(now.Add(-2 * reservationLease))
+	stored, err := repo.Reserve(ctx, abandoned)
+	require.NoError(t, err)
+	require.Nil(t, stored)
+
+	retry := reservation(now)
+	stored, err = repo.Reserve(ctx, retry)
+	require.NoError(t, err)
+	assert.Nil(t, stored, "a reservation past its lease is taken over")
+
+	stored, err = repo.Reserve(ctx, reservation(now.Add(time.Second)))
+	require.NoError(t, err)
+	require.NotNil(t, stored, "a reservation within its lease is kept")
+
+	// the abandoned request can neither release nor complete the key anymore
+	require.NoError(t, repo.Release(ctx, abandoned))
+	abandoned.Status = 201
+	require.NoError(t, repo.Complete(ctx, abandoned))
+	stored, err = repo.Reserve(ctx, reservation(now.Add(time.Second)))
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.False(t, stored.Completed())
+
+	retry.Status = 201
+	require.NoError(t, repo.Complete(ctx, retry))
+	stored, err = repo.Reserve(ctx, reservation(now.Add(time.Second)))
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.True(t, stored.Completed())
+}
+
+func TestReleaseIdempotencyKeyKeepsCompletedKeys(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewPostgresIdempotencyRepo(db, zap.NewNop())
+	now := time.Now().UTC()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "idempotency_keys" WHERE subject = $1 AND key = $2 AND created_at = $3 AND status = 0`)).
+		WithArgs("user-1", "key-1", now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.Release(context.Background(), &entities.IdempotencyKey{Subject: "user-1", Key: "key-1", CreatedAt: now}))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteExpiredIdempotencyKeys(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewPostgresIdempotencyRepo(db, zap.NewNop())
+	now := time.Now().UTC()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "idempotency_keys" WHERE expires_at <= $1`)).
+		WithArgs(now).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+	mock.ExpectCommit()
+
+	n, err := repo.DeleteExpired(context.Background(), now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), n)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
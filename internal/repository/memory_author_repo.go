@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/antonchaban/articles-go/internal/entities"
+	"github.com/antonchaban/articles-go/internal/services"
+
+	"go.uber.org/zap"
+)
+
+// MemoryAuthorRepo implements the AuthorRepository interface in memory,
+// next to a MemoryRepo holding the articles of the authors.
+//
+// Calls needing both repositories lock the MemoryRepo first, like article
+// transactions looking authors up do, so that they can't deadlock.
+type MemoryAuthorRepo struct {
+	mu       sync.RWMutex
+	authors  map[uint]*entities.Author
+	lastID   uint
+	articles *MemoryRepo
+	log      *zap.Logger
+}
+
+func NewMemoryAuthorRepo(articles *MemoryRepo, logger *zap.Logger) *MemoryAuthorRepo {
+	return &MemoryAuthorRepo{
+		authors:  map[uint]*entities.Author{},
+		articles: articles,
+		log:      logger.With(zap.String("layer", "repository")),
+	}
+}
+
+// Create stores a new author. Emails and subjects must be unique.
+func (r *MemoryAuthorRepo) Create(_ context.Context, a *entities.Author) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.taken(a) {
+		return services.ErrDuplicate
+	}
+
+	now := time.Now()
+	r.lastID++
+	a.ID = r.lastID
+	if a.CreatedAt.IsZero() {
+		a.CreatedAt = now
+	}
+	if a.UpdatedAt.IsZero() {
+		a.UpdatedAt = now
+	}
+	r.authors[a.ID] = cloneAuthor(a)
+	return nil
+}
+
+// GetByID retrieves an author by its ID.
+func (r *MemoryAuthorRepo) GetByID(_ context.Context, id uint) (*entities.Author, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	a, ok := r.authors[id]
+	if !ok {
+		return nil, services.ErrNotFound
+	}
+	return cloneAuthor(a), nil
+}
+
+// GetBySubject retrieves the author linked to the given token subject.
+func (r *MemoryAuthorRepo) GetBySubject(_ context.Context, subject string) (*entities.Author, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, a := range r.authors {
+		if a.Subject != nil && *a.Subject == subject {
+			return cloneAuthor(a), nil
+		}
+	}
+	return nil, services.ErrNotFound
+}
+
+// List returns a page of authors ordered by ID together with the total number of authors.
+func (r *MemoryAuthorRepo) List(_ context.Context, limit, offset int) ([]entities.Author, int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	authors := make([]entities.Author, 0, len(r.authors))
+	for id := uint(1); id <= r.lastID; id++ {
+		if a, ok := r.authors[id]; ok {
+			authors = append(authors, *cloneAuthor(a))
+		}
+	}
+	total := int64(len(authors))
+
+	authors = authors[min(offset, len(authors)):]
+	if limit > 0 && len(authors) > limit {
+		authors = authors[:limit]
+	}
+	return authors, total, nil
+}
+
+// Update overwrites the profile fields of an existing author.
+// Returns services.ErrNotFound if no author with the given ID exists.
+func (r *MemoryAuthorRepo) Update(_ context.Context, a *entities.Author) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, ok := r.authors[a.ID]
+	if !ok {
+		return services.ErrNotFound
+	}
+	if r.taken(&entities.Author{ID: a.ID, Email: a.Email}) {
+		return services.ErrDuplicate
+	}
+
+	a.UpdatedAt = time.Now()
+	current.Name = a.Name
+	current.Email = a.Email
+	current.Bio = a.Bio
+	current.UpdatedAt = a.UpdatedAt
+	return nil
+}
+
+// Delete removes an author. It fails with services.ErrReferenced
+// while the author still owns articles.
+// Returns services.ErrNotFound if no author with the given ID exists.
+func (r *MemoryAuthorRepo) Delete(ctx context.Context, id uint) error {
+	if r.articles != nil {
+		defer r.articles.rlock(ctx)()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.authors[id]; !ok {
+		return services.ErrNotFound
+	}
+	if r.articles != nil && r.articles.ownsArticles(id) {
+		return services.ErrReferenced
+	}
+	delete(r.authors, id)
+	return nil
+}
+
+// taken reports whether another author than a uses its email or subject.
+func (r *MemoryAuthorRepo) taken(a *entities.Author) bool {
+	for _, other := range r.authors {
+		if other.ID == a.ID {
+			continue
+		}
+		if other.Email == a.Email ||
+			(a.Subject != nil && other.Subject != nil && *other.Subject == *a.Subject) {
+			return true
+		}
+	}
+	return false
+}
+
+func cloneAuthor(a *entities.Author) *entities.Author {
+	c := *a
+	c.Subject = clonePtr(a.Subject)
+	return &c
+}
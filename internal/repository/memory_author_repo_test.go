@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/antonchaban/articles-go/internal/entities"
+	"github.com/antonchaban/articles-go/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestMemoryAuthorRepoRejectsDuplicateEmail(t *testing.T) {
+	repo := NewMemoryAuthorRepo(NewMemoryRepo(zap.NewNop()), zap.NewNop())
+	ctx := context.Background()
+
+	first := &entities.Author{Name: "Ann", Email: "ann@example.com"}
+	require.NoError(t, repo.Create(ctx, first))
+	second := &entities.Author{Name: "Bob", Email: "bob@example.com"}
+	require.NoError(t, repo.Create(ctx, second))
+
+	assert.ErrorIs(t, repo.Create(ctx, &entities.Author{Name: "Ann", Email: "ann@example.com"}), services.ErrDuplicate)
+	second.Email = "ann@example.com"
+	assert.ErrorIs(t, repo.Update(ctx, second), services.ErrDuplicate)
+
+	authors, total, err := repo.List(ctx, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, "bob@example.com", authors[1].Email)
+}
+
+func TestMemoryAuthorRepoKeepsAuthorsOwningArticles(t *testing.T) {
+	articles := NewMemoryRepo(zap.NewNop())
+	repo := NewMemoryAuthorRepo(articles, zap.NewNop())
+	ctx := context.Background()
+
+	author := &entities.Author{Name: "Ann", Email: "ann@example.com"}
+	require.NoError(t, repo.Create(ctx, author))
+	article := &entities.Article{Title: "Mine", Slug: "mine", AuthorID: &author.ID}
+	require.NoError(t, articles.Create(ctx, article))
+	require.NoError(t, articles.Delete(ctx, article.ID, article.Version))
+
+	assert.ErrorIs(t, repo.Delete(ctx, author.ID), services.ErrReferenced)
+
+	_, err := articles.Purge(ctx, article.CreatedAt.AddDate(1, 0, 0))
+	require.NoError(t, err)
+	assert.NoError(t, repo.Delete(ctx, author.ID))
+	assert.ErrorIs(t, repo.Delete(ctx, author.ID), services.ErrNotFound)
+}
+
+func TestMemoryAuthorRepoDeleteDoesNotDeadlockWithArticleTransactions(t *testing.T) {
+	articles := NewMemoryRepo(zap.NewNop())
+	repo := NewMemoryAuthorRepo(articles, zap.NewNop())
+	ctx := context.Background()
+
+	subject := "ann"
+	require.NoError(t, repo.Create(ctx, &entities.Author{Name: "Ann", Email: "ann@example.com", Subject: &subject}))
+	other := &entities.Author{Name: "Bob", Email: "bob@example.com"}
+	require.NoError(t, repo.Create(ctx, other))
+
+	done := make(chan error, 2)
+	go func() {
+		// an atomic batch resolving its author while another one is deleted
+		done <- articles.Transaction(ctx, func(ctx context.Context) error {
+			go func() { done <- repo.Delete(context.Background(), other.ID) }()
+			time.Sleep(50 * time.Millisecond)
+			_, err := repo.GetBySubject(ctx, subject)
+			return err
+		})
+	}()
+
+	for range 2 {
+		select {
+		case err := <-done:
+			assert.NoError(t, err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("deleting an author deadlocked with an article transaction")
+		}
+	}
+}
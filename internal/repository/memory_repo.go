@@ -0,0 +1,722 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/antonchaban/articles-go/internal/auth"
+	"github.com/antonchaban/articles-go/internal/entities"
+	"github.com/antonchaban/articles-go/internal/log"
+	"github.com/antonchaban/articles-go/internal/services"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// MemoryRepo implements the ArticleRepository interface in memory, for tests
+// and local development. It follows the semantics of PostgresRepo, except
+// that Search matches words literally instead of using Postgres text search.
+// Everything is lost when the process exits.
+type MemoryRepo struct {
+	mu    sync.RWMutex
+	state *memoryState
+	now   func() time.Time
+	log   *zap.Logger
+}
+
+// memoryState holds the rows of a MemoryRepo. The last* counters act like
+// sequences and never go back, not even when a transaction is rolled back.
+type memoryState struct {
+	articles map[uint]*entities.Article
+	// slugs maps former slugs to their article.
+	slugs map[string]uint
+	// revisions holds the revisions of every article, oldest first.
+	revisions  map[uint][]entities.ArticleRevision
+	tags       map[string]uint
+	categories map[string]uint
+
+	lastArticleID  uint
+	lastRevisionID uint
+	lastTagID      uint
+	lastCategoryID uint
+}
+
+func NewMemoryRepo(logger *zap.Logger) *MemoryRepo {
+	return &MemoryRepo{
+		state: &memoryState{
+			articles:   map[uint]*entities.Article{},
+			slugs:      map[string]uint{},
+			revisions:  map[uint][]entities.ArticleRevision{},
+			tags:       map[string]uint{},
+			categories: map[string]uint{},
+		},
+		now: time.Now,
+		log: logger.With(zap.String("layer", "repository")),
+	}
+}
+
+// logger returns the logger of r annotated with the trace of ctx.
+func (r *MemoryRepo) logger(ctx context.Context) *zap.Logger {
+	return log.WithTrace(ctx, r.log)
+}
+
+// memoryTxKey is the context key marking calls made within a Transaction.
+type memoryTxKey struct{}
+
+// Transaction runs fn while holding the repository exclusively. Changes made
+// by fn are undone if it returns an error or panics. Repository calls made
+// with the context passed to fn join the transaction; like a database
+// transaction, that context must not be used concurrently.
+func (r *MemoryRepo) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if !r.inTx(ctx) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		ctx = context.WithValue(ctx, memoryTxKey{}, r)
+	}
+
+	saved := r.state.clone()
+	committed := false
+	defer func() {
+		if !committed {
+			r.state.rollback(saved)
+		}
+	}()
+
+	if err := fn(ctx); err != nil {
+		return err
+	}
+	committed = true
+	return nil
+}
+
+func (r *MemoryRepo) inTx(ctx context.Context) bool {
+	tx, _ := ctx.Value(memoryTxKey{}).(*MemoryRepo)
+	return tx == r
+}
+
+// lock takes the write lock, unless a transaction of ctx holds it already,
+// and returns its release.
+func (r *MemoryRepo) lock(ctx context.Context) func() {
+	if r.inTx(ctx) {
+		return func() {}
+	}
+	r.mu.Lock()
+	return r.mu.Unlock
+}
+
+// rlock is lock for reading.
+func (r *MemoryRepo) rlock(ctx context.Context) func() {
+	if r.inTx(ctx) {
+		return func() {}
+	}
+	r.mu.RLock()
+	return r.mu.RUnlock
+}
+
+// Create stores a new article and records its first revision. Like the
+// database defaults, an empty status is set to draft, an unset version to 1
+// and an empty language to english. a.Slug is suffixed with a number when
+// another article already uses it.
+func (r *MemoryRepo) Create(ctx context.Context, a *entities.Article) error {
+	defer r.lock(ctx)()
+
+	a.Slug = r.state.uniqueSlug(a.Slug, 0, nil)
+	r.insert(ctx, a)
+	return nil
+}
+
+// CreateInBatches stores articles like Create, all at once. Slugs are made
+// unique among the new articles too. batchSize has no meaning in memory.
+func (r *MemoryRepo) CreateInBatches(ctx context.Context, articles []*entities.Article, _ int) error {
+	defer r.lock(ctx)()
+
+	reserved := make(map[string]bool, len(articles))
+	for _, a := range articles {
+		a.Slug = r.state.uniqueSlug(a.Slug, 0, reserved)
+		reserved[a.Slug] = true
+	}
+	for _, a := range articles {
+		r.insert(ctx, a)
+	}
+	return nil
+}
+
+// insert stores a as a new article. The caller holds the write lock.
+func (r *MemoryRepo) insert(ctx context.Context, a *entities.Article) {
+	s := r.state
+	now := r.now()
+
+	s.lastArticleID++
+	a.ID = s.lastArticleID
+	if a.CreatedAt.IsZero() {
+		a.CreatedAt = now
+	}
+	if a.UpdatedAt.IsZero() {
+		a.UpdatedAt = now
+	}
+	if a.Status == "" {
+		a.Status = entities.StatusDraft
+	}
+	if a.Language == "" {
+		a.Language = "english"
+	}
+	if a.Version == 0 {
+		a.Version = 1
+	}
+
+	s.saveLabels(a)
+	s.articles[a.ID] = stored(a)
+	r.recordRevision(ctx, a)
+}
+
+// GetByID retrieves an article by its ID.
+// Soft-deleted articles are only returned when includeDeleted is set.
+func (r *MemoryRepo) GetByID(ctx context.Context, id uint, includeDeleted bool) (*entities.Article, error) {
+	defer r.rlock(ctx)()
+
+	a, ok := r.state.articles[id]
+	if !ok || (a.DeletedAt.Valid && !includeDeleted) {
+		r.logger(ctx).Warn("article not found", zap.Int("id", int(id)))
+		return nil, services.ErrNotFound
+	}
+	return loaded(a), nil
+}
+
+// GetBySlug retrieves the live article whose current or former slug is slug.
+func (r *MemoryRepo) GetBySlug(ctx context.Context, slug string) (*entities.Article, error) {
+	defer r.rlock(ctx)()
+
+	s := r.state
+	for _, a := range s.articles {
+		if a.Slug == slug && !a.DeletedAt.Valid {
+			return loaded(a), nil
+		}
+	}
+	if id, ok := s.slugs[slug]; ok {
+		if a, ok := s.articles[id]; ok && !a.DeletedAt.Valid {
+			return loaded(a), nil
+		}
+	}
+	r.logger(ctx).Warn("article not found", zap.String("slug", slug))
+	return nil, services.ErrNotFound
+}
+
+// Update overwrites the mutable fields of a live article, provided its
+// stored version still equals a.Version, and behaves like PostgresRepo.Update
+// otherwise: the version is incremented, ownership and publication state are
+// kept, labels are replaced, a changed slug is made unique with the previous
+// one kept for redirects, and the new state is recorded as a revision.
+// Returns services.ErrNotFound if no article with the given ID and version exists.
+func (r *MemoryRepo) Update(ctx context.Context, a *entities.Article) error {
+	defer r.lock(ctx)()
+
+	s := r.state
+	current, ok := s.articles[a.ID]
+	if !ok || current.DeletedAt.Valid || current.Version != a.Version {
+		r.logger(ctx).Warn("article not found for update", zap.Uint("id", a.ID), zap.Uint("version", a.Version))
+		return services.ErrNotFound
+	}
+
+	s.renameSlug(a, current.Slug)
+	a.Version++
+	a.UpdatedAt = r.now()
+	s.saveLabels(a)
+
+	updated := stored(a)
+	updated.AuthorID = current.AuthorID
+	updated.Status = current.Status
+	updated.PublishedAt = current.PublishedAt
+	updated.PublishAt = current.PublishAt
+	updated.CreatedAt = current.CreatedAt
+	updated.DeletedAt = current.DeletedAt
+	s.articles[a.ID] = updated
+
+	r.recordRevision(ctx, a)
+	return nil
+}
+
+// Delete soft-deletes an article with the given version, increments its
+// version and records the deleted state as a revision.
+// Returns services.ErrNotFound if no such article exists or it is already deleted.
+func (r *MemoryRepo) Delete(ctx context.Context, id uint, version uint) error {
+	defer r.lock(ctx)()
+
+	a, ok := r.state.articles[id]
+	if !ok || a.DeletedAt.Valid || a.Version != version {
+		r.logger(ctx).Warn("article not found for delete", zap.Uint("id", id))
+		return services.ErrNotFound
+	}
+	now := r.now()
+	a.DeletedAt = gorm.DeletedAt{Time: now, Valid: true}
+	a.Version++
+	a.UpdatedAt = now
+	r.recordRevision(ctx, a)
+	return nil
+}
+
+// Restore clears the deletion mark of a soft-deleted article with the given
+// version, increments its version and records the restored state as a revision.
+// Returns services.ErrNotFound if there is no such deleted article.
+func (r *MemoryRepo) Restore(ctx context.Context, id uint, version uint) error {
+	defer r.lock(ctx)()
+
+	a, ok := r.state.articles[id]
+	if !ok || !a.DeletedAt.Valid || a.Version != version {
+		r.logger(ctx).Warn("deleted article not found for restore", zap.Uint("id", id))
+		return services.ErrNotFound
+	}
+	a.DeletedAt = gorm.DeletedAt{}
+	a.Version++
+	a.UpdatedAt = r.now()
+	r.recordRevision(ctx, a)
+	return nil
+}
+
+// UpdateStatus persists the publication state of a live article, provided its
+// stored version still equals a.Version, and records it as a revision.
+// On success a.Version is incremented.
+// Returns services.ErrNotFound if no article with the given ID and version exists.
+func (r *MemoryRepo) UpdateStatus(ctx context.Context, a *entities.Article) error {
+	defer r.lock(ctx)()
+
+	current, ok := r.state.articles[a.ID]
+	if !ok || current.DeletedAt.Valid || current.Version != a.Version {
+		r.logger(ctx).Warn("article not found for status update", zap.Uint("id", a.ID), zap.Uint("version", a.Version))
+		return services.ErrNotFound
+	}
+
+	a.Version++
+	current.Status = a.Status
+	current.PublishedAt = clonePtr(a.PublishedAt)
+	current.PublishAt = clonePtr(a.PublishAt)
+	current.Version = a.Version
+	current.UpdatedAt = r.now()
+	r.recordRevision(ctx, a)
+	return nil
+}
+
+// PublishDue publishes up to limit live articles under review whose
+// publish_at is not after now, earliest first, records a revision for each
+// and returns their IDs.
+func (r *MemoryRepo) PublishDue(ctx context.Context, now time.Time, limit int) ([]uint, error) {
+	defer r.lock(ctx)()
+
+	var due []*entities.Article
+	for _, a := range r.state.articles {
+		if scheduled(a) && !a.PublishAt.After(now) {
+			due = append(due, a)
+		}
+	}
+	slices.SortFunc(due, func(a, b *entities.Article) int {
+		return cmp.Or(a.PublishAt.Compare(*b.PublishAt), cmp.Compare(a.ID, b.ID))
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	ids := make([]uint, 0, len(due))
+	for _, a := range due {
+		published := now
+		a.Status = entities.StatusPublished
+		a.PublishedAt = &published
+		a.PublishAt = nil
+		a.Version++
+		a.UpdatedAt = r.now()
+		r.recordRevision(ctx, a)
+		ids = append(ids, a.ID)
+	}
+	return ids, nil
+}
+
+// CountScheduled returns the number of live articles waiting for their publish_at.
+func (r *MemoryRepo) CountScheduled(ctx context.Context) (int64, error) {
+	defer r.rlock(ctx)()
+
+	var n int64
+	for _, a := range r.state.articles {
+		if scheduled(a) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// Purge permanently removes articles soft-deleted before the given time,
+// together with their revisions and former slugs, and returns how many
+// articles were removed.
+func (r *MemoryRepo) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	defer r.lock(ctx)()
+
+	s := r.state
+	var n int64
+	for id, a := range s.articles {
+		if !a.DeletedAt.Valid || !a.DeletedAt.Time.Before(deletedBefore) {
+			continue
+		}
+		delete(s.articles, id)
+		delete(s.revisions, id)
+		for slug, owner := range s.slugs {
+			if owner == id {
+				delete(s.slugs, slug)
+			}
+		}
+		n++
+	}
+	return n, nil
+}
+
+// List returns a page of articles matching the filter together with the
+// total number of matching articles, ignoring pagination.
+func (r *MemoryRepo) List(ctx context.Context, f entities.ArticleFilter) ([]entities.Article, int64, error) {
+	defer r.rlock(ctx)()
+
+	var matching []*entities.Article
+	for _, a := range r.state.articles {
+		if matchesFilter(a, f) {
+			matching = append(matching, a)
+		}
+	}
+	total := int64(len(matching))
+
+	if f.Sort == "" {
+		f.Sort = entities.SortCreatedAtDesc
+	}
+	byCreatedAt := f.Sort.Field() == "created_at"
+	order := func(a *entities.Article, id uint, createdAt time.Time) int {
+		c := cmp.Compare(a.ID, id)
+		if byCreatedAt {
+			c = cmp.Or(a.CreatedAt.Compare(createdAt), c)
+		}
+		if f.Sort.Descending() {
+			return -c
+		}
+		return c
+	}
+	slices.SortFunc(matching, func(a, b *entities.Article) int {
+		return order(a, b.ID, b.CreatedAt)
+	})
+
+	if f.After != nil {
+		matching = slices.DeleteFunc(matching, func(a *entities.Article) bool {
+			return order(a, f.After.ID, f.After.CreatedAt) <= 0
+		})
+	} else {
+		matching = matching[min(f.Offset, len(matching)):]
+	}
+	if f.Limit > 0 && len(matching) > f.Limit {
+		matching = matching[:f.Limit]
+	}
+
+	articles := make([]entities.Article, 0, len(matching))
+	for _, a := range matching {
+		articles = append(articles, *loaded(a))
+	}
+	return articles, total, nil
+}
+
+// Search returns live articles containing the words of the query in their
+// title, summary or body, most mentions first, together with the total
+// number of matches. The query supports the web search syntax of
+// PostgresRepo.Search, but words are matched case-insensitively as they
+// are, without stemming or stop words.
+func (r *MemoryRepo) Search(ctx context.Context, s entities.ArticleSearch) ([]entities.ArticleSearchHit, int64, error) {
+	defer r.rlock(ctx)()
+
+	query := parseWebSearch(s.Query)
+	var hits []entities.ArticleSearchHit
+	for _, a := range r.state.articles {
+		if a.DeletedAt.Valid || (s.Status != "" && a.Status != s.Status) {
+			continue
+		}
+		rank, ok := query.rank(words(a.Title + " " + a.Summary + " " + a.Body))
+		if !ok {
+			continue
+		}
+		hits = append(hits, entities.ArticleSearchHit{Article: *loaded(a), Rank: rank, Headline: query.headline(a.Body)})
+	}
+	total := int64(len(hits))
+
+	slices.SortFunc(hits, func(a, b entities.ArticleSearchHit) int {
+		return cmp.Or(cmp.Compare(b.Rank, a.Rank), cmp.Compare(b.Article.ID, a.Article.ID))
+	})
+	hits = hits[min(s.Offset, len(hits)):]
+	if s.Limit > 0 && len(hits) > s.Limit {
+		hits = hits[:s.Limit]
+	}
+	if hits == nil {
+		hits = []entities.ArticleSearchHit{}
+	}
+	return hits, total, nil
+}
+
+// TagCounts returns every tag with the number of published, live articles
+// using it, most used first.
+func (r *MemoryRepo) TagCounts(ctx context.Context) ([]entities.TagCount, error) {
+	defer r.rlock(ctx)()
+
+	counts := make(map[string]int64, len(r.state.tags))
+	for name := range r.state.tags {
+		counts[name] = 0
+	}
+	for _, a := range r.state.articles {
+		if a.DeletedAt.Valid || a.Status != entities.StatusPublished {
+			continue
+		}
+		for _, t := range a.Tags {
+			counts[t.Name]++
+		}
+	}
+
+	result := make([]entities.TagCount, 0, len(counts))
+	for name, n := range counts {
+		result = append(result, entities.TagCount{Name: name, Count: n})
+	}
+	slices.SortFunc(result, func(a, b entities.TagCount) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Name, b.Name))
+	})
+	return result, nil
+}
+
+// ListRevisions returns a page of the revisions of an article, newest first,
+// together with the total number of revisions.
+func (r *MemoryRepo) ListRevisions(ctx context.Context, articleID uint, limit, offset int) ([]entities.ArticleRevision, int64, error) {
+	defer r.rlock(ctx)()
+
+	revisions := slices.Clone(r.state.revisions[articleID])
+	slices.Reverse(revisions)
+	total := int64(len(revisions))
+
+	revisions = revisions[min(offset, len(revisions)):]
+	if limit > 0 && len(revisions) > limit {
+		revisions = revisions[:limit]
+	}
+	return revisions, total, nil
+}
+
+// GetRevision retrieves the revision of an article at the given version.
+func (r *MemoryRepo) GetRevision(ctx context.Context, articleID uint, version uint) (*entities.ArticleRevision, error) {
+	defer r.rlock(ctx)()
+
+	for _, rev := range r.state.revisions[articleID] {
+		if rev.Version == version {
+			return &rev, nil
+		}
+	}
+	return nil, services.ErrNotFound
+}
+
+// ownsArticles reports whether any article, deleted or not, belongs to the
+// author. The caller holds the read lock.
+func (r *MemoryRepo) ownsArticles(authorID uint) bool {
+	for _, a := range r.state.articles {
+		if a.AuthorID != nil && *a.AuthorID == authorID {
+			return true
+		}
+	}
+	return false
+}
+
+// recordRevision snapshots a as of its current version. The caller holds
+// the write lock; a must carry its tags and categories.
+func (r *MemoryRepo) recordRevision(ctx context.Context, a *entities.Article) {
+	s := r.state
+	s.lastRevisionID++
+	rev := entities.ArticleRevision{
+		ID:         s.lastRevisionID,
+		ArticleID:  a.ID,
+		Version:    a.Version,
+		Title:      a.Title,
+		Summary:    a.Summary,
+		Body:       a.Body,
+		Status:     a.Status,
+		Tags:       make([]string, 0, len(a.Tags)),
+		Categories: make([]string, 0, len(a.Categories)),
+		CreatedAt:  r.now(),
+	}
+	for _, t := range a.Tags {
+		rev.Tags = append(rev.Tags, t.Name)
+	}
+	for _, c := range a.Categories {
+		rev.Categories = append(rev.Categories, c.Name)
+	}
+	if p, ok := auth.FromContext(ctx); ok {
+		rev.CreatedBy = p.Subject
+	}
+	s.revisions[a.ID] = append(s.revisions[a.ID], rev)
+}
+
+// uniqueSlug returns base, or base with the lowest numeric suffix from 2 on,
+// so that it is neither the current nor a former slug of any article but id,
+// nor one of the reserved slugs.
+func (s *memoryState) uniqueSlug(base string, id uint, reserved map[string]bool) string {
+	taken := func(slug string) bool {
+		if owner, ok := s.slugs[slug]; ok && owner != id {
+			return true
+		}
+		for _, a := range s.articles {
+			if a.ID != id && a.Slug == slug {
+				return true
+			}
+		}
+		return reserved[slug]
+	}
+
+	slug := base
+	for n := 2; taken(slug); n++ {
+		slug = fmt.Sprintf("%s-%d", base, n)
+	}
+	return slug
+}
+
+// renameSlug makes the slug set on a unique if it differs from current,
+// which is then kept as a former slug of the article. An empty a.Slug keeps
+// the current slug.
+func (s *memoryState) renameSlug(a *entities.Article, current string) {
+	if a.Slug == "" {
+		a.Slug = current
+	}
+	if a.Slug == current {
+		return
+	}
+
+	a.Slug = s.uniqueSlug(a.Slug, a.ID, nil)
+	if a.Slug == current {
+		return
+	}
+	if _, ok := s.slugs[current]; !ok {
+		s.slugs[current] = a.ID
+	}
+	// the article may be taking back one of its own former slugs
+	delete(s.slugs, a.Slug)
+}
+
+// saveLabels makes sure the tags and categories of a exist and fills in their IDs.
+func (s *memoryState) saveLabels(a *entities.Article) {
+	for i, t := range a.Tags {
+		id, ok := s.tags[t.Name]
+		if !ok {
+			s.lastTagID++
+			id = s.lastTagID
+			s.tags[t.Name] = id
+		}
+		a.Tags[i].ID = id
+	}
+	for i, c := range a.Categories {
+		id, ok := s.categories[c.Name]
+		if !ok {
+			s.lastCategoryID++
+			id = s.lastCategoryID
+			s.categories[c.Name] = id
+		}
+		a.Categories[i].ID = id
+	}
+}
+
+// clone returns a deep copy of s to roll back to.
+func (s *memoryState) clone() *memoryState {
+	c := *s
+	c.articles = make(map[uint]*entities.Article, len(s.articles))
+	for id, a := range s.articles {
+		c.articles[id] = cloneArticle(a)
+	}
+	c.slugs = maps.Clone(s.slugs)
+	c.tags = maps.Clone(s.tags)
+	c.categories = maps.Clone(s.categories)
+	// recorded revisions are never modified, appending to a copy is enough
+	c.revisions = make(map[uint][]entities.ArticleRevision, len(s.revisions))
+	for id, revs := range s.revisions {
+		c.revisions[id] = slices.Clip(revs)
+	}
+	return &c
+}
+
+// rollback restores the rows of saved, keeping the sequences where they are.
+func (s *memoryState) rollback(saved *memoryState) {
+	s.articles = saved.articles
+	s.slugs = saved.slugs
+	s.revisions = saved.revisions
+	s.tags = saved.tags
+	s.categories = saved.categories
+}
+
+// matchesFilter reports whether a passes the conditions of f, ignoring pagination.
+func matchesFilter(a *entities.Article, f entities.ArticleFilter) bool {
+	switch {
+	case a.DeletedAt.Valid && !f.IncludeDeleted,
+		f.Title != "" && !strings.Contains(strings.ToLower(a.Title), strings.ToLower(f.Title)),
+		f.AuthorID != 0 && (a.AuthorID == nil || *a.AuthorID != f.AuthorID),
+		f.Status != "" && a.Status != f.Status,
+		!f.CreatedFrom.IsZero() && a.CreatedAt.Before(f.CreatedFrom),
+		!f.CreatedTo.IsZero() && a.CreatedAt.After(f.CreatedTo):
+		return false
+	}
+
+	if len(f.Tags) > 0 {
+		carries := func(name string) bool {
+			return slices.ContainsFunc(a.Tags, func(t entities.Tag) bool { return t.Name == name })
+		}
+		if f.TagMatch == entities.TagMatchAll {
+			for _, name := range f.Tags {
+				if !carries(name) {
+					return false
+				}
+			}
+		} else if !slices.ContainsFunc(f.Tags, carries) {
+			return false
+		}
+	}
+	if f.Category != "" && !slices.ContainsFunc(a.Categories, func(c entities.Category) bool { return c.Name == f.Category }) {
+		return false
+	}
+	return true
+}
+
+// scheduled reports whether a is a live article under review waiting for its publish_at.
+func scheduled(a *entities.Article) bool {
+	return !a.DeletedAt.Valid && a.Status == entities.StatusInReview && a.PublishAt != nil
+}
+
+// stored returns the copy of a kept by the repository, with labels ordered by name.
+func stored(a *entities.Article) *entities.Article {
+	c := cloneArticle(a)
+	slices.SortFunc(c.Tags, func(x, y entities.Tag) int { return cmp.Compare(x.Name, y.Name) })
+	slices.SortFunc(c.Categories, func(x, y entities.Category) int { return cmp.Compare(x.Name, y.Name) })
+	return c
+}
+
+// loaded returns the copy of a handed out to callers. Like preloading,
+// it always carries label slices, empty ones for articles without labels.
+func loaded(a *entities.Article) *entities.Article {
+	c := cloneArticle(a)
+	if c.Tags == nil {
+		c.Tags = []entities.Tag{}
+	}
+	if c.Categories == nil {
+		c.Categories = []entities.Category{}
+	}
+	return c
+}
+
+func cloneArticle(a *entities.Article) *entities.Article {
+	c := *a
+	c.AuthorID = clonePtr(a.AuthorID)
+	c.PublishedAt = clonePtr(a.PublishedAt)
+	c.PublishAt = clonePtr(a.PublishAt)
+	c.Tags = slices.Clone(a.Tags)
+	c.Categories = slices.Clone(a.Categories)
+	return &c
+}
+
+func clonePtr[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/antonchaban/articles-go/internal/entities"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// SQLiteRepo implements the ArticleRepository interface using SQLite as the
+// data store, for installations that can't run PostgreSQL. It shares the
+// queries of PostgresRepo, which SQLite understands as well, except for
+// full-text search, which runs on the articles_fts index instead.
+//
+// SQLite stores times as text, so times passed in are converted to UTC,
+// like the ones the database connection generates itself.
+type SQLiteRepo struct {
+	*PostgresRepo
+}
+
+func NewSQLiteRepo(db *gorm.DB, logger *zap.Logger) *SQLiteRepo {
+	return &SQLiteRepo{PostgresRepo: NewPostgresRepo(db, logger)}
+}
+
+// List returns a page of articles matching the filter together with the
+// total number of matching articles, ignoring pagination.
+func (r *SQLiteRepo) List(ctx context.Context, f entities.ArticleFilter) ([]entities.Article, int64, error) {
+	f.CreatedFrom = f.CreatedFrom.UTC()
+	f.CreatedTo = f.CreatedTo.UTC()
+	if f.After != nil {
+		after := *f.After
+		after.CreatedAt = after.CreatedAt.UTC()
+		f.After = &after
+	}
+	return r.PostgresRepo.List(ctx, f)
+}
+
+// Search returns live articles matching the query, most relevant first,
+// together with the total number of matches. Matches in the title weigh
+// more than matches in the summary, which weigh more than ones in the body.
+// Words are stemmed as English whatever s.Language is.
+func (r *SQLiteRepo) Search(ctx context.Context, s entities.ArticleSearch) ([]entities.ArticleSearchHit, int64, error) {
+	match := parseWebSearch(s.Query).fts5()
+	if match == "" {
+		return []entities.ArticleSearchHit{}, 0, nil
+	}
+	matching := func() *gorm.DB {
+		q := r.conn(ctx).Table("articles_fts").
+			Joins("JOIN articles ON articles.id = articles_fts.rowid").
+			Where("articles_fts MATCH ? AND articles.deleted_at IS NULL", match)
+		if s.Status != "" {
+			q = q.Where("articles.status = ?", s.Status)
+		}
+		return q
+	}
+
+	var total int64
+	if err := matching().Count(&total).Error; err != nil {
+		r.logger(ctx).Error("failed to count search results", zap.Error(err))
+		return nil, 0, translate(err)
+	}
+
+	// bm25 scores better matches lower
+	var ranked []rankedHit
+	err := matching().
+		Select("articles.id AS id, -bm25(articles_fts, 4.0, 2.0, 1.0) AS rank, snippet(articles_fts, 2, ?, ?, ' … ', 30) AS headline",
+			entities.HeadlineStart, entities.HeadlineStop).
+		Order("rank DESC, articles.id DESC").
+		Limit(s.Limit).Offset(s.Offset).
+		Scan(&ranked).Error
+	if err != nil {
+		r.logger(ctx).Error("failed to search articles", zap.Error(err))
+		return nil, 0, translate(err)
+	}
+	hits, err := r.loadHits(ctx, ranked)
+	if err != nil {
+		return nil, 0, err
+	}
+	return hits, total, nil
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/antonchaban/articles-go/internal/entities"
+)
+
+// webSearch is a parsed web search query: alternatives separated by "or",
+// each matching text that contains all of its terms and none of its
+// excluded terms. A term is a word or a quoted phrase.
+type webSearch [][]searchTerm
+
+type searchTerm struct {
+	words   []string
+	exclude bool
+}
+
+// parseWebSearch parses the web search syntax of websearch_to_tsquery.
+func parseWebSearch(q string) webSearch {
+	var (
+		query       webSearch
+		alternative []searchTerm
+	)
+	for len(q) > 0 {
+		q = strings.TrimLeftFunc(q, unicode.IsSpace)
+		exclude := strings.HasPrefix(q, "-")
+		if exclude {
+			q = q[1:]
+		}
+
+		var token string
+		if rest, ok := strings.CutPrefix(q, `"`); ok {
+			token, q, _ = strings.Cut(rest, `"`)
+		} else {
+			end := strings.IndexFunc(q, unicode.IsSpace)
+			if end < 0 {
+				end = len(q)
+			}
+			token, q = q[:end], q[end:]
+			if strings.EqualFold(token, "or") && !exclude {
+				query, alternative = append(query, alternative), nil
+				continue
+			}
+		}
+		if w := words(token); len(w) > 0 {
+			alternative = append(alternative, searchTerm{words: w, exclude: exclude})
+		}
+	}
+	return append(query, alternative)
+}
+
+// fts5 returns q as an SQLite FTS5 query, or "" if nothing can match. FTS5
+// can only exclude terms from others, so alternatives without a term to
+// look for are left out.
+func (q webSearch) fts5() string {
+	var alternatives []string
+	for _, alternative := range q {
+		var include, exclude []string
+		for _, t := range alternative {
+			// words consist of letters and digits only and need no escaping
+			phrase := `"` + strings.Join(t.words, " ") + `"`
+			if t.exclude {
+				exclude = append(exclude, phrase)
+			} else {
+				include = append(include, phrase)
+			}
+		}
+		if len(include) == 0 {
+			continue
+		}
+		expr := "(" + strings.Join(include, " AND ") + ")"
+		for _, e := range exclude {
+			expr += " NOT " + e
+		}
+		alternatives = append(alternatives, "("+expr+")")
+	}
+	return strings.Join(alternatives, " OR ")
+}
+
+// rank reports whether text matches q, and how often the terms of the
+// matching alternatives occur in it.
+func (q webSearch) rank(text []string) (float64, bool) {
+	var (
+		rank    float64
+		matched bool
+	)
+	for _, alternative := range q {
+		n, ok := 0, len(alternative) > 0
+		for _, t := range alternative {
+			found := t.count(text)
+			if t.exclude == (found > 0) {
+				ok = false
+				break
+			}
+			n += found
+		}
+		if ok {
+			matched = true
+			rank += float64(n)
+		}
+	}
+	return rank, matched
+}
+
+// headline returns up to 30 words of body starting shortly before the first
+// word of a term, with the words of terms wrapped in the headline markers.
+func (q webSearch) headline(body string) string {
+	marked := map[string]bool{}
+	for _, alternative := range q {
+		for _, t := range alternative {
+			if !t.exclude {
+				for _, w := range t.words {
+					marked[w] = true
+				}
+			}
+		}
+	}
+
+	fields := strings.Fields(body)
+	start := 0
+	for i, f := range fields {
+		if w := words(f); len(w) > 0 && marked[w[0]] {
+			start = max(i-5, 0)
+			break
+		}
+	}
+	fields = fields[start:min(start+30, len(fields))]
+	for i, f := range fields {
+		if w := words(f); len(w) > 0 && marked[w[0]] {
+			fields[i] = entities.HeadlineStart + f + entities.HeadlineStop
+		}
+	}
+	return strings.Join(fields, " ")
+}
+
+// count returns how often the words of t occur in text one after another.
+func (t searchTerm) count(text []string) int {
+	n := 0
+	for i := 0; i+len(t.words) <= len(text); i++ {
+		if slices.Equal(text[i:i+len(t.words)], t.words) {
+			n++
+		}
+	}
+	return n
+}
+
+// words splits s into lower-cased words of letters and digits.
+func words(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
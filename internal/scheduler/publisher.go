@@ -0,0 +1,123 @@
+// Package scheduler runs background jobs: it publishes articles whose
+// scheduled publication time has come and deletes expired records.
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+var (
+	scheduledArticles = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "articles_scheduled",
+			Help: "Number of articles waiting for their scheduled publication time",
+		},
+	)
+
+	scheduledPublishedTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "articles_scheduled_published_total",
+			Help: "Total number of articles published by the scheduler",
+		},
+	)
+
+	schedulerErrorsTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "articles_scheduler_errors_total",
+			Help: "Total number of failed scheduler polls",
+		},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(scheduledArticles)
+	prometheus.MustRegister(scheduledPublishedTotal)
+	prometheus.MustRegister(schedulerErrorsTotal)
+}
+
+// Store is the storage the Publisher works on.
+type Store interface {
+	// PublishDue publishes up to limit due articles and returns their IDs.
+	// Implementations must make sure concurrent callers, possibly in other
+	// replicas, never claim the same article.
+	PublishDue(ctx context.Context, now time.Time, limit int) ([]uint, error)
+	// CountScheduled returns the number of articles still waiting to be published.
+	CountScheduled(ctx context.Context) (int64, error)
+}
+
+// Publisher periodically publishes scheduled articles that are due.
+type Publisher struct {
+	store    Store
+	interval time.Duration
+	batch    int
+	now      func() time.Time
+	log      *zap.Logger
+}
+
+// NewPublisher creates a Publisher polling store every interval and
+// publishing at most batch articles per transaction. Both must be positive.
+func NewPublisher(store Store, interval time.Duration, batch int, logger *zap.Logger) *Publisher {
+	return &Publisher{
+		store:    store,
+		interval: interval,
+		batch:    batch,
+		now:      func() time.Time { return time.Now().UTC() },
+		log:      logger.With(zap.String("layer", "scheduler")),
+	}
+}
+
+// Run polls until ctx is cancelled. A poll in progress is allowed to finish
+// so that no batch is abandoned halfway; Run returns afterwards.
+func (p *Publisher) Run(ctx context.Context) {
+	p.log.Info("scheduler started", zap.Duration("interval", p.interval), zap.Int("batch", p.batch))
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		p.poll(ctx)
+
+		select {
+		case <-ctx.Done():
+			p.log.Info("scheduler stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll publishes due articles batch by batch until none are left, then
+// refreshes the scheduled gauge.
+func (p *Publisher) poll(ctx context.Context) {
+	// a started poll outlives cancellation but not the next tick
+	pollCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), p.interval)
+	defer cancel()
+
+	for {
+		ids, err := p.store.PublishDue(pollCtx, p.now(), p.batch)
+		if err != nil {
+			schedulerErrorsTotal.Inc()
+			p.log.Error("failed to publish due articles", zap.Error(err))
+			return
+		}
+		if len(ids) > 0 {
+			scheduledPublishedTotal.Add(float64(len(ids)))
+			p.log.Info("published scheduled articles", zap.Uints("ids", ids))
+		}
+		if len(ids) < p.batch || ctx.Err() != nil {
+			break
+		}
+	}
+
+	n, err := p.store.CountScheduled(pollCtx)
+	if err != nil {
+		schedulerErrorsTotal.Inc()
+		p.log.Warn("failed to count scheduled articles", zap.Error(err))
+		return
+	}
+	scheduledArticles.Set(float64(n))
+}
@@ -0,0 +1,89 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type fakeStore struct {
+	mu        sync.Mutex
+	due       []uint
+	calls     int
+	scheduled int64
+	err       error
+}
+
+func (s *fakeStore) PublishDue(_ context.Context, _ time.Time, limit int) ([]uint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+	n := min(limit, len(s.due))
+	ids := s.due[:n]
+	s.due = s.due[n:]
+	return ids, nil
+}
+
+func (s *fakeStore) CountScheduled(context.Context) (int64, error) {
+	return s.scheduled, nil
+}
+
+func TestPollDrainsAllDueBatches(t *testing.T) {
+	store := &fakeStore{due: []uint{1, 2, 3, 4, 5}, scheduled: 7}
+	p := NewPublisher(store, time.Minute, 2, zap.NewNop())
+
+	before := testutil.ToFloat64(scheduledPublishedTotal)
+	p.poll(context.Background())
+
+	// two full batches, then a partial one ends the poll
+	assert.Equal(t, 3, store.calls)
+	assert.Empty(t, store.due)
+	assert.Equal(t, float64(5), testutil.ToFloat64(scheduledPublishedTotal)-before)
+	assert.Equal(t, float64(7), testutil.ToFloat64(scheduledArticles))
+}
+
+func TestPollCountsErrors(t *testing.T) {
+	store := &fakeStore{err: errors.New("db down")}
+	p := NewPublisher(store, time.Minute, 2, zap.NewNop())
+
+	before := testutil.ToFloat64(schedulerErrorsTotal)
+	p.poll(context.Background())
+
+	assert.Equal(t, 1, store.calls)
+	assert.Equal(t, float64(1), testutil.ToFloat64(schedulerErrorsTotal)-before)
+}
+
+func TestRunStopsOnCancel(t *testing.T) {
+	store := &fakeStore{}
+	p := NewPublisher(store, 10*time.Millisecond, 10, zap.NewNop())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		p.Run(ctx)
+		close(done)
+	}()
+
+	time.Sleep(35 * time.Millisecond)
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("scheduler did not stop after cancellation")
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	assert.GreaterOrEqual(t, store.calls, 2)
+}
@@ -0,0 +1,66 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// SweepFunc deletes the records that expired by now and returns how many it deleted.
+type SweepFunc func(ctx context.Context, now time.Time) (int64, error)
+
+// Sweeper periodically deletes expired records, like stale idempotency keys.
+type Sweeper struct {
+	name     string
+	sweep    SweepFunc
+	interval time.Duration
+	now      func() time.Time
+	log      *zap.Logger
+}
+
+// NewSweeper creates a Sweeper calling sweep every interval, which must be
+// positive. name tells the swept records apart in logs.
+func NewSweeper(name string, sweep SweepFunc, interval time.Duration, logger *zap.Logger) *Sweeper {
+	return &Sweeper{
+		name:     name,
+		sweep:    sweep,
+		interval: interval,
+		now:      func() time.Time { return time.Now().UTC() },
+		log:      logger.With(zap.String("layer", "scheduler"), zap.String("sweeper", name)),
+	}
+}
+
+// Run sweeps until ctx is cancelled, starting right away.
+func (s *Sweeper) Run(ctx context.Context) {
+	s.log.Info("sweeper started", zap.Duration("interval", s.interval))
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.run(ctx)
+
+		select {
+		case <-ctx.Done():
+			s.log.Info("sweeper stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Sweeper) run(ctx context.Context) {
+	sweepCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.interval)
+	defer cancel()
+
+	n, err := s.sweep(sweepCtx, s.now())
+	if err != nil {
+		schedulerErrorsTotal.Inc()
+		s.log.Error("failed to delete expired records", zap.Error(err))
+		return
+	}
+	if n > 0 {
+		s.log.Info("deleted expired records", zap.Int64("count", n))
+	}
+}
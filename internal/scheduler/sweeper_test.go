@@ -0,0 +1,37 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestSweeperPassesCurrentTime(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	var got time.Time
+	s := NewSweeper("test", func(_ context.Context, t time.Time) (int64, error) {
+		got = t
+		return 3, nil
+	}, time.Minute, zap.NewNop())
+	s.now = func() time.Time { return now }
+
+	s.run(context.Background())
+
+	assert.Equal(t, now, got)
+}
+
+func TestSweeperCountsErrors(t *testing.T) {
+	s := NewSweeper("test", func(context.Context, time.Time) (int64, error) {
+		return 0, errors.New("db down")
+	}, time.Minute, zap.NewNop())
+
+	before := testutil.ToFloat64(schedulerErrorsTotal)
+	s.run(context.Background())
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(schedulerErrorsTotal)-before)
+}
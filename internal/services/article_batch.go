@@ -0,0 +1,185 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/antonchaban/articles-go/internal/dto"
+	"github.com/antonchaban/articles-go/internal/entities"
+
+	"go.uber.org/zap"
+)
+
+// batchInsertSize is the number of articles inserted per statement when
+// non-atomic batches create articles.
+const batchInsertSize = 100
+
+// ErrBatchAborted is reported for the operations of an atomic batch that
+// were rolled back or skipped because another operation failed.
+var ErrBatchAborted = &Error{Kind: KindAborted, Code: "batch_aborted", Message: "not applied, another operation of the atomic batch failed"}
+
+// Batch applies the operations of req and reports the outcome of each, in
+// the order they were given.
+//
+// Atomic batches run the operations one after another in a single transaction
+// and stop at the first failure; every other operation then reports
+// ErrBatchAborted. Other batches insert all creations together with
+// CreateInBatches, which succeed or fail as a whole, and then apply updates
+// and deletes independently of each other.
+//
+// The returned error is only set if the batch could not be run at all.
+func (s *ArticleService) Batch(ctx context.Context, req dto.BatchRequest) (_ *dto.BatchResponse, err error) {
+	ctx, span := tracer.Start(ctx, "ArticleService.Batch")
+	defer endSpan(span, &err)
+
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	var items []dto.BatchResult
+	if req.Atomic {
+		items, err = s.batchAtomic(ctx, req.Operations)
+	} else {
+		items = s.batchEach(ctx, req.Operations)
+	}
+	if err != nil {
+		s.logger(ctx).Error("failed to run batch", zap.Int("operations", len(req.Operations)), zap.Error(err))
+		return nil, err
+	}
+
+	failed := 0
+	for _, it := range items {
+		if it.Err != nil {
+			failed++
+		}
+	}
+	s.logger(ctx).Info("batch applied", zap.Int("operations", len(items)), zap.Bool("atomic", req.Atomic), zap.Int("failed", failed))
+
+	return &dto.BatchResponse{Items: items}, nil
+}
+
+// batchAtomic runs ops in a single transaction.
+func (s *ArticleService) batchAtomic(ctx context.Context, ops []dto.BatchOperation) ([]dto.BatchResult, error) {
+	items := make([]dto.BatchResult, len(ops))
+	failed := -1
+	err := s.repo.Transaction(ctx, func(ctx context.Context) error {
+		for i, op := range ops {
+			items[i] = s.runOperation(ctx, i, op)
+			if items[i].Err != nil {
+				failed = i
+				return items[i].Err
+			}
+		}
+		return nil
+	})
+	if err == nil {
+		return items, nil
+	}
+	if failed < 0 {
+		// every operation went through but the transaction didn't
+		return nil, err
+	}
+
+	for i, op := range ops {
+		if i != failed {
+			items[i] = dto.BatchResult{Index: i, Op: op.Op, ID: op.ID, Err: ErrBatchAborted}
+		}
+	}
+	return items, nil
+}
+
+// batchEach inserts the creations of ops at once and then applies the
+// remaining operations one by one.
+func (s *ArticleService) batchEach(ctx context.Context, ops []dto.BatchOperation) []dto.BatchResult {
+	items := make([]dto.BatchResult, len(ops))
+
+	var (
+		articles []*entities.Article
+		created  []int
+	)
+	for i, op := range ops {
+		items[i] = dto.BatchResult{Index: i, Op: op.Op, ID: op.ID}
+		if op.Op != dto.BatchCreate {
+			continue
+		}
+		req, err := decodeOperation[dto.CreateArticleRequest](op)
+		if err != nil {
+			items[i].Err = err
+			continue
+		}
+		article, err := s.newArticle(ctx, req)
+		if err != nil {
+			items[i].Err = err
+			continue
+		}
+		articles = append(articles, article)
+		created = append(created, i)
+	}
+
+	err := s.repo.CreateInBatches(ctx, articles, batchInsertSize)
+	for n, i := range created {
+		if err != nil {
+			items[i].Err = err
+			continue
+		}
+		a := articles[n]
+		items[i].ID, items[i].Slug, items[i].Version = a.ID, a.Slug, a.Version
+	}
+
+	for i, op := range ops {
+		if op.Op != dto.BatchCreate {
+			items[i] = s.runOperation(ctx, i, op)
+		}
+	}
+	return items
+}
+
+// runOperation applies a single operation through the regular service methods.
+func (s *ArticleService) runOperation(ctx context.Context, i int, op dto.BatchOperation) dto.BatchResult {
+	res := dto.BatchResult{Index: i, Op: op.Op, ID: op.ID}
+
+	switch op.Op {
+	case dto.BatchCreate:
+		req, err := decodeOperation[dto.CreateArticleRequest](op)
+		if err != nil {
+			res.Err = err
+			return res
+		}
+		resp, err := s.Create(ctx, req)
+		if err != nil {
+			res.Err = err
+			return res
+		}
+		res.ID, res.Slug, res.Version = resp.ID, resp.Slug, resp.Version
+	case dto.BatchUpdate:
+		req, err := decodeOperation[dto.UpdateArticleRequest](op)
+		if err != nil {
+			res.Err = err
+			return res
+		}
+		resp, err := s.Update(ctx, op.ID, op.Version, req)
+		if err != nil {
+			res.Err = err
+			return res
+		}
+		res.Slug, res.Version = resp.Slug, resp.Version
+	case dto.BatchDelete:
+		res.Err = s.Delete(ctx, op.ID, op.Version)
+	default:
+		res.Err = fmt.Errorf("%w: unknown operation %q", ErrInvalidArticle, op.Op)
+	}
+	return res
+}
+
+// decodeOperation decodes the article carried by op, rejecting unknown fields.
+func decodeOperation[T any](op dto.BatchOperation) (T, error) {
+	var req T
+	dec := json.NewDecoder(bytes.NewReader(op.Article))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		return req, fmt.Errorf("%w: %v", ErrInvalidArticle, err)
+	}
+	return req, nil
+}
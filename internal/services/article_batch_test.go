@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/antonchaban/articles-go/internal/dto"
+	"github.com/antonchaban/articles-go/internal/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestBatchInsertsCreationsTogether(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), zap.NewNop())
+
+	mockRepo.On("CreateInBatches", mock.Anything, mock.MatchedBy(func(as []*entities.Article) bool {
+		return len(as) == 2 && as[0].Title == "First" && as[1].Title == "Second"
+	}), batchInsertSize).Run(func(args mock.Arguments) {
+		for i, a := range args.Get(1).([]*entities.Article) {
+			a.ID = uint(10 + i)
+		}
+	}).Return(nil)
+	mockRepo.On("GetByID", mock.Anything, uint(3), false).
+		Return(&entities.Article{ID: 3, Title: "Old", Version: 2}, nil)
+
+	resp, err := service.Batch(context.Background(), dto.BatchRequest{Operations: []dto.BatchOperation{
+		{Op: dto.BatchCreate, Article: json.RawMessage(`{"title":"First"}`)},
+		{Op: dto.BatchCreate, Article: json.RawMessage(`{"title":" "}`)},
+		{Op: dto.BatchDelete, ID: 3, Version: 1},
+		{Op: dto.BatchCreate, Article: json.RawMessage(`{"title":"Second","tags":["go"]}`)},
+	}})
+
+	require.NoError(t, err)
+	require.Len(t, resp.Items, 4)
+	assert.Equal(t, dto.BatchResult{Index: 0, Op: dto.BatchCreate, ID: 10, Slug: "first", Version: 1}, resp.Items[0])
+	assert.ErrorIs(t, resp.Items[1].Err, ErrInvalidArticle)
+	assert.ErrorIs(t, resp.Items[2].Err, ErrVersionConflict)
+	assert.Equal(t, dto.BatchResult{Index: 3, Op: dto.BatchCreate, ID: 11, Slug: "second", Version: 1}, resp.Items[3])
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "Transaction", mock.Anything)
+}
+
+func TestBatchReportsFailedCreationsForEachItem(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), zap.NewNop())
+
+	mockRepo.On("CreateInBatches", mock.Anything, mock.Anything, batchInsertSize).Return(ErrUnavailable)
+
+	resp, err := service.Batch(context.Background(), dto.BatchRequest{Operations: []dto.BatchOperation{
+		{Op: dto.BatchCreate, Article: json.RawMessage(`{"title":"First"}`)},
+		{Op: dto.BatchCreate, Article: json.RawMessage(`{"title":"Second"}`)},
+	}})
+
+	require.NoError(t, err)
+	for _, it := range resp.Items {
+		assert.ErrorIs(t, it.Err, ErrUnavailable)
+	}
+}
+
+func TestAtomicBatchRunsInTransaction(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), zap.NewNop())
+
+	mockRepo.On("Transaction", mock.Anything).Return(nil)
+	mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*entities.Article")).
+		Run(func(args mock.Arguments) { args.Get(1).(*entities.Article).ID = 7 }).Return(nil)
+	mockRepo.On("GetByID", mock.Anything, uint(3), false).
+		Return(&entities.Article{ID: 3, Title: "Old", Slug: "old", Version: 2}, nil)
+	mockRepo.On("Update", mock.Anything, mock.AnythingOfType("*entities.Article")).
+		Run(func(args mock.Arguments) { args.Get(1).(*entities.Article).Version++ }).Return(nil)
+
+	resp, err := service.Batch(context.Background(), dto.BatchRequest{Atomic: true, Operations: []dto.BatchOperation{
+		{Op: dto.BatchCreate, Article: json.RawMessage(`{"title":"New"}`)},
+		{Op: dto.BatchUpdate, ID: 3, Version: 2, Article: json.RawMessage(`{"title":"Renamed"}`)},
+	}})
+
+	require.NoError(t, err)
+	assert.Equal(t, []dto.BatchResult{
+		{Index: 0, Op: dto.BatchCreate, ID: 7, Slug: "new", Version: 1},
+		{Index: 1, Op: dto.BatchUpdate, ID: 3, Slug: "renamed", Version: 3},
+	}, resp.Items)
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "CreateInBatches", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAtomicBatchAbortsOnFirstFailure(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), zap.NewNop())
+
+	mockRepo.On("Transaction", mock.Anything).Return(nil)
+	mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*entities.Article")).Return(nil)
+	mockRepo.On("GetByID", mock.Anything, uint(3), false).Return(nil, ErrNotFound)
+
+	resp, err := service.Batch(context.Background(), dto.BatchRequest{Atomic: true, Operations: []dto.BatchOperation{
+		{Op: dto.BatchCreate, Article: json.RawMessage(`{"title":"New"}`)},
+		{Op: dto.BatchDelete, ID: 3, Version: 1},
+		{Op: dto.BatchCreate, Article: json.RawMessage(`{"title":"Never"}`)},
+	}})
+
+	require.NoError(t, err)
+	assert.ErrorIs(t, resp.Items[0].Err, ErrBatchAborted)
+	assert.ErrorIs(t, resp.Items[1].Err, ErrArticleNotFound)
+	assert.ErrorIs(t, resp.Items[2].Err, ErrBatchAborted)
+	mockRepo.AssertNumberOfCalls(t, "Create", 1)
+}
+
+func TestAtomicBatchFailingToCommit(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), zap.NewNop())
+
+	commitErr := errors.New("commit failed")
+	mockRepo.On("Transaction", mock.Anything).Return(commitErr)
+	mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*entities.Article")).Return(nil)
+
+	resp, err := service.Batch(context.Background(), dto.BatchRequest{Atomic: true, Operations: []dto.BatchOperation{
+		{Op: dto.BatchCreate, Article: json.RawMessage(`{"title":"New"}`)},
+	}})
+
+	assert.Nil(t, resp)
+	assert.ErrorIs(t, err, commitErr)
+}
+
+func TestBatchRejectsMalformedOperations(t *testing.T) {
+	service := NewArticleService(new(MockArticleRepository), new(MockAuthorRepository), zap.NewNop())
+
+	for name, ops := range map[string][]dto.BatchOperation{
+		"empty":          nil,
+		"unknown op":     {{Op: "upsert", Article: json.RawMessage(`{}`)}},
+		"update no id":   {{Op: dto.BatchUpdate, Version: 1, Article: json.RawMessage(`{"title":"x"}`)}},
+		"delete no vers": {{Op: dto.BatchDelete, ID: 1}},
+		"create no body": {{Op: dto.BatchCreate}},
+	} {
+		_, err := service.Batch(context.Background(), dto.BatchRequest{Operations: ops})
+		assert.ErrorIs(t, err, ErrInvalidArticle, name)
+	}
+}
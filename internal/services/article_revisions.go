@@ -0,0 +1,201 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/antonchaban/articles-go/internal/dto"
+	"github.com/antonchaban/articles-go/internal/entities"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"go.uber.org/zap"
+)
+
+// ErrRevisionNotFound is returned when an article has no revision with the requested version.
+var ErrRevisionNotFound = &Error{Kind: KindNotFound, Code: "revision_not_found", Message: "revision not found"}
+
+// ListRevisions returns a page of the revisions of an Article, newest first.
+// The history may contain unpublished states, so only the author and admins
+// may read it.
+func (s *ArticleService) ListRevisions(ctx context.Context, id uint, req dto.ListRevisionsRequest) (_ *dto.ListRevisionsResponse, err error) {
+	ctx, span := tracer.Start(ctx, "ArticleService.ListRevisions")
+	defer endSpan(span, &err)
+
+	if err := s.authorizeHistory(ctx, id); err != nil {
+		return nil, err
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+	if limit > maxListLimit {
+		limit = maxListLimit
+	}
+
+	revisions, total, err := s.repo.ListRevisions(ctx, id, limit, req.Offset)
+	if err != nil {
+		s.logger(ctx).Warn("failed to list revisions", zap.Uint("id", id), zap.Error(err))
+		return nil, err
+	}
+
+	resp := &dto.ListRevisionsResponse{
+		Items: make([]dto.RevisionResponse, 0, len(revisions)),
+		Meta: dto.ListMeta{
+			Total:  total,
+			Limit:  limit,
+			Offset: req.Offset,
+		},
+	}
+	for i := range revisions {
+		rev := toRevisionResponse(&revisions[i])
+		rev.Body = ""
+		resp.Items = append(resp.Items, rev)
+	}
+	return resp, nil
+}
+
+// GetRevision returns an Article as of the given version.
+func (s *ArticleService) GetRevision(ctx context.Context, id uint, version uint) (_ *dto.RevisionResponse, err error) {
+	ctx, span := tracer.Start(ctx, "ArticleService.GetRevision")
+	defer endSpan(span, &err)
+
+	if err := s.authorizeHistory(ctx, id); err != nil {
+		return nil, err
+	}
+
+	rev, err := s.revision(ctx, id, version)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := toRevisionResponse(rev)
+	return &resp, nil
+}
+
+// DiffRevisions returns a unified diff turning revision from into revision to.
+// Title, summary, status and labels are compared as a header above the body.
+func (s *ArticleService) DiffRevisions(ctx context.Context, id uint, from, to uint) (_ *dto.RevisionDiffResponse, err error) {
+	ctx, span := tracer.Start(ctx, "ArticleService.DiffRevisions")
+	defer endSpan(span, &err)
+
+	if err := s.authorizeHistory(ctx, id); err != nil {
+		return nil, err
+	}
+
+	a, err := s.revision(ctx, id, from)
+	if err != nil {
+		return nil, err
+	}
+	b, err := s.revision(ctx, id, to)
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(revisionText(a)),
+		B:        difflib.SplitLines(revisionText(b)),
+		FromFile: fmt.Sprintf("revision %d", from),
+		ToFile:   fmt.Sprintf("revision %d", to),
+		Context:  3,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.RevisionDiffResponse{From: from, To: to, Diff: diff}, nil
+}
+
+// RestoreRevision brings the content of an Article (title, summary, body,
+// tags and categories) back to the given revision. The publication status
+// is left alone and the restored state becomes a new revision.
+// Versioning follows the same rules as Update.
+func (s *ArticleService) RestoreRevision(ctx context.Context, id uint, revision uint, version uint) (_ *dto.ArticleResponse, err error) {
+	ctx, span := tracer.Start(ctx, "ArticleService.RestoreRevision")
+	defer endSpan(span, &err)
+
+	article, err := s.loadVersion(ctx, id, version, false)
+	if err != nil {
+		return nil, err
+	}
+
+	rev, err := s.revision(ctx, id, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger(ctx).Info("restoring article revision", zap.Uint("id", id), zap.Uint("revision", revision))
+
+	return s.apply(ctx, article, dto.UpdateArticleRequest{
+		Title:      rev.Title,
+		Body:       rev.Body,
+		Summary:    rev.Summary,
+		Tags:       rev.Tags,
+		Categories: rev.Categories,
+	})
+}
+
+// authorizeHistory checks that the article exists, soft-deleted or not,
+// and that the caller may modify it.
+func (s *ArticleService) authorizeHistory(ctx context.Context, id uint) error {
+	article, err := s.repo.GetByID(ctx, id, true)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return ErrArticleNotFound
+		}
+		s.logger(ctx).Warn("failed to retrieve article", zap.Uint("id", id), zap.Error(err))
+		return err
+	}
+	return s.authorize(ctx, article)
+}
+
+func (s *ArticleService) revision(ctx context.Context, id uint, version uint) (*entities.ArticleRevision, error) {
+	rev, err := s.repo.GetRevision(ctx, id, version)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrRevisionNotFound
+		}
+		s.logger(ctx).Warn("failed to retrieve revision", zap.Uint("id", id), zap.Uint("version", version), zap.Error(err))
+		return nil, err
+	}
+	return rev, nil
+}
+
+// revisionText renders a revision as the text document revisions are diffed as.
+func revisionText(r *entities.ArticleRevision) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Title: %s\n", r.Title)
+	fmt.Fprintf(&b, "Summary: %s\n", r.Summary)
+	fmt.Fprintf(&b, "Status: %s\n", r.Status)
+	fmt.Fprintf(&b, "Tags: %s\n", strings.Join(r.Tags, ", "))
+	fmt.Fprintf(&b, "Categories: %s\n", strings.Join(r.Categories, ", "))
+	b.WriteString("\n")
+	b.WriteString(r.Body)
+	if r.Body != "" && !strings.HasSuffix(r.Body, "\n") {
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func toRevisionResponse(r *entities.ArticleRevision) dto.RevisionResponse {
+	resp := dto.RevisionResponse{
+		Version:    r.Version,
+		Title:      r.Title,
+		Summary:    r.Summary,
+		Body:       r.Body,
+		Status:     string(r.Status),
+		Tags:       r.Tags,
+		Categories: r.Categories,
+		CreatedBy:  r.CreatedBy,
+		CreatedAt:  r.CreatedAt,
+	}
+	if resp.Tags == nil {
+		resp.Tags = []string{}
+	}
+	if resp.Categories == nil {
+		resp.Categories = []string{}
+	}
+	return resp
+}
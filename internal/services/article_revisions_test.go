@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/antonchaban/articles-go/internal/auth"
+	"github.com/antonchaban/articles-go/internal/dto"
+	"github.com/antonchaban/articles-go/internal/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestListRevisionsOmitsBodies(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), zap.NewNop())
+
+	mockRepo.On("GetByID", mock.Anything, uint(1), true).Return(&entities.Article{ID: 1, Version: 2}, nil)
+	mockRepo.On("ListRevisions", mock.Anything, uint(1), defaultListLimit, 0).Return([]entities.ArticleRevision{
+		{ArticleID: 1, Version: 2, Title: "New", Body: "second"},
+		{ArticleID: 1, Version: 1, Title: "Old", Body: "first"},
+	}, int64(2), nil)
+
+	resp, err := service.ListRevisions(context.Background(), 1, dto.ListRevisionsRequest{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), resp.Meta.Total)
+	assert.Len(t, resp.Items, 2)
+	assert.Equal(t, uint(2), resp.Items[0].Version)
+	assert.Empty(t, resp.Items[0].Body)
+	assert.Equal(t, []string{}, resp.Items[0].Tags)
+}
+
+func TestRevisionHistoryLimitedToAuthor(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	authors := new(MockAuthorRepository)
+	service := NewArticleService(mockRepo, authors, zap.NewNop())
+
+	mockRepo.On("GetByID", mock.Anything, uint(1), true).
+		Return(&entities.Article{ID: 1, AuthorID: ptr(uint(7)), Status: entities.StatusPublished}, nil)
+	authors.On("GetByID", mock.Anything, uint(7)).Return(&entities.Author{ID: 7, Subject: ptr("alice")}, nil)
+
+	_, err := service.GetRevision(withPrincipal("mallory"), 1, 1)
+	assert.ErrorIs(t, err, ErrForbidden)
+
+	_, err = service.GetRevision(auth.WithPrincipal(context.Background(), auth.Anonymous), 1, 1)
+	assert.ErrorIs(t, err, ErrForbidden)
+
+	mockRepo.AssertNotCalled(t, "GetRevision", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetRevisionNotFound(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), zap.NewNop())
+
+	mockRepo.On("GetByID", mock.Anything, uint(1), true).Return(&entities.Article{ID: 1}, nil)
+	mockRepo.On("GetRevision", mock.Anything, uint(1), uint(9)).Return(nil, ErrNotFound)
+	mockRepo.On("GetByID", mock.Anything, uint(2), true).Return(nil, ErrNotFound)
+
+	_, err := service.GetRevision(context.Background(), 1, 9)
+	assert.ErrorIs(t, err, ErrRevisionNotFound)
+
+	_, err = service.GetRevision(context.Background(), 2, 1)
+	assert.ErrorIs(t, err, ErrArticleNotFound)
+}
+
+func TestDiffRevisions(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), zap.NewNop())
+
+	mockRepo.On("GetByID", mock.Anything, uint(1), true).Return(&entities.Article{ID: 1}, nil)
+	mockRepo.On("GetRevision", mock.Anything, uint(1), uint(1)).
+		Return(&entities.ArticleRevision{Version: 1, Title: "Old", Body: "line one\nline two\n"}, nil)
+	mockRepo.On("GetRevision", mock.Anything, uint(1), uint(2)).
+		Return(&entities.ArticleRevision{Version: 2, Title: "New", Body: "line one\nline 2\n", Tags: []string{"go"}}, nil)
+
+	resp, err := service.DiffRevisions(context.Background(), 1, 1, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), resp.From)
+	assert.Equal(t, uint(2), resp.To)
+	assert.Contains(t, resp.Diff, "--- revision 1\n+++ revision 2\n")
+	assert.Contains(t, resp.Diff, "-Title: Old\n+Title: New\n")
+	assert.Contains(t, resp.Diff, "-Tags: \n+Tags: go\n")
+	assert.Contains(t, resp.Diff, " line one\n-line two\n+line 2\n")
+}
+
+func TestRestoreRevisionAppliesContent(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), zap.NewNop())
+
+	mockRepo.On("GetByID", mock.Anything, uint(1), false).
+		Return(&entities.Article{ID: 1, Title: "New", Status: entities.StatusPublished, Version: 3}, nil)
+	mockRepo.On("GetRevision", mock.Anything, uint(1), uint(1)).
+		Return(&entities.ArticleRevision{Version: 1, Title: "Old", Body: "*old*", Status: entities.StatusDraft, Tags: []string{"go"}}, nil)
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(a *entities.Article) bool {
+		return a.Title == "Old" && a.Body == "*old*" && a.BodyHTML == "<p><em>old</em></p>\n" &&
+			len(a.Tags) == 1 && a.Tags[0].Name == "go" &&
+			a.Status == entities.StatusPublished
+	})).Return(nil)
+
+	resp, err := service.RestoreRevision(context.Background(), 1, 1, 3)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Old", resp.Title)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRestoreUnknownRevision(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), zap.NewNop())
+
+	mockRepo.On("GetByID", mock.Anything, uint(1), false).Return(&entities.Article{ID: 1, Title: "Title", Version: 3}, nil)
+	mockRepo.On("GetRevision", mock.Anything, uint(1), uint(7)).Return(nil, ErrNotFound)
+
+	_, err := service.RestoreRevision(context.Background(), 1, 7, 3)
+
+	assert.ErrorIs(t, err, ErrRevisionNotFound)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"html"
+	"strings"
+
+	"github.com/antonchaban/articles-go/internal/dto"
+	"github.com/antonchaban/articles-go/internal/entities"
+
+	"go.uber.org/zap"
+)
+
+// maxSearchQueryLength bounds the length of a full-text search query.
+const maxSearchQueryLength = 256
+
+// ErrInvalidSearch is returned when a search query is empty, too long
+// or names an unsupported language.
+var ErrInvalidSearch = &Error{Kind: KindInvalid, Code: "invalid_search", Message: "invalid search query"}
+
+// searchLanguages are the text search configurations shipped with Postgres.
+// Article and query languages are limited to these so that user input never
+// names an arbitrary regconfig.
+var searchLanguages = map[string]struct{}{
+	"simple": {}, "arabic": {}, "armenian": {}, "basque": {}, "catalan": {},
+	"danish": {}, "dutch": {}, "english": {}, "finnish": {}, "french": {},
+	"german": {}, "greek": {}, "hindi": {}, "hungarian": {}, "indonesian": {},
+	"irish": {}, "italian": {}, "lithuanian": {}, "nepali": {}, "norwegian": {},
+	"portuguese": {}, "romanian": {}, "russian": {}, "serbian": {}, "spanish": {},
+	"swedish": {}, "tamil": {}, "turkish": {}, "yiddish": {},
+}
+
+// normalizeLanguage lower-cases a search language and reports whether it is supported.
+func normalizeLanguage(lang string) (string, bool) {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	_, ok := searchLanguages[lang]
+	return lang, ok
+}
+
+// Search runs a full-text query over published articles and returns the matches
+// ordered by relevance. The query uses web search syntax ("quoted phrases",
+// OR and -negation); lang selects the text search configuration, falling
+// back to defaultLanguage.
+func (s *ArticleService) Search(ctx context.Context, req dto.SearchArticlesRequest, defaultLanguage string) (_ *dto.SearchArticlesResponse, err error) {
+	ctx, span := tracer.Start(ctx, "ArticleService.Search")
+	defer endSpan(span, &err)
+
+	query := strings.TrimSpace(req.Query)
+	if query == "" || len(query) > maxSearchQueryLength {
+		s.logger(ctx).Warn("search attempt with invalid query", zap.Int("length", len(query)))
+		return nil, ErrInvalidSearch
+	}
+
+	lang := req.Language
+	if strings.TrimSpace(lang) == "" {
+		lang = defaultLanguage
+	}
+	lang, ok := normalizeLanguage(lang)
+	if !ok {
+		s.logger(ctx).Warn("search attempt with unsupported language", zap.String("language", req.Language))
+		return nil, ErrInvalidSearch
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+	if limit > maxListLimit {
+		limit = maxListLimit
+	}
+
+	hits, total, err := s.repo.Search(ctx, entities.ArticleSearch{
+		Query:    query,
+		Language: lang,
+		Status:   entities.StatusPublished,
+		Limit:    limit,
+		Offset:   req.Offset,
+	})
+	if err != nil {
+		s.logger(ctx).Warn("failed to search articles", zap.Error(err))
+		return nil, err
+	}
+
+	resp := &dto.SearchArticlesResponse{
+		Items: make([]dto.SearchHitResponse, 0, len(hits)),
+		Meta: dto.ListMeta{
+			Total:  total,
+			Limit:  limit,
+			Offset: req.Offset,
+		},
+	}
+	for i := range hits {
+		resp.Items = append(resp.Items, dto.SearchHitResponse{
+			ArticleResponse: toArticleResponse(&hits[i].Article),
+			Rank:            hits[i].Rank,
+			Headline:        highlight(hits[i].Headline),
+		})
+	}
+
+	return resp, nil
+}
+
+// highlight turns a raw ts_headline snippet into safe HTML. The snippet is
+// produced from the Markdown source, so it is escaped first and only the
+// match markers are turned into <mark> elements.
+func highlight(headline string) string {
+	escaped := html.EscapeString(headline)
+	return strings.NewReplacer(
+		html.EscapeString(entities.HeadlineStart), "<mark>",
+		html.EscapeString(entities.HeadlineStop), "</mark>",
+	).Replace(escaped)
+}
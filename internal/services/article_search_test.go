@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/antonchaban/articles-go/internal/dto"
+	"github.com/antonchaban/articles-go/internal/entities"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestSearchEscapesHeadline(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), zap.NewNop())
+
+	expected := entities.ArticleSearch{Query: "script", Language: "english", Status: entities.StatusPublished, Limit: defaultListLimit}
+	mockRepo.On("Search", mock.Anything, expected).Return([]entities.ArticleSearchHit{{
+		Article:  entities.Article{ID: 1, Title: "XSS"},
+		Rank:     0.3,
+		Headline: "<b>" + entities.HeadlineStart + "script" + entities.HeadlineStop + "</b> & more",
+	}}, int64(1), nil)
+
+	resp, err := service.Search(context.Background(), dto.SearchArticlesRequest{Query: " script "}, "english")
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), resp.Meta.Total)
+	assert.Len(t, resp.Items, 1)
+	assert.Equal(t, "&lt;b&gt;<mark>script</mark>&lt;/b&gt; &amp; more", resp.Items[0].Headline)
+	assert.Equal(t, 0.3, resp.Items[0].Rank)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSearchNormalizesRequestedLanguage(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), zap.NewNop())
+
+	expected := entities.ArticleSearch{Query: "häuser", Language: "german", Status: entities.StatusPublished, Limit: maxListLimit, Offset: 10}
+	mockRepo.On("Search", mock.Anything, expected).Return([]entities.ArticleSearchHit{}, int64(0), nil)
+
+	req := dto.SearchArticlesRequest{Query: "häuser", Language: "German", Limit: 500, Offset: 10}
+	resp, err := service.Search(context.Background(), req, "english")
+
+	assert.NoError(t, err)
+	assert.Empty(t, resp.Items)
+	assert.Equal(t, maxListLimit, resp.Meta.Limit)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSearchRejectsInvalidRequests(t *testing.T) {
+	tests := map[string]dto.SearchArticlesRequest{
+		"blank query":          {Query: "   "},
+		"unsupported language": {Query: "go", Language: "klingon"},
+		"regconfig injection":  {Query: "go", Language: "english'); DROP TABLE articles; --"},
+	}
+
+	for name, req := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockRepo := new(MockArticleRepository)
+			service := NewArticleService(mockRepo, new(MockAuthorRepository), zap.NewNop())
+
+			_, err := service.Search(context.Background(), req, "english")
+
+			assert.True(t, errors.Is(err, ErrInvalidSearch))
+			mockRepo.AssertNotCalled(t, "Search", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestCreateArticleRejectsUnsupportedLanguage(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), zap.NewNop())
+
+	_, err := service.Create(context.Background(), dto.CreateArticleRequest{Title: "Hallo", Language: "martian"})
+
+	assert.True(t, errors.Is(err, ErrInvalidArticle))
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
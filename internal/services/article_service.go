@@ -1,76 +1,984 @@
 package services
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
+	"github.com/antonchaban/articles-go/internal/auth"
 	"github.com/antonchaban/articles-go/internal/dto"
 	"github.com/antonchaban/articles-go/internal/entities"
+	"github.com/antonchaban/articles-go/internal/log"
+	"github.com/antonchaban/articles-go/pkg/markdown"
+	"github.com/antonchaban/articles-go/pkg/slug"
+	"github.com/antonchaban/articles-go/pkg/validation"
 
+	jsonpatch "github.com/evanphx/json-patch/v5"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 // ArticleRepository defines the methods that any
 // data storage provider must implement to manage Articles.
 type ArticleRepository interface {
 	Create(ctx context.Context, article *entities.Article) error
-	GetByID(ctx context.Context, id uint) (*entities.Article, error)
+	GetByID(ctx context.Context, id uint, includeDeleted bool) (*entities.Article, error)
+	// GetBySlug finds an article by its current or a former slug.
+	GetBySlug(ctx context.Context, slug string) (*entities.Article, error)
+	List(ctx context.Context, filter entities.ArticleFilter) ([]entities.Article, int64, error)
+	// Update, Delete and Restore only succeed while the stored version still
+	// matches the given one and increment it.
+	Update(ctx context.Context, article *entities.Article) error
+	Delete(ctx context.Context, id uint, version uint) error
+	Restore(ctx context.Context, id uint, version uint) error
+	UpdateStatus(ctx context.Context, article *entities.Article) error
+	Purge(ctx context.Context, deletedBefore time.Time) (int64, error)
+	TagCounts(ctx context.Context) ([]entities.TagCount, error)
+	Search(ctx context.Context, search entities.ArticleSearch) ([]entities.ArticleSearchHit, int64, error)
+	// CreateInBatches stores several new articles at once, all or none.
+	CreateInBatches(ctx context.Context, articles []*entities.Article, batchSize int) error
+	// Transaction runs fn atomically. Repository calls made with the
+	// context passed to fn are part of the transaction.
+	Transaction(ctx context.Context, fn func(ctx context.Context) error) error
+	ListRevisions(ctx context.Context, articleID uint, limit, offset int) ([]entities.ArticleRevision, int64, error)
+	GetRevision(ctx context.Context, articleID uint, version uint) (*entities.ArticleRevision, error)
 }
 
+const (
+	defaultListLimit = 20
+	maxListLimit     = 100
+
+	// excerptLength is the maximum number of characters in a generated excerpt.
+	excerptLength = 200
+
+	// maxLabels and maxLabelLength bound the tags and categories of an article.
+	maxLabels      = 20
+	maxLabelLength = 50
+)
+
+// AnyVersion disables the version precondition of a mutation,
+// matching the semantics of "If-Match: *".
+const AnyVersion uint = 0
+
+var (
+	// ErrInvalidCursor is returned when a pagination cursor cannot be decoded
+	// or was issued for a different sort order.
+	ErrInvalidCursor = &Error{Kind: KindInvalid, Code: "invalid_cursor", Message: "invalid cursor"}
+
+	// ErrArticleNotFound is returned when the requested article does not exist.
+	ErrArticleNotFound = &Error{Kind: KindNotFound, Code: "article_not_found", Message: "article not found"}
+
+	// ErrInvalidPatch is returned when a patch document is malformed
+	// or its content type is not supported.
+	ErrInvalidPatch = &Error{Kind: KindInvalid, Code: "invalid_patch", Message: "invalid patch document"}
+
+	// ErrInvalidArticle is returned when the resulting article fails validation.
+	ErrInvalidArticle = &Error{Kind: KindValidation, Code: "invalid_article", Message: "invalid article"}
+
+	// ErrVersionConflict is returned when the article was modified since the
+	// version the caller based its change on.
+	ErrVersionConflict = &Error{Kind: KindStale, Code: "version_conflict", Message: "article version conflict"}
+
+	// ErrForbidden is returned when the caller is authenticated but not
+	// allowed to act on the resource, e.g. an article owned by someone else.
+	ErrForbidden = &Error{Kind: KindForbidden, Code: "forbidden", Message: "operation not permitted"}
+
+	// ErrInvalidTransition is returned when the publication workflow does not
+	// allow moving an article from its current status to the requested one.
+	ErrInvalidTransition = &Error{Kind: KindConflict, Code: "invalid_transition", Message: "invalid status transition"}
+)
+
 type ArticleService struct {
-	repo ArticleRepository
-	log  *zap.Logger
+	repo    ArticleRepository
+	authors AuthorRepository
+	log     *zap.Logger
 }
 
-func NewArticleService(repo ArticleRepository, log *zap.Logger) *ArticleService {
+func NewArticleService(repo ArticleRepository, authors AuthorRepository, log *zap.Logger) *ArticleService {
 	return &ArticleService{
-		repo: repo,
-		log:  log.With(zap.String("layer", "service")),
+		repo:    repo,
+		authors: authors,
+		log:     log.With(zap.String("layer", "service")),
 	}
 }
 
-// Create creates a new Article and returns its ID and creation timestamp
-func (s *ArticleService) Create(ctx context.Context, req dto.CreateArticleRequest) (*dto.CreateArticleResponse, error) {
-	if req.Title == "" {
-		s.log.Warn("creation attempt with empty title")
-		return nil, errors.New("title cannot be empty")
-	}
+// logger returns the logger of s annotated with the trace of ctx.
+func (s *ArticleService) logger(ctx context.Context) *zap.Logger {
+	return log.WithTrace(ctx, s.log)
+}
 
-	// prepare entity
-	article := &entities.Article{
-		Title:     req.Title,
-		CreatedAt: time.Now().UTC(),
+// Create creates a new Article and returns its ID and creation timestamp.
+// The article is owned by the caller's author profile, see resolveAuthor,
+// and starts out as a draft.
+func (s *ArticleService) Create(ctx context.Context, req dto.CreateArticleRequest) (_ *dto.CreateArticleResponse, err error) {
+	ctx, span := tracer.Start(ctx, "ArticleService.Create")
+	defer endSpan(span, &err)
+
+	article, err := s.newArticle(ctx, req)
+	if err != nil {
+		return nil, err
 	}
 
-	s.log.Info("creating new article", zap.String("title", req.Title))
+	s.logger(ctx).Info("creating new article", zap.String("title", article.Title))
 
 	// repo cvall
 	if err := s.repo.Create(ctx, article); err != nil {
 		return nil, err
 	}
 
-	s.log.Info("article created successfully", zap.Uint("id", article.ID))
+	s.logger(ctx).Info("article created successfully", zap.Uint("id", article.ID))
 
 	// return response DTO
+	return toCreateResponse(article), nil
+}
+
+// newArticle validates req and turns it into the draft Create stores.
+func (s *ArticleService) newArticle(ctx context.Context, req dto.CreateArticleRequest) (*entities.Article, error) {
+	if err := validate(req); err != nil {
+		s.logger(ctx).Warn("creation attempt with invalid article", zap.Error(err))
+		return nil, err
+	}
+	title := strings.TrimSpace(req.Title)
+
+	tags, categories, err := labels(req.Tags, req.Categories)
+	if err != nil {
+		s.logger(ctx).Warn("creation attempt with invalid labels", zap.Error(err))
+		return nil, err
+	}
+
+	language := ""
+	if req.Language != "" {
+		var ok bool
+		if language, ok = normalizeLanguage(req.Language); !ok {
+			s.logger(ctx).Warn("creation attempt with unsupported language", zap.String("language", req.Language))
+			return nil, fmt.Errorf("%w: unsupported language %q", ErrInvalidArticle, req.Language)
+		}
+	}
+
+	authorID, err := s.resolveAuthor(ctx, req.AuthorID)
+	if err != nil {
+		return nil, err
+	}
+
+	// prepare entity
+	article := &entities.Article{
+		Title:      title,
+		Slug:       slug.Make(title),
+		AuthorID:   authorID,
+		Body:       req.Body,
+		Summary:    strings.TrimSpace(req.Summary),
+		Language:   language,
+		Status:     entities.StatusDraft,
+		Tags:       tags,
+		Categories: categories,
+		CreatedAt:  time.Now().UTC(),
+		Version:    1,
+	}
+
+	if err := render(article); err != nil {
+		s.logger(ctx).Error("failed to render article body", zap.Error(err))
+		return nil, err
+	}
+	return article, nil
+}
+
+func toCreateResponse(a *entities.Article) *dto.CreateArticleResponse {
 	return &dto.CreateArticleResponse{
-		ID:        article.ID,
-		CreatedAt: article.CreatedAt,
-	}, nil
+		ID:        a.ID,
+		Slug:      a.Slug,
+		CreatedAt: a.CreatedAt,
+		Version:   a.Version,
+	}
 }
 
-// GetByID retrieves an Article by its ID
-func (s *ArticleService) GetByID(ctx context.Context, id uint) (*dto.ArticleResponse, error) {
-	article, err := s.repo.GetByID(ctx, id)
+// GetByID retrieves an Article by its ID.
+// Soft-deleted articles are only returned when includeDeleted is set.
+// Unpublished and soft-deleted articles are reported as ErrArticleNotFound
+// unless the caller owns them or is an admin.
+func (s *ArticleService) GetByID(ctx context.Context, id uint, includeDeleted bool) (_ *dto.ArticleResponse, err error) {
+	ctx, span := tracer.Start(ctx, "ArticleService.GetByID")
+	defer endSpan(span, &err)
+
+	article, err := s.repo.GetByID(ctx, id, includeDeleted)
+	if err != nil {
+		s.logger(ctx).Warn("failed to retrieve article", zap.Uint("id", id), zap.Error(err))
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrArticleNotFound
+		}
+		return nil, err
+	}
+
+	visible, err := s.visible(ctx, article)
 	if err != nil {
-		s.log.Warn("failed to retrieve article", zap.Uint("id", id), zap.Error(err))
 		return nil, err
 	}
+	if !visible {
+		return nil, ErrArticleNotFound
+	}
 
 	// return response DTO
-	return &dto.ArticleResponse{
-		ID:        article.ID,
-		Title:     article.Title,
-		CreatedAt: article.CreatedAt,
+	resp := toArticleResponse(article)
+	return &resp, nil
+}
+
+// GetBySlug retrieves an Article by its current or a former slug, with the
+// same visibility rules as GetByID. For a former slug the Slug of the
+// response differs from name, callers should redirect to the current one.
+func (s *ArticleService) GetBySlug(ctx context.Context, name string) (_ *dto.ArticleResponse, err error) {
+	ctx, span := tracer.Start(ctx, "ArticleService.GetBySlug")
+	defer endSpan(span, &err)
+
+	article, err := s.repo.GetBySlug(ctx, name)
+	if err != nil {
+		s.logger(ctx).Warn("failed to retrieve article", zap.String("slug", name), zap.Error(err))
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrArticleNotFound
+		}
+		return nil, err
+	}
+
+	visible, err := s.visible(ctx, article)
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		return nil, ErrArticleNotFound
+	}
+
+	resp := toArticleResponse(article)
+	return &resp, nil
+}
+
+// Update replaces the mutable fields of an existing Article.
+// The change is rejected with ErrVersionConflict unless version matches the
+// stored version or is AnyVersion.
+func (s *ArticleService) Update(ctx context.Context, id uint, version uint, req dto.UpdateArticleRequest) (_ *dto.ArticleResponse, err error) {
+	ctx, span := tracer.Start(ctx, "ArticleService.Update")
+	defer endSpan(span, &err)
+
+	article, err := s.loadVersion(ctx, id, version, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.apply(ctx, article, req)
+}
+
+// Patch applies a JSON Merge Patch (RFC 7396) or JSON Patch (RFC 6902)
+// document to an existing Article. The patch is applied to the
+// dto.UpdateArticleRequest representation of the article.
+// Versioning follows the same rules as Update.
+func (s *ArticleService) Patch(ctx context.Context, id uint, version uint, contentType string, patch []byte) (_ *dto.ArticleResponse, err error) {
+	ctx, span := tracer.Start(ctx, "ArticleService.Patch")
+	defer endSpan(span, &err)
+
+	article, err := s.loadVersion(ctx, id, version, false)
+	if err != nil {
+		return nil, err
+	}
+
+	original, err := json.Marshal(dto.UpdateArticleRequest{
+		Title:      article.Title,
+		Body:       article.Body,
+		Summary:    article.Summary,
+		Tags:       tagNames(article.Tags),
+		Categories: categoryNames(article.Categories),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var patched []byte
+	switch contentType {
+	case dto.MergePatchContentType, "application/json":
+		if !json.Valid(patch) {
+			return nil, fmt.Errorf("%w: body is not valid JSON", ErrInvalidPatch)
+		}
+		patched, err = jsonpatch.MergePatch(original, patch)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+		}
+	case dto.JSONPatchContentType:
+		ops, decodeErr := jsonpatch.DecodePatch(patch)
+		if decodeErr != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, decodeErr)
+		}
+		patched, err = ops.Apply(original)
+		if err != nil {
+			// the patch is well-formed but cannot be applied to this article
+			return nil, fmt.Errorf("%w: %v", ErrInvalidArticle, err)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unsupported content type %q", ErrInvalidPatch, contentType)
+	}
+
+	var req dto.UpdateArticleRequest
+	dec := json.NewDecoder(bytes.NewReader(patched))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidArticle, err)
+	}
+
+	return s.apply(ctx, article, req)
+}
+
+// Delete soft-deletes an Article. It can be undone with Restore until purged.
+// Versioning follows the same rules as Update.
+func (s *ArticleService) Delete(ctx context.Context, id uint, version uint) (err error) {
+	ctx, span := tracer.Start(ctx, "ArticleService.Delete")
+	defer endSpan(span, &err)
+
+	article, err := s.loadVersion(ctx, id, version, false)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, id, article.Version); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			// the article existed a moment ago, so it must have changed in between
+			return ErrVersionConflict
+		}
+		return err
+	}
+
+	s.logger(ctx).Info("article deleted", zap.Uint("id", id))
+	return nil
+}
+
+// Restore undoes a soft delete and returns the restored Article.
+// Versioning follows the same rules as Update.
+func (s *ArticleService) Restore(ctx context.Context, id uint, version uint) (_ *dto.ArticleResponse, err error) {
+	ctx, span := tracer.Start(ctx, "ArticleService.Restore")
+	defer endSpan(span, &err)
+
+	article, err := s.loadVersion(ctx, id, version, true)
+	if err != nil {
+		return nil, err
+	}
+	if !article.DeletedAt.Valid {
+		return nil, ErrArticleNotFound
+	}
+
+	if err := s.repo.Restore(ctx, id, article.Version); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrVersionConflict
+		}
+		return nil, err
+	}
+
+	s.logger(ctx).Info("article restored", zap.Uint("id", id))
+
+	article.Version++
+	article.DeletedAt = gorm.DeletedAt{}
+
+	resp := toArticleResponse(article)
+	return &resp, nil
+}
+
+// Transition moves an Article to another publication status and returns it.
+// Allowed moves are defined by entities.ArticleStatus.CanTransitionTo;
+// publishing additionally requires the admin role, everything else is up to
+// the author. Versioning follows the same rules as Update.
+func (s *ArticleService) Transition(ctx context.Context, id uint, version uint, to entities.ArticleStatus) (_ *dto.ArticleResponse, err error) {
+	ctx, span := tracer.Start(ctx, "ArticleService.Transition")
+	defer endSpan(span, &err)
+
+	article, err := s.loadVersion(ctx, id, version, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if !article.Status.CanTransitionTo(to) {
+		s.logger(ctx).Warn("rejected status transition",
+			zap.Uint("id", id), zap.String("from", string(article.Status)), zap.String("to", string(to)))
+		return nil, fmt.Errorf("%w: cannot move a %s article to %s", ErrInvalidTransition, article.Status, to)
+	}
+	if p, ok := auth.FromContext(ctx); ok && to == entities.StatusPublished && !p.IsAdmin() {
+		return nil, fmt.Errorf("%w: only reviewers may publish articles", ErrForbidden)
+	}
+
+	from := article.Status
+	article.Status = to
+	// a schedule only applies while the article is under review
+	article.PublishAt = nil
+	if to == entities.StatusPublished {
+		now := time.Now().UTC()
+		article.PublishedAt = &now
+	}
+
+	if err := s.repo.UpdateStatus(ctx, article); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrVersionConflict
+		}
+		return nil, err
+	}
+
+	s.logger(ctx).Info("article status changed",
+		zap.Uint("id", id), zap.String("from", string(from)), zap.String("to", string(to)))
+
+	resp := toArticleResponse(article)
+	return &resp, nil
+}
+
+// Schedule queues an Article under review to be published automatically at
+// publishAt, replacing an earlier schedule. Like publishing, scheduling
+// requires the admin role. Versioning follows the same rules as Update.
+func (s *ArticleService) Schedule(ctx context.Context, id uint, version uint, publishAt time.Time) (_ *dto.ArticleResponse, err error) {
+	ctx, span := tracer.Start(ctx, "ArticleService.Schedule")
+	defer endSpan(span, &err)
+
+	if !publishAt.After(time.Now()) {
+		return nil, fmt.Errorf("%w: publish_at must be in the future", ErrInvalidArticle)
+	}
+
+	article, err := s.loadVersion(ctx, id, version, false)
+	if err != nil {
+		return nil, err
+	}
+	if article.Status != entities.StatusInReview {
+		return nil, fmt.Errorf("%w: only articles in review can be scheduled, this one is %s", ErrInvalidTransition, article.Status)
+	}
+	if p, ok := auth.FromContext(ctx); ok && !p.IsAdmin() {
+		return nil, fmt.Errorf("%w: only reviewers may schedule articles", ErrForbidden)
+	}
+
+	at := publishAt.UTC()
+	article.PublishAt = &at
+
+	if err := s.repo.UpdateStatus(ctx, article); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrVersionConflict
+		}
+		return nil, err
+	}
+
+	s.logger(ctx).Info("article scheduled", zap.Uint("id", id), zap.Time("publish_at", at))
+
+	resp := toArticleResponse(article)
+	return &resp, nil
+}
+
+// Unschedule cancels the scheduled publication of an Article.
+// The article stays in review. Like Schedule, it requires the admin role.
+// Versioning follows the same rules as Update.
+func (s *ArticleService) Unschedule(ctx context.Context, id uint, version uint) (_ *dto.ArticleResponse, err error) {
+	ctx, span := tracer.Start(ctx, "ArticleService.Unschedule")
+	defer endSpan(span, &err)
+
+	article, err := s.loadVersion(ctx, id, version, false)
+	if err != nil {
+		return nil, err
+	}
+	if article.PublishAt == nil {
+		return nil, fmt.Errorf("%w: article is not scheduled", ErrInvalidTransition)
+	}
+	if p, ok := auth.FromContext(ctx); ok && !p.IsAdmin() {
+		return nil, fmt.Errorf("%w: only reviewers may unschedule articles", ErrForbidden)
+	}
+
+	article.PublishAt = nil
+
+	if err := s.repo.UpdateStatus(ctx, article); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrVersionConflict
+		}
+		return nil, err
+	}
+
+	s.logger(ctx).Info("article unscheduled", zap.Uint("id", id))
+
+	resp := toArticleResponse(article)
+	return &resp, nil
+}
+
+// Purge permanently removes articles that have been soft-deleted for longer than retention.
+func (s *ArticleService) Purge(ctx context.Context, retention time.Duration) (_ *dto.PurgeArticlesResponse, err error) {
+	ctx, span := tracer.Start(ctx, "ArticleService.Purge")
+	defer endSpan(span, &err)
+
+	before := time.Now().UTC().Add(-retention)
+
+	purged, err := s.repo.Purge(ctx, before)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger(ctx).Info("purged deleted articles", zap.Int64("count", purged), zap.Time("deleted_before", before))
+
+	return &dto.PurgeArticlesResponse{
+		Purged:        purged,
+		DeletedBefore: before,
 	}, nil
 }
+
+// loadVersion fetches an article the caller is allowed to modify, translating
+// a missing record into ErrArticleNotFound, a foreign article into
+// ErrForbidden and a version mismatch into ErrVersionConflict.
+func (s *ArticleService) loadVersion(ctx context.Context, id uint, version uint, includeDeleted bool) (*entities.Article, error) {
+	article, err := s.repo.GetByID(ctx, id, includeDeleted)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrArticleNotFound
+		}
+		s.logger(ctx).Warn("failed to retrieve article", zap.Uint("id", id), zap.Error(err))
+		return nil, err
+	}
+
+	if err := s.authorize(ctx, article); err != nil {
+		return nil, err
+	}
+
+	if version != AnyVersion && article.Version != version {
+		s.logger(ctx).Info("article version mismatch",
+			zap.Uint("id", id), zap.Uint("expected", version), zap.Uint("actual", article.Version))
+		return nil, ErrVersionConflict
+	}
+
+	return article, nil
+}
+
+// authorize checks that the caller owns article or is an admin.
+// Articles without an author can only be modified by admins.
+func (s *ArticleService) authorize(ctx context.Context, article *entities.Article) error {
+	p, ok := auth.FromContext(ctx)
+	if !ok || p.IsAdmin() {
+		return nil
+	}
+
+	owned, err := s.owns(ctx, article)
+	if err != nil {
+		return err
+	}
+	if owned {
+		return nil
+	}
+
+	s.logger(ctx).Warn("attempt to modify a foreign article",
+		zap.Uint("id", article.ID), zap.String("subject", p.Subject))
+	return ErrForbidden
+}
+
+// visible reports whether the caller may read article. Published articles
+// are public, all other states and soft-deleted articles are limited to the
+// author and admins.
+func (s *ArticleService) visible(ctx context.Context, article *entities.Article) (bool, error) {
+	if article.Status == entities.StatusPublished && !article.DeletedAt.Valid {
+		return true, nil
+	}
+	p, ok := auth.FromContext(ctx)
+	if !ok || p.IsAdmin() {
+		return true, nil
+	}
+	return s.owns(ctx, article)
+}
+
+// owns reports whether article belongs to the author profile of the caller.
+func (s *ArticleService) owns(ctx context.Context, article *entities.Article) (bool, error) {
+	if article.AuthorID == nil {
+		return false, nil
+	}
+	author, err := s.authors.GetByID(ctx, *article.AuthorID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return isOwner(ctx, author), nil
+}
+
+// listedAuthor restricts listings of unpublished or deleted articles to the
+// caller's own articles unless it is an admin. requested is the author the
+// caller asked for, zero meaning any.
+func (s *ArticleService) listedAuthor(ctx context.Context, requested uint) (uint, error) {
+	p, ok := auth.FromContext(ctx)
+	if !ok || p.IsAdmin() {
+		return requested, nil
+	}
+	if p.IsAnonymous() {
+		return 0, fmt.Errorf("%w: sign in to list unpublished or deleted articles", ErrForbidden)
+	}
+
+	author, err := s.authors.GetBySubject(ctx, p.Subject)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return 0, fmt.Errorf("%w: no author profile", ErrForbidden)
+		}
+		return 0, err
+	}
+	if requested != 0 && requested != author.ID {
+		return 0, fmt.Errorf("%w: cannot list unpublished or deleted articles of another author", ErrForbidden)
+	}
+	return author.ID, nil
+}
+
+// resolveAuthor determines the owner of a new article. Authenticated callers
+// own what they create and need an author profile to do so; admins and
+// unauthenticated deployments may pick any existing author or none.
+func (s *ArticleService) resolveAuthor(ctx context.Context, requested *uint) (*uint, error) {
+	p, ok := auth.FromContext(ctx)
+
+	if requested != nil && (!ok || p.IsAdmin()) {
+		if _, err := s.authors.GetByID(ctx, *requested); err != nil {
+			if errors.Is(err, ErrNotFound) {
+				return nil, fmt.Errorf("%w: author %d does not exist", ErrInvalidArticle, *requested)
+			}
+			return nil, err
+		}
+		return requested, nil
+	}
+	if !ok {
+		return nil, nil
+	}
+
+	author, err := s.authors.GetBySubject(ctx, p.Subject)
+	if err != nil {
+		if !errors.Is(err, ErrNotFound) {
+			return nil, err
+		}
+		if p.IsAdmin() {
+			return nil, nil
+		}
+		s.logger(ctx).Warn("article creation without author profile", zap.String("subject", p.Subject))
+		return nil, fmt.Errorf("%w: create an author profile first", ErrForbidden)
+	}
+
+	if requested != nil && *requested != author.ID {
+		return nil, fmt.Errorf("%w: cannot create articles for another author", ErrForbidden)
+	}
+	return &author.ID, nil
+}
+
+// apply validates the requested state and persists it onto article.
+func (s *ArticleService) apply(ctx context.Context, article *entities.Article, req dto.UpdateArticleRequest) (*dto.ArticleResponse, error) {
+	if err := validate(req); err != nil {
+		s.logger(ctx).Warn("update attempt with invalid article", zap.Uint("id", article.ID), zap.Error(err))
+		return nil, err
+	}
+	title := strings.TrimSpace(req.Title)
+
+	tags, categories, err := labels(req.Tags, req.Categories)
+	if err != nil {
+		s.logger(ctx).Warn("update attempt with invalid labels", zap.Uint("id", article.ID), zap.Error(err))
+		return nil, err
+	}
+
+	// the slug follows the title, the repository keeps the former one
+	if next := slug.Make(title); next != slug.Make(article.Title) {
+		article.Slug = next
+	}
+	article.Title = title
+	article.Body = req.Body
+	article.Summary = strings.TrimSpace(req.Summary)
+	article.Tags = tags
+	article.Categories = categories
+
+	if err := render(article); err != nil {
+		s.logger(ctx).Error("failed to render article body", zap.Uint("id", article.ID), zap.Error(err))
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, article); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrVersionConflict
+		}
+		return nil, err
+	}
+
+	s.logger(ctx).Info("article updated successfully", zap.Uint("id", article.ID))
+
+	resp := toArticleResponse(article)
+	return &resp, nil
+}
+
+// List returns a page of articles matching the request filters.
+// Pagination is offset based unless a cursor from a previous page is supplied.
+// Only published articles are listed unless another status or soft-deleted
+// articles are requested; those listings are limited to the caller's own
+// articles for non-admins.
+func (s *ArticleService) List(ctx context.Context, req dto.ListArticlesRequest) (_ *dto.ListArticlesResponse, err error) {
+	ctx, span := tracer.Start(ctx, "ArticleService.List")
+	defer endSpan(span, &err)
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+	if limit > maxListLimit {
+		limit = maxListLimit
+	}
+
+	sort := entities.ArticleSort(req.Sort)
+	if sort == "" {
+		sort = entities.SortCreatedAtDesc
+	}
+
+	status := entities.ArticleStatus(req.Status)
+	if status == "" {
+		status = entities.StatusPublished
+	}
+	authorID := req.AuthorID
+	if status != entities.StatusPublished || req.IncludeDeleted {
+		var err error
+		if authorID, err = s.listedAuthor(ctx, req.AuthorID); err != nil {
+			return nil, err
+		}
+	}
+
+	filter := entities.ArticleFilter{
+		Title:       strings.TrimSpace(req.Title),
+		AuthorID:    authorID,
+		Status:      status,
+		Tags:        tagFilter(req.Tags),
+		TagMatch:    entities.TagMatch(req.TagMatch),
+		Category:    normalizeLabel(req.Category),
+		CreatedFrom: req.CreatedFrom,
+		CreatedTo:   req.CreatedTo,
+		Sort:        sort,
+		// fetch one extra row to find out whether another page exists
+		Limit:  limit + 1,
+		Offset: req.Offset,
+
+		IncludeDeleted: req.IncludeDeleted,
+	}
+
+	if req.Cursor != "" {
+		after, err := decodeCursor(req.Cursor, sort)
+		if err != nil {
+			s.logger(ctx).Warn("invalid list cursor", zap.String("cursor", req.Cursor), zap.Error(err))
+			return nil, ErrInvalidCursor
+		}
+		filter.After = after
+		filter.Offset = 0
+	}
+
+	articles, total, err := s.repo.List(ctx, filter)
+	if err != nil {
+		s.logger(ctx).Warn("failed to list articles", zap.Error(err))
+		return nil, err
+	}
+
+	resp := &dto.ListArticlesResponse{
+		Items: make([]dto.ArticleResponse, 0, len(articles)),
+		Meta: dto.ListMeta{
+			Total:  total,
+			Limit:  limit,
+			Offset: filter.Offset,
+		},
+	}
+
+	if len(articles) > limit {
+		articles = articles[:limit]
+		last := articles[len(articles)-1]
+		resp.Meta.NextCursor = encodeCursor(last, sort)
+	}
+
+	for i := range articles {
+		resp.Items = append(resp.Items, toArticleResponse(&articles[i]))
+	}
+
+	return resp, nil
+}
+
+// ListByAuthor lists the articles owned by an author.
+// It fails with ErrAuthorNotFound if the author does not exist.
+func (s *ArticleService) ListByAuthor(ctx context.Context, authorID uint, req dto.ListArticlesRequest) (_ *dto.ListArticlesResponse, err error) {
+	ctx, span := tracer.Start(ctx, "ArticleService.ListByAuthor")
+	defer endSpan(span, &err)
+
+	if _, err := s.authors.GetByID(ctx, authorID); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrAuthorNotFound
+		}
+		return nil, err
+	}
+
+	req.AuthorID = authorID
+	return s.List(ctx, req)
+}
+
+// ListTags returns all tags with the number of articles using them.
+func (s *ArticleService) ListTags(ctx context.Context) (_ *dto.ListTagsResponse, err error) {
+	ctx, span := tracer.Start(ctx, "ArticleService.ListTags")
+	defer endSpan(span, &err)
+
+	counts, err := s.repo.TagCounts(ctx)
+	if err != nil {
+		s.logger(ctx).Warn("failed to count tags", zap.Error(err))
+		return nil, err
+	}
+
+	resp := &dto.ListTagsResponse{Items: make([]dto.TagCountResponse, 0, len(counts))}
+	for _, c := range counts {
+		resp.Items = append(resp.Items, dto.TagCountResponse{Name: c.Name, Count: c.Count})
+	}
+	return resp, nil
+}
+
+// render refreshes the cached HTML and the excerpt derived from the Markdown body.
+func render(a *entities.Article) error {
+	html, err := markdown.Render(a.Body)
+	if err != nil {
+		return err
+	}
+	a.BodyHTML = html
+	a.Excerpt = markdown.Excerpt(html, excerptLength)
+	return nil
+}
+
+func toArticleResponse(a *entities.Article) dto.ArticleResponse {
+	return dto.ArticleResponse{
+		ID:          a.ID,
+		Title:       a.Title,
+		Slug:        a.Slug,
+		AuthorID:    a.AuthorID,
+		Summary:     a.Summary,
+		Excerpt:     a.Excerpt,
+		Body:        a.Body,
+		BodyHTML:    a.BodyHTML,
+		Tags:        tagNames(a.Tags),
+		Categories:  categoryNames(a.Categories),
+		Language:    a.Language,
+		Status:      string(a.Status),
+		PublishedAt: a.PublishedAt,
+		PublishAt:   a.PublishAt,
+		CreatedAt:   a.CreatedAt,
+		UpdatedAt:   a.UpdatedAt,
+		DeletedAt:   deletedAt(a),
+		Version:     a.Version,
+	}
+}
+
+// validate checks req against the rules declared on its fields. Handlers
+// bind requests with the same rules, but patched documents and other callers
+// of the service are only checked here.
+func validate(req any) error {
+	if err := validation.Struct(req); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArticle, err)
+	}
+	return nil
+}
+
+// normalizeLabel lower-cases a tag or category name and collapses whitespace.
+func normalizeLabel(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// normalizeLabels normalizes and de-duplicates names, keeping their order.
+func normalizeLabels(kind string, names []string) ([]string, error) {
+	seen := make(map[string]bool, len(names))
+	out := make([]string, 0, len(names))
+	for _, n := range names {
+		n = normalizeLabel(n)
+		if n == "" {
+			return nil, fmt.Errorf("%w: %s cannot be empty", ErrInvalidArticle, kind)
+		}
+		if len([]rune(n)) > maxLabelLength {
+			return nil, fmt.Errorf("%w: %s %q is longer than %d characters", ErrInvalidArticle, kind, n, maxLabelLength)
+		}
+		if !seen[n] {
+			seen[n] = true
+			out = append(out, n)
+		}
+	}
+	if len(out) > maxLabels {
+		return nil, fmt.Errorf("%w: at most %d %ss are allowed", ErrInvalidArticle, maxLabels, kind)
+	}
+	return out, nil
+}
+
+// labels validates the requested tag and category names and turns them into entities.
+func labels(tagNames, categoryNames []string) ([]entities.Tag, []entities.Category, error) {
+	tn, err := normalizeLabels("tag", tagNames)
+	if err != nil {
+		return nil, nil, err
+	}
+	cn, err := normalizeLabels("category", categoryNames)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tags := make([]entities.Tag, 0, len(tn))
+	for _, n := range tn {
+		tags = append(tags, entities.Tag{Name: n})
+	}
+	categories := make([]entities.Category, 0, len(cn))
+	for _, n := range cn {
+		categories = append(categories, entities.Category{Name: n})
+	}
+	return tags, categories, nil
+}
+
+// tagFilter splits comma separated query values into normalized tag names.
+func tagFilter(values []string) []string {
+	var tags []string
+	seen := map[string]bool{}
+	for _, v := range values {
+		for _, n := range strings.Split(v, ",") {
+			n = normalizeLabel(n)
+			if n != "" && !seen[n] {
+				seen[n] = true
+				tags = append(tags, n)
+			}
+		}
+	}
+	return tags
+}
+
+func tagNames(tags []entities.Tag) []string {
+	names := make([]string, 0, len(tags))
+	for _, t := range tags {
+		names = append(names, t.Name)
+	}
+	return names
+}
+
+func categoryNames(categories []entities.Category) []string {
+	names := make([]string, 0, len(categories))
+	for _, c := range categories {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+func deletedAt(a *entities.Article) *time.Time {
+	if !a.DeletedAt.Valid {
+		return nil
+	}
+	t := a.DeletedAt.Time
+	return &t
+}
+
+// cursorPayload is the JSON document hidden inside an opaque cursor.
+type cursorPayload struct {
+	ID        uint      `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	Sort      string    `json:"sort"`
+}
+
+func encodeCursor(a entities.Article, sort entities.ArticleSort) string {
+	b, _ := json.Marshal(cursorPayload{ID: a.ID, CreatedAt: a.CreatedAt, Sort: string(sort)})
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(cursor string, sort entities.ArticleSort) (*entities.ArticleCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	var p cursorPayload
+	if err := json.Unmarshal(b, &p); err != nil {
+		return nil, err
+	}
+	if p.Sort != string(sort) {
+		return nil, errors.New("cursor was issued for a different sort order")
+	}
+
+	return &entities.ArticleCursor{ID: p.ID, CreatedAt: p.CreatedAt}, nil
+}
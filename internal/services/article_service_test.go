@@ -3,11 +3,14 @@ package services
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/antonchaban/articles-go/internal/auth"
 	"github.com/antonchaban/articles-go/internal/dto"
 	"github.com/antonchaban/articles-go/internal/entities"
+	"github.com/antonchaban/articles-go/pkg/validation"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"go.uber.org/zap"
@@ -23,18 +26,105 @@ func (m *MockArticleRepository) Create(ctx context.Context, article *entities.Ar
 	return args.Error(0)
 }
 
-func (m *MockArticleRepository) GetByID(ctx context.Context, id uint) (*entities.Article, error) {
-	args := m.Called(ctx, id)
+func (m *MockArticleRepository) CreateInBatches(ctx context.Context, articles []*entities.Article, batchSize int) error {
+	args := m.Called(ctx, articles, batchSize)
+	return args.Error(0)
+}
+
+// Transaction runs fn right away. Unless fn fails it returns the error set up for the call.
+func (m *MockArticleRepository) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	args := m.Called(ctx)
+	if err := fn(ctx); err != nil {
+		return err
+	}
+	return args.Error(0)
+}
+
+func (m *MockArticleRepository) GetByID(ctx context.Context, id uint, includeDeleted bool) (*entities.Article, error) {
+	args := m.Called(ctx, id, includeDeleted)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*entities.Article), args.Error(1)
 }
 
+func (m *MockArticleRepository) GetBySlug(ctx context.Context, slug string) (*entities.Article, error) {
+	args := m.Called(ctx, slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Article), args.Error(1)
+}
+
+func (m *MockArticleRepository) List(ctx context.Context, filter entities.ArticleFilter) ([]entities.Article, int64, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]entities.Article), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockArticleRepository) Update(ctx context.Context, article *entities.Article) error {
+	args := m.Called(ctx, article)
+	return args.Error(0)
+}
+
+func (m *MockArticleRepository) Delete(ctx context.Context, id uint, version uint) error {
+	args := m.Called(ctx, id, version)
+	return args.Error(0)
+}
+
+func (m *MockArticleRepository) Restore(ctx context.Context, id uint, version uint) error {
+	args := m.Called(ctx, id, version)
+	return args.Error(0)
+}
+
+func (m *MockArticleRepository) UpdateStatus(ctx context.Context, article *entities.Article) error {
+	args := m.Called(ctx, article)
+	return args.Error(0)
+}
+
+func (m *MockArticleRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	args := m.Called(ctx, deletedBefore)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockArticleRepository) TagCounts(ctx context.Context) ([]entities.TagCount, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entities.TagCount), args.Error(1)
+}
+
+func (m *MockArticleRepository) Search(ctx context.Context, search entities.ArticleSearch) ([]entities.ArticleSearchHit, int64, error) {
+	args := m.Called(ctx, search)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]entities.ArticleSearchHit), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockArticleRepository) ListRevisions(ctx context.Context, articleID uint, limit, offset int) ([]entities.ArticleRevision, int64, error) {
+	args := m.Called(ctx, articleID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]entities.ArticleRevision), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockArticleRepository) GetRevision(ctx context.Context, articleID uint, version uint) (*entities.ArticleRevision, error) {
+	args := m.Called(ctx, articleID, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.ArticleRevision), args.Error(1)
+}
+
 func TestCreateArticleWithValidTitle(t *testing.T) {
 	mockRepo := new(MockArticleRepository)
 	logger := zap.NewNop()
-	service := NewArticleService(mockRepo, logger)
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), logger)
 
 	req := dto.CreateArticleRequest{
 		Title: "Valid Article Title",
@@ -59,7 +149,7 @@ func TestCreateArticleWithValidTitle(t *testing.T) {
 func TestCreateArticleWithEmptyTitle(t *testing.T) {
 	mockRepo := new(MockArticleRepository)
 	logger := zap.NewNop()
-	service := NewArticleService(mockRepo, logger)
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), logger)
 
 	req := dto.CreateArticleRequest{
 		Title: "",
@@ -69,14 +159,15 @@ func TestCreateArticleWithEmptyTitle(t *testing.T) {
 
 	assert.Error(t, err)
 	assert.Nil(t, resp)
-	assert.Equal(t, "title cannot be empty", err.Error())
+	assert.ErrorIs(t, err, ErrInvalidArticle)
+	assert.EqualError(t, err, "invalid article: title: is required")
 	mockRepo.AssertNotCalled(t, "Create")
 }
 
 func TestCreateArticleWithRepositoryError(t *testing.T) {
 	mockRepo := new(MockArticleRepository)
 	logger := zap.NewNop()
-	service := NewArticleService(mockRepo, logger)
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), logger)
 
 	req := dto.CreateArticleRequest{
 		Title: "Valid Article Title",
@@ -96,7 +187,7 @@ func TestCreateArticleWithRepositoryError(t *testing.T) {
 func TestGetByIDReturnsArticle(t *testing.T) {
 	mockRepo := new(MockArticleRepository)
 	logger := zap.NewNop()
-	service := NewArticleService(mockRepo, logger)
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), logger)
 
 	expectedArticle := &entities.Article{
 		ID:        1,
@@ -104,9 +195,9 @@ func TestGetByIDReturnsArticle(t *testing.T) {
 		CreatedAt: time.Now().UTC(),
 	}
 
-	mockRepo.On("GetByID", mock.Anything, uint(1)).Return(expectedArticle, nil)
+	mockRepo.On("GetByID", mock.Anything, uint(1), false).Return(expectedArticle, nil)
 
-	resp, err := service.GetByID(context.Background(), 1)
+	resp, err := service.GetByID(context.Background(), 1, false)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, resp)
@@ -119,30 +210,850 @@ func TestGetByIDReturnsArticle(t *testing.T) {
 func TestGetByIDWithNonExistentArticle(t *testing.T) {
 	mockRepo := new(MockArticleRepository)
 	logger := zap.NewNop()
-	service := NewArticleService(mockRepo, logger)
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), logger)
 
-	mockRepo.On("GetByID", mock.Anything, uint(999)).Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("GetByID", mock.Anything, uint(999), false).Return(nil, ErrNotFound)
 
-	resp, err := service.GetByID(context.Background(), 999)
+	resp, err := service.GetByID(context.Background(), 999, false)
 
 	assert.Error(t, err)
 	assert.Nil(t, resp)
-	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.ErrorIs(t, err, ErrArticleNotFound)
 	mockRepo.AssertExpectations(t)
 }
 
 func TestGetByIDWithRepositoryError(t *testing.T) {
 	mockRepo := new(MockArticleRepository)
 	logger := zap.NewNop()
-	service := NewArticleService(mockRepo, logger)
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), logger)
 
 	expectedError := errors.New("database connection error")
-	mockRepo.On("GetByID", mock.Anything, uint(1)).Return(nil, expectedError)
+	mockRepo.On("GetByID", mock.Anything, uint(1), false).Return(nil, expectedError)
 
-	resp, err := service.GetByID(context.Background(), 1)
+	resp, err := service.GetByID(context.Background(), 1, false)
 
 	assert.Error(t, err)
 	assert.Nil(t, resp)
 	assert.Equal(t, expectedError, err)
 	mockRepo.AssertExpectations(t)
 }
+
+func TestListReturnsPageWithNextCursor(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	logger := zap.NewNop()
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), logger)
+
+	now := time.Now().UTC()
+	articles := []entities.Article{
+		{ID: 3, Title: "Third", CreatedAt: now},
+		{ID: 2, Title: "Second", CreatedAt: now.Add(-time.Minute)},
+		{ID: 1, Title: "First", CreatedAt: now.Add(-2 * time.Minute)},
+	}
+
+	mockRepo.On("List", mock.Anything, mock.MatchedBy(func(f entities.ArticleFilter) bool {
+		return f.Limit == 3 && f.Sort == entities.SortCreatedAtDesc && f.Title == "art"
+	})).Return(articles, int64(5), nil)
+
+	resp, err := service.List(context.Background(), dto.ListArticlesRequest{Limit: 2, Title: " art "})
+
+	assert.NoError(t, err)
+	assert.Len(t, resp.Items, 2)
+	assert.Equal(t, int64(5), resp.Meta.Total)
+	assert.Equal(t, 2, resp.Meta.Limit)
+	assert.NotEmpty(t, resp.Meta.NextCursor)
+	mockRepo.AssertExpectations(t)
+
+	// the cursor must resume after the last returned item
+	mockRepo.On("List", mock.Anything, mock.MatchedBy(func(f entities.ArticleFilter) bool {
+		return f.After != nil && f.After.ID == 2 && f.After.CreatedAt.Equal(articles[1].CreatedAt)
+	})).Return(articles[2:], int64(5), nil)
+
+	resp, err = service.List(context.Background(), dto.ListArticlesRequest{Limit: 2, Cursor: resp.Meta.NextCursor})
+
+	assert.NoError(t, err)
+	assert.Len(t, resp.Items, 1)
+	assert.Empty(t, resp.Meta.NextCursor)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestListWithCursorForDifferentSort(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	logger := zap.NewNop()
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), logger)
+
+	cursor := encodeCursor(entities.Article{ID: 1}, entities.SortIDAsc)
+
+	resp, err := service.List(context.Background(), dto.ListArticlesRequest{Cursor: cursor, Sort: "-created_at"})
+
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+	assert.Nil(t, resp)
+	mockRepo.AssertNotCalled(t, "List")
+}
+
+func TestListWithMalformedCursor(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	logger := zap.NewNop()
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), logger)
+
+	resp, err := service.List(context.Background(), dto.ListArticlesRequest{Cursor: "!!not-base64!!"})
+
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+	assert.Nil(t, resp)
+	mockRepo.AssertNotCalled(t, "List")
+}
+
+func TestListWithRepositoryError(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	logger := zap.NewNop()
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), logger)
+
+	expectedError := errors.New("database error")
+	mockRepo.On("List", mock.Anything, mock.Anything).Return(nil, int64(0), expectedError)
+
+	resp, err := service.List(context.Background(), dto.ListArticlesRequest{})
+
+	assert.Equal(t, expectedError, err)
+	assert.Nil(t, resp)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdateArticleReplacesTitle(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	logger := zap.NewNop()
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), logger)
+
+	existing := &entities.Article{ID: 1, Title: "Tpyo", CreatedAt: time.Now().UTC()}
+	mockRepo.On("GetByID", mock.Anything, uint(1), false).Return(existing, nil)
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(a *entities.Article) bool {
+		return a.ID == 1 && a.Title == "Typo"
+	})).Return(nil)
+
+	resp, err := service.Update(context.Background(), 1, AnyVersion, dto.UpdateArticleRequest{Title: " Typo "})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Typo", resp.Title)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdateNonExistentArticle(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	logger := zap.NewNop()
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), logger)
+
+	mockRepo.On("GetByID", mock.Anything, uint(999), false).Return(nil, ErrNotFound)
+
+	resp, err := service.Update(context.Background(), 999, AnyVersion, dto.UpdateArticleRequest{Title: "Title"})
+
+	assert.ErrorIs(t, err, ErrArticleNotFound)
+	assert.Nil(t, resp)
+	mockRepo.AssertNotCalled(t, "Update")
+}
+
+func TestUpdateArticleWithBlankTitle(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	logger := zap.NewNop()
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), logger)
+
+	mockRepo.On("GetByID", mock.Anything, uint(1), false).Return(&entities.Article{ID: 1, Title: "Title"}, nil)
+
+	resp, err := service.Update(context.Background(), 1, AnyVersion, dto.UpdateArticleRequest{Title: "   "})
+
+	assert.ErrorIs(t, err, ErrInvalidArticle)
+	assert.Nil(t, resp)
+	mockRepo.AssertNotCalled(t, "Update")
+}
+
+func TestPatchArticleWithMergePatch(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	logger := zap.NewNop()
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), logger)
+
+	mockRepo.On("GetByID", mock.Anything, uint(1), false).Return(&entities.Article{ID: 1, Title: "Old"}, nil)
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(a *entities.Article) bool {
+		return a.Title == "New"
+	})).Return(nil)
+
+	resp, err := service.Patch(context.Background(), 1, AnyVersion, dto.MergePatchContentType, []byte(`{"title":"New"}`))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "New", resp.Title)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPatchArticleWithJSONPatch(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	logger := zap.NewNop()
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), logger)
+
+	mockRepo.On("GetByID", mock.Anything, uint(1), false).Return(&entities.Article{ID: 1, Title: "Old"}, nil)
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(a *entities.Article) bool {
+		return a.Title == "New"
+	})).Return(nil)
+
+	patch := []byte(`[{"op":"test","path":"/title","value":"Old"},{"op":"replace","path":"/title","value":"New"}]`)
+	resp, err := service.Patch(context.Background(), 1, AnyVersion, dto.JSONPatchContentType, patch)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "New", resp.Title)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPatchArticleWithFailingJSONPatchTest(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	logger := zap.NewNop()
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), logger)
+
+	mockRepo.On("GetByID", mock.Anything, uint(1), false).Return(&entities.Article{ID: 1, Title: "Old"}, nil)
+
+	patch := []byte(`[{"op":"test","path":"/title","value":"Other"}]`)
+	resp, err := service.Patch(context.Background(), 1, AnyVersion, dto.JSONPatchContentType, patch)
+
+	assert.ErrorIs(t, err, ErrInvalidArticle)
+	assert.Nil(t, resp)
+	mockRepo.AssertNotCalled(t, "Update")
+}
+
+func TestPatchArticleWithMalformedDocument(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	logger := zap.NewNop()
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), logger)
+
+	mockRepo.On("GetByID", mock.Anything, uint(1), false).Return(&entities.Article{ID: 1, Title: "Old"}, nil)
+
+	for contentType, patch := range map[string]string{
+		dto.MergePatchContentType: `{"title":`,
+		dto.JSONPatchContentType:  `{"op":"replace"}`,
+		"text/plain":              `title=New`,
+	} {
+		resp, err := service.Patch(context.Background(), 1, AnyVersion, contentType, []byte(patch))
+
+		assert.ErrorIs(t, err, ErrInvalidPatch, contentType)
+		assert.Nil(t, resp)
+	}
+	mockRepo.AssertNotCalled(t, "Update")
+}
+
+func TestPatchArticleWithUnknownField(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	logger := zap.NewNop()
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), logger)
+
+	mockRepo.On("GetByID", mock.Anything, uint(1), false).Return(&entities.Article{ID: 1, Title: "Old"}, nil)
+
+	resp, err := service.Patch(context.Background(), 1, AnyVersion, dto.MergePatchContentType, []byte(`{"id":5}`))
+
+	assert.ErrorIs(t, err, ErrInvalidArticle)
+	assert.Nil(t, resp)
+	mockRepo.AssertNotCalled(t, "Update")
+}
+
+func TestPatchArticleValidatesPatchedDocument(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), zap.NewNop())
+
+	mockRepo.On("GetByID", mock.Anything, uint(1), false).Return(&entities.Article{ID: 1, Title: "Old"}, nil)
+
+	patch := []byte(`{"title":"line\nbreak","tags":["ok","no/slash"]}`)
+	resp, err := service.Patch(context.Background(), 1, AnyVersion, dto.MergePatchContentType, patch)
+
+	assert.Nil(t, resp)
+	assert.ErrorIs(t, err, ErrInvalidArticle)
+	fields, ok := validation.Fields(err)
+	assert.True(t, ok)
+	assert.Equal(t, validation.Errors{
+		{Field: "title", Rule: "singleline", Message: "must be a single line without control characters"},
+		{Field: "tags[1]", Rule: "label", Message: "may only contain letters, digits, spaces and - _ . + #"},
+	}, fields)
+	mockRepo.AssertNotCalled(t, "Update")
+}
+
+func TestGetByIDIncludingDeletedArticle(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	logger := zap.NewNop()
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), logger)
+
+	deletedAt := time.Now().UTC()
+	article := &entities.Article{
+		ID:        1,
+		Title:     "Deleted",
+		DeletedAt: gorm.DeletedAt{Time: deletedAt, Valid: true},
+	}
+	mockRepo.On("GetByID", mock.Anything, uint(1), true).Return(article, nil)
+
+	resp, err := service.GetByID(context.Background(), 1, true)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, resp.DeletedAt)
+	assert.Equal(t, deletedAt, *resp.DeletedAt)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestDeleteArticle(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	logger := zap.NewNop()
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), logger)
+
+	mockRepo.On("GetByID", mock.Anything, uint(1), false).Return(&entities.Article{ID: 1, Version: 2}, nil)
+	mockRepo.On("Delete", mock.Anything, uint(1), uint(2)).Return(nil)
+
+	err := service.Delete(context.Background(), 1, 2)
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestDeleteNonExistentArticle(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	logger := zap.NewNop()
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), logger)
+
+	mockRepo.On("GetByID", mock.Anything, uint(999), false).Return(nil, ErrNotFound)
+
+	err := service.Delete(context.Background(), 999, AnyVersion)
+
+	assert.ErrorIs(t, err, ErrArticleNotFound)
+	mockRepo.AssertNotCalled(t, "Delete")
+}
+
+func TestDeleteArticleWithStaleVersion(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	logger := zap.NewNop()
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), logger)
+
+	mockRepo.On("GetByID", mock.Anything, uint(1), false).Return(&entities.Article{ID: 1, Version: 3}, nil)
+
+	err := service.Delete(context.Background(), 1, 2)
+
+	assert.ErrorIs(t, err, ErrVersionConflict)
+	mockRepo.AssertNotCalled(t, "Delete")
+}
+
+func TestRestoreArticle(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	logger := zap.NewNop()
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), logger)
+
+	deleted := &entities.Article{
+		ID:        1,
+		Title:     "Back",
+		Version:   2,
+		DeletedAt: gorm.DeletedAt{Time: time.Now().UTC(), Valid: true},
+	}
+	mockRepo.On("GetByID", mock.Anything, uint(1), true).Return(deleted, nil)
+	mockRepo.On("Restore", mock.Anything, uint(1), uint(2)).Return(nil)
+
+	resp, err := service.Restore(context.Background(), 1, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Back", resp.Title)
+	assert.Equal(t, uint(3), resp.Version)
+	assert.Nil(t, resp.DeletedAt)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRestoreArticleThatIsNotDeleted(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	logger := zap.NewNop()
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), logger)
+
+	mockRepo.On("GetByID", mock.Anything, uint(1), true).Return(&entities.Article{ID: 1, Version: 1}, nil)
+
+	resp, err := service.Restore(context.Background(), 1, AnyVersion)
+
+	assert.ErrorIs(t, err, ErrArticleNotFound)
+	assert.Nil(t, resp)
+	mockRepo.AssertNotCalled(t, "Restore")
+}
+
+func TestPurgeUsesRetention(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	logger := zap.NewNop()
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), logger)
+
+	retention := 24 * time.Hour
+	mockRepo.On("Purge", mock.Anything, mock.MatchedBy(func(before time.Time) bool {
+		return time.Since(before) >= retention && time.Since(before) < retention+time.Minute
+	})).Return(int64(3), nil)
+
+	resp, err := service.Purge(context.Background(), retention)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), resp.Purged)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdateArticleWithStaleVersion(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	logger := zap.NewNop()
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), logger)
+
+	mockRepo.On("GetByID", mock.Anything, uint(1), false).Return(&entities.Article{ID: 1, Title: "Title", Version: 5}, nil)
+
+	resp, err := service.Update(context.Background(), 1, 4, dto.UpdateArticleRequest{Title: "New"})
+
+	assert.ErrorIs(t, err, ErrVersionConflict)
+	assert.Nil(t, resp)
+	mockRepo.AssertNotCalled(t, "Update")
+}
+
+func TestUpdateArticleLosingConcurrentRace(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	logger := zap.NewNop()
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), logger)
+
+	mockRepo.On("GetByID", mock.Anything, uint(1), false).Return(&entities.Article{ID: 1, Title: "Title", Version: 5}, nil)
+	// another writer bumped the version between the read and the conditional update
+	mockRepo.On("Update", mock.Anything, mock.Anything).Return(ErrNotFound)
+
+	resp, err := service.Update(context.Background(), 1, 5, dto.UpdateArticleRequest{Title: "New"})
+
+	assert.ErrorIs(t, err, ErrVersionConflict)
+	assert.Nil(t, resp)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateArticleRendersMarkdownBody(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	logger := zap.NewNop()
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), logger)
+
+	req := dto.CreateArticleRequest{
+		Title:   "Rich",
+		Body:    "Hello **world**\n\n<script>alert(1)</script>",
+		Summary: "  A greeting ",
+	}
+
+	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(a *entities.Article) bool {
+		return a.Body == req.Body &&
+			a.Summary == "A greeting" &&
+			strings.Contains(a.BodyHTML, "<strong>world</strong>") &&
+			!strings.Contains(a.BodyHTML, "<script") &&
+			a.Excerpt == "Hello world"
+	})).Return(nil)
+
+	resp, err := service.Create(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, resp)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPatchArticleBodyRefreshesRendering(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	logger := zap.NewNop()
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), logger)
+
+	existing := &entities.Article{ID: 1, Title: "Title", Body: "old", BodyHTML: "<p>old</p>", Excerpt: "old", Summary: "kept"}
+	mockRepo.On("GetByID", mock.Anything, uint(1), false).Return(existing, nil)
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(a *entities.Article) bool {
+		return a.Title == "Title" && a.Summary == "kept" && a.BodyHTML == "<h1>new</h1>\n" && a.Excerpt == "new"
+	})).Return(nil)
+
+	resp, err := service.Patch(context.Background(), 1, AnyVersion, dto.MergePatchContentType, []byte(`{"body":"# new"}`))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "# new", resp.Body)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateArticleNormalizesLabels(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), zap.NewNop())
+
+	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(a *entities.Article) bool {
+		return assert.ObjectsAreEqual([]entities.Tag{{Name: "go"}, {Name: "web dev"}}, a.Tags) &&
+			assert.ObjectsAreEqual([]entities.Category{{Name: "backend"}}, a.Categories)
+	})).Return(nil)
+
+	_, err := service.Create(context.Background(), dto.CreateArticleRequest{
+		Title:      "Tagged",
+		Tags:       []string{" Go ", "web   dev", "go"},
+		Categories: []string{"Backend"},
+	})
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateArticleWithInvalidTags(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), zap.NewNop())
+
+	for _, tags := range [][]string{{" "}, {strings.Repeat("x", maxLabelLength+1)}} {
+		_, err := service.Create(context.Background(), dto.CreateArticleRequest{Title: "Tagged", Tags: tags})
+		assert.ErrorIs(t, err, ErrInvalidArticle)
+	}
+	mockRepo.AssertNotCalled(t, "Create")
+}
+
+func TestListPassesTagFilter(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), zap.NewNop())
+
+	mockRepo.On("List", mock.Anything, mock.MatchedBy(func(f entities.ArticleFilter) bool {
+		return assert.ObjectsAreEqual([]string{"go", "sql"}, f.Tags) &&
+			f.TagMatch == entities.TagMatchAll && f.Category == "backend"
+	})).Return([]entities.Article{}, int64(0), nil)
+
+	_, err := service.List(context.Background(), dto.ListArticlesRequest{
+		Tags:     []string{"Go,sql", "go"},
+		TagMatch: "all",
+		Category: " Backend",
+	})
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPatchArticleKeepsTags(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), zap.NewNop())
+
+	existing := &entities.Article{ID: 1, Title: "Old", Tags: []entities.Tag{{ID: 3, Name: "go"}}, Version: 1}
+	mockRepo.On("GetByID", mock.Anything, uint(1), false).Return(existing, nil)
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(a *entities.Article) bool {
+		return a.Title == "New" && len(a.Tags) == 1 && a.Tags[0].Name == "go"
+	})).Return(nil)
+
+	resp, err := service.Patch(context.Background(), 1, AnyVersion, dto.MergePatchContentType, []byte(`{"title":"New"}`))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"go"}, resp.Tags)
+}
+
+func TestListTagsReturnsCounts(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), zap.NewNop())
+
+	mockRepo.On("TagCounts", mock.Anything).Return([]entities.TagCount{{Name: "go", Count: 2}}, nil)
+
+	resp, err := service.ListTags(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []dto.TagCountResponse{{Name: "go", Count: 2}}, resp.Items)
+}
+
+func TestTransitionSubmitsDraft(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), zap.NewNop())
+
+	mockRepo.On("GetByID", mock.Anything, uint(1), false).
+		Return(&entities.Article{ID: 1, Title: "Draft", Status: entities.StatusDraft, Version: 2}, nil)
+	mockRepo.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(a *entities.Article) bool {
+		return a.Status == entities.StatusInReview && a.PublishedAt == nil && a.Version == 2
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*entities.Article).Version++
+	}).Return(nil)
+
+	resp, err := service.Transition(context.Background(), 1, 2, entities.StatusInReview)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "in_review", resp.Status)
+	assert.Equal(t, uint(3), resp.Version)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTransitionPublishSetsPublishedAt(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), zap.NewNop())
+
+	mockRepo.On("GetByID", mock.Anything, uint(1), false).
+		Return(&entities.Article{ID: 1, Status: entities.StatusInReview, Version: 1}, nil)
+	mockRepo.On("UpdateStatus", mock.Anything, mock.Anything).Return(nil)
+
+	resp, err := service.Transition(withPrincipal("reviewer", auth.RoleAdmin), 1, AnyVersion, entities.StatusPublished)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "published", resp.Status)
+	assert.NotNil(t, resp.PublishedAt)
+}
+
+func TestTransitionRejectsDisallowedMove(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), zap.NewNop())
+
+	mockRepo.On("GetByID", mock.Anything, uint(1), false).
+		Return(&entities.Article{ID: 1, Status: entities.StatusDraft, Version: 1}, nil)
+
+	_, err := service.Transition(context.Background(), 1, AnyVersion, entities.StatusPublished)
+
+	assert.ErrorIs(t, err, ErrInvalidTransition)
+	mockRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything)
+}
+
+func TestTransitionPublishRequiresAdmin(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	authors := new(MockAuthorRepository)
+	service := NewArticleService(mockRepo, authors, zap.NewNop())
+
+	mockRepo.On("GetByID", mock.Anything, uint(1), false).
+		Return(&entities.Article{ID: 1, AuthorID: ptr(uint(7)), Status: entities.StatusInReview, Version: 1}, nil)
+	authors.On("GetByID", mock.Anything, uint(7)).Return(&entities.Author{ID: 7, Subject: ptr("alice")}, nil)
+
+	_, err := service.Transition(withPrincipal("alice"), 1, AnyVersion, entities.StatusPublished)
+
+	assert.ErrorIs(t, err, ErrForbidden)
+	mockRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything)
+}
+
+func TestTransitionVersionConflict(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), zap.NewNop())
+
+	mockRepo.On("GetByID", mock.Anything, uint(1), false).
+		Return(&entities.Article{ID: 1, Status: entities.StatusPublished, Version: 4}, nil)
+	mockRepo.On("UpdateStatus", mock.Anything, mock.Anything).Return(ErrNotFound)
+
+	_, err := service.Transition(context.Background(), 1, 4, entities.StatusArchived)
+
+	assert.ErrorIs(t, err, ErrVersionConflict)
+}
+
+func TestGetByIDHidesDraftsFromAnonymousCallers(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), zap.NewNop())
+
+	mockRepo.On("GetByID", mock.Anything, uint(1), false).
+		Return(&entities.Article{ID: 1, Status: entities.StatusDraft}, nil)
+
+	ctx := auth.WithPrincipal(context.Background(), auth.Anonymous)
+	_, err := service.GetByID(ctx, 1, false)
+
+	assert.ErrorIs(t, err, ErrArticleNotFound)
+}
+
+func TestGetByIDShowsDraftsToTheirAuthor(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	authors := new(MockAuthorRepository)
+	service := NewArticleService(mockRepo, authors, zap.NewNop())
+
+	mockRepo.On("GetByID", mock.Anything, uint(1), false).
+		Return(&entities.Article{ID: 1, AuthorID: ptr(uint(7)), Status: entities.StatusDraft}, nil)
+	authors.On("GetByID", mock.Anything, uint(7)).Return(&entities.Author{ID: 7, Subject: ptr("alice")}, nil)
+
+	resp, err := service.GetByID(withPrincipal("alice"), 1, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "draft", resp.Status)
+}
+
+func TestGetByIDHidesDeletedArticlesFromOtherCallers(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	authors := new(MockAuthorRepository)
+	service := NewArticleService(mockRepo, authors, zap.NewNop())
+
+	deleted := &entities.Article{
+		ID:        1,
+		AuthorID:  ptr(uint(7)),
+		Status:    entities.StatusPublished,
+		DeletedAt: gorm.DeletedAt{Time: time.Now(), Valid: true},
+	}
+	mockRepo.On("GetByID", mock.Anything, uint(1), true).Return(deleted, nil)
+	authors.On("GetByID", mock.Anything, uint(7)).Return(&entities.Author{ID: 7, Subject: ptr("alice")}, nil)
+
+	_, err := service.GetByID(auth.WithPrincipal(context.Background(), auth.Anonymous), 1, true)
+	assert.ErrorIs(t, err, ErrArticleNotFound)
+
+	_, err = service.GetByID(withPrincipal("bob"), 1, true)
+	assert.ErrorIs(t, err, ErrArticleNotFound)
+
+	_, err = service.GetByID(withPrincipal("alice"), 1, true)
+	assert.NoError(t, err)
+
+	_, err = service.GetByID(withPrincipal("root", auth.RoleAdmin), 1, true)
+	assert.NoError(t, err)
+}
+
+func TestListDefaultsToPublishedArticles(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), zap.NewNop())
+
+	mockRepo.On("List", mock.Anything, mock.MatchedBy(func(f entities.ArticleFilter) bool {
+		return f.Status == entities.StatusPublished && f.AuthorID == 0
+	})).Return([]entities.Article{}, int64(0), nil)
+
+	ctx := auth.WithPrincipal(context.Background(), auth.Anonymous)
+	_, err := service.List(ctx, dto.ListArticlesRequest{})
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestListUnpublishedLimitedToOwnArticles(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	authors := new(MockAuthorRepository)
+	service := NewArticleService(mockRepo, authors, zap.NewNop())
+
+	authors.On("GetBySubject", mock.Anything, "alice").Return(&entities.Author{ID: 7}, nil)
+	mockRepo.On("List", mock.Anything, mock.MatchedBy(func(f entities.ArticleFilter) bool {
+		return f.Status == entities.StatusDraft && f.AuthorID == 7
+	})).Return([]entities.Article{}, int64(0), nil)
+
+	_, err := service.List(withPrincipal("alice"), dto.ListArticlesRequest{Status: "draft"})
+	assert.NoError(t, err)
+
+	_, err = service.List(withPrincipal("alice"), dto.ListArticlesRequest{Status: "draft", AuthorID: 8})
+	assert.ErrorIs(t, err, ErrForbidden)
+
+	anonymous := auth.WithPrincipal(context.Background(), auth.Anonymous)
+	_, err = service.List(anonymous, dto.ListArticlesRequest{Status: "in_review"})
+	assert.ErrorIs(t, err, ErrForbidden)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestListDeletedLimitedToOwnArticles(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	authors := new(MockAuthorRepository)
+	service := NewArticleService(mockRepo, authors, zap.NewNop())
+
+	authors.On("GetBySubject", mock.Anything, "alice").Return(&entities.Author{ID: 7}, nil)
+	mockRepo.On("List", mock.Anything, mock.MatchedBy(func(f entities.ArticleFilter) bool {
+		return f.IncludeDeleted && f.AuthorID == 7
+	})).Return([]entities.Article{}, int64(0), nil)
+
+	_, err := service.List(withPrincipal("alice"), dto.ListArticlesRequest{IncludeDeleted: true})
+	assert.NoError(t, err)
+
+	anonymous := auth.WithPrincipal(context.Background(), auth.Anonymous)
+	_, err = service.List(anonymous, dto.ListArticlesRequest{IncludeDeleted: true})
+	assert.ErrorIs(t, err, ErrForbidden)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestScheduleArticleInReview(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), zap.NewNop())
+
+	publishAt := time.Now().Add(time.Hour)
+	mockRepo.On("GetByID", mock.Anything, uint(1), false).
+		Return(&entities.Article{ID: 1, Status: entities.StatusInReview, Version: 2}, nil)
+	mockRepo.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(a *entities.Article) bool {
+		return a.Status == entities.StatusInReview && a.PublishAt != nil && a.PublishAt.Equal(publishAt)
+	})).Return(nil)
+
+	resp, err := service.Schedule(withPrincipal("reviewer", auth.RoleAdmin), 1, 2, publishAt)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, resp.PublishAt)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestScheduleRejectsPastTimesAndDrafts(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), zap.NewNop())
+
+	_, err := service.Schedule(context.Background(), 1, AnyVersion, time.Now().Add(-time.Minute))
+	assert.ErrorIs(t, err, ErrInvalidArticle)
+
+	mockRepo.On("GetByID", mock.Anything, uint(1), false).
+		Return(&entities.Article{ID: 1, Status: entities.StatusDraft, Version: 1}, nil)
+
+	_, err = service.Schedule(context.Background(), 1, AnyVersion, time.Now().Add(time.Hour))
+	assert.ErrorIs(t, err, ErrInvalidTransition)
+	mockRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything)
+}
+
+func TestScheduleAndUnscheduleRequireAdmin(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), zap.NewNop())
+
+	publishAt := time.Now().Add(time.Hour)
+	mockRepo.On("GetByID", mock.Anything, uint(1), false).
+		Return(&entities.Article{ID: 1, Status: entities.StatusInReview, PublishAt: &publishAt, Version: 2}, nil)
+
+	_, err := service.Schedule(withPrincipal("alice"), 1, 2, publishAt.Add(time.Hour))
+	assert.ErrorIs(t, err, ErrForbidden)
+
+	_, err = service.Unschedule(withPrincipal("alice"), 1, 2)
+	assert.ErrorIs(t, err, ErrForbidden)
+	mockRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything)
+
+	mockRepo.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(a *entities.Article) bool {
+		return a.PublishAt == nil
+	})).Return(nil)
+
+	_, err = service.Unschedule(withPrincipal("reviewer", auth.RoleAdmin), 1, 2)
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTransitionClearsSchedule(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), zap.NewNop())
+
+	publishAt := time.Now().Add(time.Hour)
+	mockRepo.On("GetByID", mock.Anything, uint(1), false).
+		Return(&entities.Article{ID: 1, Status: entities.StatusInReview, PublishAt: &publishAt, Version: 1}, nil)
+	mockRepo.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(a *entities.Article) bool {
+		return a.Status == entities.StatusDraft && a.PublishAt == nil
+	})).Return(nil)
+
+	_, err := service.Transition(context.Background(), 1, AnyVersion, entities.StatusDraft)
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateArticleGeneratesSlug(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), zap.NewNop())
+
+	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(a *entities.Article) bool {
+		return a.Slug == "pryvit-svite"
+	})).Run(func(args mock.Arguments) {
+		// the repository suffixes slugs already taken
+		args.Get(1).(*entities.Article).Slug = "pryvit-svite-2"
+	}).Return(nil)
+
+	resp, err := service.Create(context.Background(), dto.CreateArticleRequest{Title: "Привіт, світе!"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "pryvit-svite-2", resp.Slug)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdateArticleSlugFollowsTitle(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), zap.NewNop())
+
+	mockRepo.On("GetByID", mock.Anything, uint(1), false).
+		Return(&entities.Article{ID: 1, Title: "Old title", Slug: "old-title-2", Version: 1}, nil).Once()
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(a *entities.Article) bool {
+		return a.Slug == "old-title-2"
+	})).Return(nil).Once()
+
+	// punctuation alone doesn't change the slug
+	_, err := service.Update(context.Background(), 1, AnyVersion, dto.UpdateArticleRequest{Title: "Old title!"})
+	assert.NoError(t, err)
+
+	mockRepo.On("GetByID", mock.Anything, uint(1), false).
+		Return(&entities.Article{ID: 1, Title: "Old title", Slug: "old-title-2", Version: 2}, nil).Once()
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(a *entities.Article) bool {
+		return a.Slug == "new-title"
+	})).Return(nil).Once()
+
+	resp, err := service.Update(context.Background(), 1, AnyVersion, dto.UpdateArticleRequest{Title: "New title"})
+	assert.NoError(t, err)
+	assert.Equal(t, "new-title", resp.Slug)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetBySlugHidesDrafts(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), zap.NewNop())
+
+	mockRepo.On("GetBySlug", mock.Anything, "draft").
+		Return(&entities.Article{ID: 1, Slug: "draft", Status: entities.StatusDraft}, nil)
+	mockRepo.On("GetBySlug", mock.Anything, "old").
+		Return(&entities.Article{ID: 2, Slug: "new", Status: entities.StatusPublished}, nil)
+
+	ctx := auth.WithPrincipal(context.Background(), auth.Anonymous)
+	_, err := service.GetBySlug(ctx, "draft")
+	assert.ErrorIs(t, err, ErrArticleNotFound)
+
+	resp, err := service.GetBySlug(ctx, "old")
+	assert.NoError(t, err)
+	assert.Equal(t, "new", resp.Slug)
+}
@@ -0,0 +1,247 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/antonchaban/articles-go/internal/auth"
+	"github.com/antonchaban/articles-go/internal/dto"
+	"github.com/antonchaban/articles-go/internal/entities"
+	"github.com/antonchaban/articles-go/internal/log"
+
+	"go.uber.org/zap"
+)
+
+// AuthorRepository defines the methods that any
+// data storage provider must implement to manage Authors.
+type AuthorRepository interface {
+	Create(ctx context.Context, author *entities.Author) error
+	GetByID(ctx context.Context, id uint) (*entities.Author, error)
+	GetBySubject(ctx context.Context, subject string) (*entities.Author, error)
+	List(ctx context.Context, limit, offset int) ([]entities.Author, int64, error)
+	Update(ctx context.Context, author *entities.Author) error
+	Delete(ctx context.Context, id uint) error
+}
+
+var (
+	// ErrAuthorNotFound is returned when the requested author does not exist.
+	ErrAuthorNotFound = &Error{Kind: KindNotFound, Code: "author_not_found", Message: "author not found"}
+
+	// ErrAuthorExists is returned when the email or subject of an author is already taken.
+	ErrAuthorExists = &Error{Kind: KindConflict, Code: "author_exists", Message: "author with this email or subject already exists"}
+
+	// ErrAuthorHasArticles is returned when deleting an author who still owns articles.
+	ErrAuthorHasArticles = &Error{Kind: KindConflict, Code: "author_has_articles", Message: "author still owns articles"}
+)
+
+type AuthorService struct {
+	repo AuthorRepository
+	log  *zap.Logger
+}
+
+func NewAuthorService(repo AuthorRepository, log *zap.Logger) *AuthorService {
+	return &AuthorService{
+		repo: repo,
+		log:  log.With(zap.String("layer", "service")),
+	}
+}
+
+// logger returns the logger of s annotated with the trace of ctx.
+func (s *AuthorService) logger(ctx context.Context) *zap.Logger {
+	return log.WithTrace(ctx, s.log)
+}
+
+// Create registers a new Author. An authenticated caller registers itself
+// unless it is an admin naming another subject.
+func (s *AuthorService) Create(ctx context.Context, req dto.CreateAuthorRequest) (*dto.AuthorResponse, error) {
+	subject := strings.TrimSpace(req.Subject)
+	if p, ok := auth.FromContext(ctx); ok {
+		switch {
+		case subject == "":
+			subject = p.Subject
+		case subject != p.Subject && !p.IsAdmin():
+			s.logger(ctx).Warn("attempt to register author for another subject", zap.String("subject", p.Subject))
+			return nil, ErrForbidden
+		}
+	}
+
+	author := &entities.Author{
+		Name:  strings.TrimSpace(req.Name),
+		Email: normalizeEmail(req.Email),
+		Bio:   strings.TrimSpace(req.Bio),
+	}
+	if subject != "" {
+		author.Subject = &subject
+	}
+
+	if err := s.repo.Create(ctx, author); err != nil {
+		if errors.Is(err, ErrDuplicate) {
+			return nil, ErrAuthorExists
+		}
+		return nil, err
+	}
+
+	s.logger(ctx).Info("author created", zap.Uint("id", author.ID))
+
+	resp := toAuthorResponse(ctx, author)
+	return &resp, nil
+}
+
+// GetByID retrieves an Author by its ID.
+func (s *AuthorService) GetByID(ctx context.Context, id uint) (*dto.AuthorResponse, error) {
+	author, err := s.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := toAuthorResponse(ctx, author)
+	return &resp, nil
+}
+
+// List returns a page of authors ordered by ID.
+func (s *AuthorService) List(ctx context.Context, req dto.ListAuthorsRequest) (*dto.ListAuthorsResponse, error) {
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+	if limit > maxListLimit {
+		limit = maxListLimit
+	}
+
+	authors, total, err := s.repo.List(ctx, limit, req.Offset)
+	if err != nil {
+		s.logger(ctx).Warn("failed to list authors", zap.Error(err))
+		return nil, err
+	}
+
+	resp := &dto.ListAuthorsResponse{
+		Items: make([]dto.AuthorResponse, 0, len(authors)),
+		Meta: dto.ListMeta{
+			Total:  total,
+			Limit:  limit,
+			Offset: req.Offset,
+		},
+	}
+	for i := range authors {
+		resp.Items = append(resp.Items, toAuthorResponse(ctx, &authors[i]))
+	}
+
+	return resp, nil
+}
+
+// Update replaces the profile of an Author. Only the author themself or an
+// admin may do so.
+func (s *AuthorService) Update(ctx context.Context, id uint, req dto.UpdateAuthorRequest) (*dto.AuthorResponse, error) {
+	author, err := s.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !isOwner(ctx, author) {
+		return nil, ErrForbidden
+	}
+
+	author.Name = strings.TrimSpace(req.Name)
+	author.Email = normalizeEmail(req.Email)
+	author.Bio = strings.TrimSpace(req.Bio)
+	author.UpdatedAt = time.Now().UTC()
+
+	if err := s.repo.Update(ctx, author); err != nil {
+		switch {
+		case errors.Is(err, ErrNotFound):
+			return nil, ErrAuthorNotFound
+		case errors.Is(err, ErrDuplicate):
+			return nil, ErrAuthorExists
+		}
+		return nil, err
+	}
+
+	s.logger(ctx).Info("author updated", zap.Uint("id", id))
+
+	resp := toAuthorResponse(ctx, author)
+	return &resp, nil
+}
+
+// Delete removes an Author who no longer owns any articles. Only the author
+// themself or an admin may do so.
+func (s *AuthorService) Delete(ctx context.Context, id uint) error {
+	author, err := s.load(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !isOwner(ctx, author) {
+		return ErrForbidden
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		switch {
+		case errors.Is(err, ErrNotFound):
+			return ErrAuthorNotFound
+		case errors.Is(err, ErrReferenced):
+			return ErrAuthorHasArticles
+		}
+		return err
+	}
+
+	s.logger(ctx).Info("author deleted", zap.Uint("id", id))
+	return nil
+}
+
+func (s *AuthorService) load(ctx context.Context, id uint) (*entities.Author, error) {
+	author, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrAuthorNotFound
+		}
+		s.logger(ctx).Warn("failed to retrieve author", zap.Uint("id", id), zap.Error(err))
+		return nil, err
+	}
+	return author, nil
+}
+
+// isOwner reports whether the caller may modify resources owned by author.
+// Admins may modify everything; without a principal authentication is
+// disabled and ownership is not enforced.
+func isOwner(ctx context.Context, author *entities.Author) bool {
+	p, ok := auth.FromContext(ctx)
+	if !ok || p.IsAdmin() {
+		return true
+	}
+	return author != nil && author.Subject != nil && *author.Subject == p.Subject
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// mayContact reports whether the caller may see the email of author: only
+// the author themself and admins may. Unlike isOwner it needs the caller to
+// be authenticated, so emails are never public, not even while
+// authentication is disabled.
+func mayContact(ctx context.Context, author *entities.Author) bool {
+	p, ok := auth.FromContext(ctx)
+	if !ok || p.IsAnonymous() {
+		return false
+	}
+	return p.IsAdmin() || (author.Subject != nil && *author.Subject == p.Subject)
+}
+
+// toAuthorResponse converts author for the caller in ctx, leaving out the
+// email unless mayContact allows it.
+func toAuthorResponse(ctx context.Context, a *entities.Author) dto.AuthorResponse {
+	resp := dto.AuthorResponse{
+		ID:        a.ID,
+		Name:      a.Name,
+		Bio:       a.Bio,
+		CreatedAt: a.CreatedAt,
+		UpdatedAt: a.UpdatedAt,
+	}
+	if mayContact(ctx, a) {
+		resp.Email = a.Email
+	}
+	if a.Subject != nil {
+		resp.Subject = *a.Subject
+	}
+	return resp
+}
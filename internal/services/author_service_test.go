@@ -0,0 +1,324 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/antonchaban/articles-go/internal/auth"
+	"github.com/antonchaban/articles-go/internal/dto"
+	"github.com/antonchaban/articles-go/internal/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type MockAuthorRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuthorRepository) Create(ctx context.Context, author *entities.Author) error {
+	args := m.Called(ctx, author)
+	return args.Error(0)
+}
+
+func (m *MockAuthorRepository) GetByID(ctx context.Context, id uint) (*entities.Author, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Author), args.Error(1)
+}
+
+func (m *MockAuthorRepository) GetBySubject(ctx context.Context, subject string) (*entities.Author, error) {
+	args := m.Called(ctx, subject)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Author), args.Error(1)
+}
+
+func (m *MockAuthorRepository) List(ctx context.Context, limit, offset int) ([]entities.Author, int64, error) {
+	args := m.Called(ctx, limit, offset)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]entities.Author), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockAuthorRepository) Update(ctx context.Context, author *entities.Author) error {
+	args := m.Called(ctx, author)
+	return args.Error(0)
+}
+
+func (m *MockAuthorRepository) Delete(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func withPrincipal(subject string, roles ...string) context.Context {
+	return auth.WithPrincipal(context.Background(), auth.Principal{Subject: subject, Roles: roles})
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestCreateAuthorUsesCallerSubject(t *testing.T) {
+	mockRepo := new(MockAuthorRepository)
+	service := NewAuthorService(mockRepo, zap.NewNop())
+
+	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(a *entities.Author) bool {
+		return a.Subject != nil && *a.Subject == "user-1" && a.Email == "jane@example.com"
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*entities.Author).ID = 7
+	}).Return(nil)
+
+	resp, err := service.Create(withPrincipal("user-1"), dto.CreateAuthorRequest{Name: "Jane", Email: " Jane@Example.com "})
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(7), resp.ID)
+	assert.Equal(t, "user-1", resp.Subject)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateAuthorForAnotherSubjectRequiresAdmin(t *testing.T) {
+	mockRepo := new(MockAuthorRepository)
+	service := NewAuthorService(mockRepo, zap.NewNop())
+
+	req := dto.CreateAuthorRequest{Name: "Jane", Email: "jane@example.com", Subject: "user-2"}
+
+	_, err := service.Create(withPrincipal("user-1"), req)
+	assert.ErrorIs(t, err, ErrForbidden)
+	mockRepo.AssertNotCalled(t, "Create")
+
+	mockRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	resp, err := service.Create(withPrincipal("root", auth.RoleAdmin), req)
+	assert.NoError(t, err)
+	assert.Equal(t, "user-2", resp.Subject)
+}
+
+func TestCreateAuthorWithTakenEmail(t *testing.T) {
+	mockRepo := new(MockAuthorRepository)
+	service := NewAuthorService(mockRepo, zap.NewNop())
+
+	mockRepo.On("Create", mock.Anything, mock.Anything).Return(ErrDuplicate)
+
+	resp, err := service.Create(context.Background(), dto.CreateAuthorRequest{Name: "Jane", Email: "jane@example.com"})
+
+	assert.ErrorIs(t, err, ErrAuthorExists)
+	assert.Nil(t, resp)
+}
+
+func TestUpdateForeignAuthorIsForbidden(t *testing.T) {
+	mockRepo := new(MockAuthorRepository)
+	service := NewAuthorService(mockRepo, zap.NewNop())
+
+	mockRepo.On("GetByID", mock.Anything, uint(7)).
+		Return(&entities.Author{ID: 7, Subject: ptr("user-1")}, nil)
+
+	_, err := service.Update(withPrincipal("user-2"), 7, dto.UpdateAuthorRequest{Name: "X", Email: "x@example.com"})
+
+	assert.ErrorIs(t, err, ErrForbidden)
+	mockRepo.AssertNotCalled(t, "Update")
+}
+
+func TestUpdateOwnAuthor(t *testing.T) {
+	mockRepo := new(MockAuthorRepository)
+	service := NewAuthorService(mockRepo, zap.NewNop())
+
+	mockRepo.On("GetByID", mock.Anything, uint(7)).
+		Return(&entities.Author{ID: 7, Name: "Jane", Subject: ptr("user-1"), CreatedAt: time.Now()}, nil)
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(a *entities.Author) bool {
+		return a.Name == "Jane Doe" && !a.UpdatedAt.IsZero()
+	})).Return(nil)
+
+	resp, err := service.Update(withPrincipal("user-1"), 7, dto.UpdateAuthorRequest{Name: "Jane Doe", Email: "jane@example.com"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Jane Doe", resp.Name)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestDeleteAuthorWithArticles(t *testing.T) {
+	mockRepo := new(MockAuthorRepository)
+	service := NewAuthorService(mockRepo, zap.NewNop())
+
+	mockRepo.On("GetByID", mock.Anything, uint(7)).Return(&entities.Author{ID: 7}, nil)
+	mockRepo.On("Delete", mock.Anything, uint(7)).Return(ErrReferenced)
+
+	err := service.Delete(withPrincipal("root", auth.RoleAdmin), 7)
+
+	assert.ErrorIs(t, err, ErrAuthorHasArticles)
+}
+
+func TestDeleteNonExistentAuthor(t *testing.T) {
+	mockRepo := new(MockAuthorRepository)
+	service := NewAuthorService(mockRepo, zap.NewNop())
+
+	mockRepo.On("GetByID", mock.Anything, uint(999)).Return(nil, ErrNotFound)
+
+	err := service.Delete(context.Background(), 999)
+
+	assert.ErrorIs(t, err, ErrAuthorNotFound)
+}
+
+func TestListAuthorsClampsLimit(t *testing.T) {
+	mockRepo := new(MockAuthorRepository)
+	service := NewAuthorService(mockRepo, zap.NewNop())
+
+	mockRepo.On("List", mock.Anything, defaultListLimit, 0).
+		Return([]entities.Author{{ID: 1, Name: "Jane"}}, int64(1), nil)
+
+	resp, err := service.List(context.Background(), dto.ListAuthorsRequest{})
+
+	assert.NoError(t, err)
+	assert.Len(t, resp.Items, 1)
+	assert.Equal(t, defaultListLimit, resp.Meta.Limit)
+}
+
+func TestAuthorEmailIsOnlyShownToAuthorAndAdmins(t *testing.T) {
+	mockRepo := new(MockAuthorRepository)
+	service := NewAuthorService(mockRepo, zap.NewNop())
+
+	mockRepo.On("GetByID", mock.Anything, uint(7)).
+		Return(&entities.Author{ID: 7, Email: "jane@example.com", Subject: ptr("user-1")}, nil)
+
+	for name, tc := range map[string]struct {
+		ctx   context.Context
+		email string
+	}{
+		"author":        {withPrincipal("user-1"), "jane@example.com"},
+		"admin":         {withPrincipal("root", auth.RoleAdmin), "jane@example.com"},
+		"other user":    {withPrincipal("user-2"), ""},
+		"anonymous":     {auth.WithPrincipal(context.Background(), auth.Anonymous), ""},
+		"auth disabled": {context.Background(), ""},
+	} {
+		t.Run(name, func(t *testing.T) {
+			resp, err := service.GetByID(tc.ctx, 7)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.email, resp.Email)
+		})
+	}
+}
+
+func TestListAuthorsHidesEmails(t *testing.T) {
+	mockRepo := new(MockAuthorRepository)
+	service := NewAuthorService(mockRepo, zap.NewNop())
+
+	mockRepo.On("List", mock.Anything, defaultListLimit, 0).
+		Return([]entities.Author{{ID: 1, Name: "Jane", Email: "jane@example.com", Subject: ptr("user-1")}}, int64(1), nil)
+
+	resp, err := service.List(withPrincipal("user-2"), dto.ListAuthorsRequest{})
+
+	assert.NoError(t, err)
+	assert.Empty(t, resp.Items[0].Email)
+}
+
+func TestCreateArticleOwnedByCaller(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	mockAuthors := new(MockAuthorRepository)
+	service := NewArticleService(mockRepo, mockAuthors, zap.NewNop())
+
+	mockAuthors.On("GetBySubject", mock.Anything, "user-1").Return(&entities.Author{ID: 7}, nil)
+	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(a *entities.Article) bool {
+		return a.AuthorID != nil && *a.AuthorID == 7
+	})).Return(nil)
+
+	_, err := service.Create(withPrincipal("user-1"), dto.CreateArticleRequest{Title: "Mine"})
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateArticleWithoutAuthorProfile(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	mockAuthors := new(MockAuthorRepository)
+	service := NewArticleService(mockRepo, mockAuthors, zap.NewNop())
+
+	mockAuthors.On("GetBySubject", mock.Anything, "user-1").Return(nil, ErrNotFound)
+
+	_, err := service.Create(withPrincipal("user-1"), dto.CreateArticleRequest{Title: "Mine"})
+
+	assert.ErrorIs(t, err, ErrForbidden)
+	mockRepo.AssertNotCalled(t, "Create")
+}
+
+func TestCreateArticleForAnotherAuthor(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	mockAuthors := new(MockAuthorRepository)
+	service := NewArticleService(mockRepo, mockAuthors, zap.NewNop())
+
+	mockAuthors.On("GetBySubject", mock.Anything, "user-1").Return(&entities.Author{ID: 7}, nil)
+
+	_, err := service.Create(withPrincipal("user-1"), dto.CreateArticleRequest{Title: "Theirs", AuthorID: ptr(uint(8))})
+	assert.ErrorIs(t, err, ErrForbidden)
+
+	// admins may assign any existing author
+	mockAuthors.On("GetByID", mock.Anything, uint(8)).Return(&entities.Author{ID: 8}, nil)
+	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(a *entities.Article) bool {
+		return a.AuthorID != nil && *a.AuthorID == 8
+	})).Return(nil)
+
+	_, err = service.Create(withPrincipal("root", auth.RoleAdmin), dto.CreateArticleRequest{Title: "Theirs", AuthorID: ptr(uint(8))})
+	assert.NoError(t, err)
+}
+
+func TestUpdateForeignArticleIsForbidden(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	mockAuthors := new(MockAuthorRepository)
+	service := NewArticleService(mockRepo, mockAuthors, zap.NewNop())
+
+	mockRepo.On("GetByID", mock.Anything, uint(1), false).
+		Return(&entities.Article{ID: 1, Title: "Theirs", AuthorID: ptr(uint(8)), Version: 1}, nil)
+	mockAuthors.On("GetByID", mock.Anything, uint(8)).
+		Return(&entities.Author{ID: 8, Subject: ptr("user-2")}, nil)
+
+	_, err := service.Update(withPrincipal("user-1"), 1, AnyVersion, dto.UpdateArticleRequest{Title: "Mine now"})
+
+	assert.ErrorIs(t, err, ErrForbidden)
+	mockRepo.AssertNotCalled(t, "Update")
+}
+
+func TestDeleteOwnArticle(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	mockAuthors := new(MockAuthorRepository)
+	service := NewArticleService(mockRepo, mockAuthors, zap.NewNop())
+
+	mockRepo.On("GetByID", mock.Anything, uint(1), false).
+		Return(&entities.Article{ID: 1, AuthorID: ptr(uint(7)), Version: 3}, nil)
+	mockAuthors.On("GetByID", mock.Anything, uint(7)).
+		Return(&entities.Author{ID: 7, Subject: ptr("user-1")}, nil)
+	mockRepo.On("Delete", mock.Anything, uint(1), uint(3)).Return(nil)
+
+	err := service.Delete(withPrincipal("user-1"), 1, 3)
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUnownedArticleRequiresAdmin(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), zap.NewNop())
+
+	mockRepo.On("GetByID", mock.Anything, uint(1), false).
+		Return(&entities.Article{ID: 1, Version: 1}, nil)
+	mockRepo.On("Delete", mock.Anything, uint(1), uint(1)).Return(nil)
+
+	assert.ErrorIs(t, service.Delete(withPrincipal("user-1"), 1, 1), ErrForbidden)
+	assert.NoError(t, service.Delete(withPrincipal("root", auth.RoleAdmin), 1, 1))
+}
+
+func TestListByUnknownAuthor(t *testing.T) {
+	mockRepo := new(MockArticleRepository)
+	mockAuthors := new(MockAuthorRepository)
+	service := NewArticleService(mockRepo, mockAuthors, zap.NewNop())
+
+	mockAuthors.On("GetByID", mock.Anything, uint(999)).Return(nil, ErrNotFound)
+
+	_, err := service.ListByAuthor(context.Background(), 999, dto.ListArticlesRequest{})
+
+	assert.ErrorIs(t, err, ErrAuthorNotFound)
+	mockRepo.AssertNotCalled(t, "List")
+}
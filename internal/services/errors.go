@@ -0,0 +1,78 @@
+package services
+
+// Kind classifies domain errors by what went wrong, independently of the
+// transport reporting them.
+type Kind int
+
+const (
+	// KindInternal is an unexpected failure; the caller can't do anything about it.
+	KindInternal Kind = iota
+	// KindNotFound means the requested resource does not exist or is hidden from the caller.
+	KindNotFound
+	// KindInvalid means the request itself is malformed, e.g. an undecodable cursor.
+	KindInvalid
+	// KindValidation means the requested state of a resource fails validation.
+	KindValidation
+	// KindConflict means the request conflicts with the current state of a resource.
+	KindConflict
+	// KindStale means the caller based a change on an outdated version of a resource.
+	KindStale
+	// KindForbidden means the caller is not allowed to perform the operation.
+	KindForbidden
+	// KindUnavailable means a dependency like the database can't be reached;
+	// retrying later may succeed.
+	KindUnavailable
+	// KindAborted means the operation was not carried out because another
+	// one it depends on failed.
+	KindAborted
+)
+
+// Error is a domain error. Code is stable and meant for clients to act on,
+// Message is human readable. Errors with equal codes match in errors.Is,
+// so a sentinel also matches instances wrapping a different cause.
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	// Err is the underlying cause, if any.
+	Err error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is an *Error with the same code.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+var (
+	// ErrNotFound is returned by repositories when no record matches a query
+	// or a versioned mutation.
+	ErrNotFound = &Error{Kind: KindNotFound, Code: "not_found", Message: "record not found"}
+
+	// ErrDuplicate is returned by repositories when a record violates a unique constraint.
+	ErrDuplicate = &Error{Kind: KindConflict, Code: "duplicate", Message: "record already exists"}
+
+	// ErrReferenced is returned by repositories when a record can't be
+	// removed because other records still reference it.
+	ErrReferenced = &Error{Kind: KindConflict, Code: "referenced", Message: "record is still referenced"}
+
+	// ErrUnavailable is returned when the storage can't be reached.
+	// Use Unavailable to attach the cause.
+	ErrUnavailable = &Error{Kind: KindUnavailable, Code: "unavailable", Message: "storage unavailable"}
+)
+
+// Unavailable returns an ErrUnavailable caused by err.
+func Unavailable(err error) error {
+	return &Error{Kind: KindUnavailable, Code: ErrUnavailable.Code, Message: ErrUnavailable.Message, Err: err}
+}
@@ -0,0 +1,23 @@
+package services
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer starts a span for every ArticleService operation, so that traces
+// show where the time of a request goes between the service and the
+// database.
+var tracer = otel.Tracer("github.com/antonchaban/articles-go/internal/services")
+
+// endSpan ends span, marking it failed with *err when the operation
+// returned an error. It is deferred with the address of the named error
+// result.
+func endSpan(span trace.Span, err *error) {
+	if *err != nil {
+		span.RecordError(*err)
+		span.SetStatus(codes.Error, (*err).Error())
+	}
+	span.End()
+}
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/antonchaban/articles-go/internal/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/zap"
+)
+
+func TestServiceSpansRecordReturnedErrors(t *testing.T) {
+	// the package tracer follows the first provider set globally
+	spans := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)))
+
+	mockRepo := new(MockArticleRepository)
+	service := NewArticleService(mockRepo, new(MockAuthorRepository), zap.NewNop())
+	mockRepo.On("GetByID", mock.Anything, uint(1), false).Return(&entities.Article{ID: 1}, nil)
+	mockRepo.On("GetByID", mock.Anything, uint(2), false).Return(nil, errors.New("database connection error"))
+
+	_, err := service.GetByID(context.Background(), 1, false)
+	require.NoError(t, err)
+	_, err = service.GetByID(context.Background(), 2, false)
+	require.Error(t, err)
+
+	ended := spans.Ended()
+	require.Len(t, ended, 2)
+	assert.Equal(t, "ArticleService.GetByID", ended[0].Name())
+	assert.Equal(t, codes.Unset, ended[0].Status().Code)
+	assert.Equal(t, codes.Error, ended[1].Status().Code)
+	assert.Equal(t, "database connection error", ended[1].Status().Description)
+	require.Len(t, ended[1].Events(), 1)
+	assert.Equal(t, "exception", ended[1].Events()[0].Name)
+}
@@ -0,0 +1,31 @@
+package database
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// hookOperations registers the callbacks returned by before and after around
+// every operation GORM runs statements for, under the given plugin name.
+func hookOperations(db *gorm.DB, plugin string, before, after func(operation string) func(*gorm.DB)) error {
+	type callback interface {
+		Register(name string, fn func(*gorm.DB)) error
+	}
+	register := func(operation string, b, a callback) error {
+		return errors.Join(
+			b.Register(plugin+":before_"+operation, before(operation)),
+			a.Register(plugin+":after_"+operation, after(operation)),
+		)
+	}
+
+	cb := db.Callback()
+	return errors.Join(
+		register("create", cb.Create().Before("gorm:create"), cb.Create().After("gorm:create")),
+		register("query", cb.Query().Before("gorm:query"), cb.Query().After("gorm:query")),
+		register("update", cb.Update().Before("gorm:update"), cb.Update().After("gorm:update")),
+		register("delete", cb.Delete().Before("gorm:delete"), cb.Delete().After("gorm:delete")),
+		register("row", cb.Row().Before("gorm:row"), cb.Row().After("gorm:row")),
+		register("raw", cb.Raw().Before("gorm:raw"), cb.Raw().After("gorm:raw")),
+	)
+}
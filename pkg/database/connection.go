@@ -0,0 +1,154 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// Drivers supported by Open.
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+// dialectors create the GORM dialector of each supported driver from a DSN.
+var dialectors = map[string]func(dsn string) gorm.Dialector{
+	DriverPostgres: postgres.Open,
+	DriverSQLite:   sqlite.Open,
+}
+
+// Options configures the connection pool of a database and how Open
+// retries connecting to it.
+type Options struct {
+	// MaxOpenConns limits the open connections, 0 means no limit.
+	MaxOpenConns int
+	// MaxIdleConns limits the connections kept open while idle, 0 keeps
+	// the database/sql default of 2.
+	MaxIdleConns int
+	// ConnMaxLifetime closes connections once they are this old, 0 keeps them.
+	ConnMaxLifetime time.Duration
+	// ConnMaxIdleTime closes connections idle for this long, 0 keeps them.
+	ConnMaxIdleTime time.Duration
+
+	Retry RetryPolicy
+}
+
+// RetryPolicy backs off exponentially with jitter between connection
+// attempts. The zero RetryPolicy connects only once.
+type RetryPolicy struct {
+	// InitialInterval is the delay before the second attempt. It doubles
+	// with every further attempt, up to MaxInterval.
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+	// MaxElapsedTime bounds the time spent connecting, including attempts
+	// still waiting for an answer, 0 retries until the context passed to
+	// Open is canceled.
+	MaxElapsedTime time.Duration
+}
+
+// backoff returns the delay after the given failed attempt: its interval
+// with up to half of it taken off at random, so that replicas starting
+// together don't retry in lockstep.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	interval := p.InitialInterval
+	limit := max(p.MaxInterval, p.InitialInterval)
+	for i := 1; i < attempt && interval < limit; i++ {
+		interval *= 2
+	}
+	interval = min(interval, limit)
+	if interval <= 0 {
+		return 0
+	}
+	return interval - rand.N(interval/2+1)
+}
+
+// Open initializes a new GORM DB connection using the given driver,
+// configures its pool and installs the Metrics and Tracing plugins. Until the database
+// answers, connecting is retried as opts.Retry allows, or until ctx is canceled.
+// The schema is managed separately through Migrator. Constraint violations
+// are translated into gorm.ErrDuplicatedKey and gorm.ErrForeignKeyViolated.
+func Open(ctx context.Context, driver, dsn string, opts Options, logger *zap.Logger) (*gorm.DB, error) {
+	dialector, ok := dialectors[driver]
+	if !ok {
+		return nil, fmt.Errorf("unsupported database driver %q", driver)
+	}
+
+	// connect pings with ctx instead
+	cfg := &gorm.Config{TranslateError: true, DisableAutomaticPing: true}
+	if driver == DriverSQLite {
+		// SQLite keeps times as text and compares them as such,
+		// which only orders them correctly within a single time zone
+		cfg.NowFunc = func() time.Time { return time.Now().UTC() }
+	}
+
+	// a single attempt can hang on an unreachable host for much longer
+	if opts.Retry.MaxElapsedTime > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Retry.MaxElapsedTime)
+		defer cancel()
+	}
+
+	log := logger.With(zap.String("layer", "database"), zap.String("driver", driver))
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		db, err := connect(ctx, dialector(dsn), cfg, opts)
+		if err == nil {
+			log.Info("connected to database", zap.Int("attempt", attempt))
+			return db, nil
+		}
+
+		wait := opts.Retry.backoff(attempt)
+		if opts.Retry.InitialInterval <= 0 ||
+			(opts.Retry.MaxElapsedTime > 0 && time.Since(start)+wait > opts.Retry.MaxElapsedTime) {
+			return nil, fmt.Errorf("failed to connect to database after %d attempts: %w", attempt, err)
+		}
+		log.Warn("failed to connect to database, retrying",
+			zap.Int("attempt", attempt), zap.Duration("backoff", wait), zap.Error(err))
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("gave up connecting to database after %d attempts: %w", attempt, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// connect opens a pool configured by opts and makes sure the database answers.
+func connect(ctx context.Context, dialector gorm.Dialector, cfg *gorm.Config, opts Options) (*gorm.DB, error) {
+	db, err := gorm.Open(dialector, cfg)
+	if err != nil {
+		return nil, err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+
+	sqlDB.SetMaxOpenConns(opts.MaxOpenConns)
+	if opts.MaxIdleConns > 0 {
+		sqlDB.SetMaxIdleConns(opts.MaxIdleConns)
+	}
+	sqlDB.SetConnMaxLifetime(opts.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(opts.ConnMaxIdleTime)
+
+	if err := sqlDB.PingContext(ctx); err != nil {
+		sqlDB.Close()
+		return nil, err
+	}
+	for _, plugin := range []gorm.Plugin{Metrics{}, Tracing{}} {
+		if err := db.Use(plugin); err != nil {
+			sqlDB.Close()
+			return nil, err
+		}
+	}
+	return db, nil
+}
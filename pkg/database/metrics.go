@@ -0,0 +1,129 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gorm.io/gorm"
+)
+
+var (
+	dbQueryDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "db_query_duration_seconds",
+			Help:    "Duration of database queries in seconds",
+			Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		},
+		[]string{"table", "operation"},
+	)
+
+	dbQueryErrorsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "db_query_errors_total",
+			Help: "Total number of failed database queries, missing rows aside",
+		},
+		[]string{"table", "operation"},
+	)
+
+	// poolStats exports the statistics of the connection pool of the DB
+	// opened last.
+	poolStats = &poolCollector{}
+)
+
+func init() {
+	prometheus.MustRegister(dbQueryDuration)
+	prometheus.MustRegister(dbQueryErrorsTotal)
+	prometheus.MustRegister(poolStats)
+}
+
+var (
+	dbConnectionsOpen = prometheus.NewDesc("db_connections_open",
+		"Number of established connections to the database, in use or idle", nil, nil)
+	dbConnectionsInUse = prometheus.NewDesc("db_connections_in_use",
+		"Number of connections currently in use", nil, nil)
+	dbConnectionsIdle = prometheus.NewDesc("db_connections_idle",
+		"Number of idle connections", nil, nil)
+	dbWaitCount = prometheus.NewDesc("db_connections_wait_count",
+		"Total number of times a query waited for a free connection", nil, nil)
+	dbWaitDuration = prometheus.NewDesc("db_connections_wait_duration_seconds",
+		"Total time queries waited for a free connection in seconds", nil, nil)
+)
+
+// poolCollector reads sql.DBStats on every scrape.
+type poolCollector struct {
+	db atomic.Pointer[sql.DB]
+}
+
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- dbConnectionsOpen
+	ch <- dbConnectionsInUse
+	ch <- dbConnectionsIdle
+	ch <- dbWaitCount
+	ch <- dbWaitDuration
+}
+
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	db := c.db.Load()
+	if db == nil {
+		return
+	}
+	s := db.Stats()
+	ch <- prometheus.MustNewConstMetric(dbConnectionsOpen, prometheus.GaugeValue, float64(s.OpenConnections))
+	ch <- prometheus.MustNewConstMetric(dbConnectionsInUse, prometheus.GaugeValue, float64(s.InUse))
+	ch <- prometheus.MustNewConstMetric(dbConnectionsIdle, prometheus.GaugeValue, float64(s.Idle))
+	ch <- prometheus.MustNewConstMetric(dbWaitCount, prometheus.GaugeValue, float64(s.WaitCount))
+	ch <- prometheus.MustNewConstMetric(dbWaitDuration, prometheus.GaugeValue, s.WaitDuration.Seconds())
+}
+
+// metricsStartKey is the instance setting holding the start time of a statement.
+const metricsStartKey = "metrics:start"
+
+// Metrics is a GORM plugin that records the duration and the errors of
+// every statement by table and operation, and exports the statistics of
+// the connection pool of the DB. Open installs it.
+type Metrics struct{}
+
+func (Metrics) Name() string {
+	return "metrics"
+}
+
+func (Metrics) Initialize(db *gorm.DB) error {
+	err := hookOperations(db, "metrics", func(string) func(*gorm.DB) { return startTimer }, observeQuery)
+	if err != nil {
+		return err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	poolStats.db.Store(sqlDB)
+	return nil
+}
+
+func startTimer(db *gorm.DB) {
+	db.InstanceSet(metricsStartKey, time.Now())
+}
+
+func observeQuery(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		v, ok := db.InstanceGet(metricsStartKey)
+		if !ok {
+			return
+		}
+
+		// raw statements don't tell which table they work on
+		table := db.Statement.Table
+		if table == "" {
+			table = "unknown"
+		}
+
+		dbQueryDuration.WithLabelValues(table, operation).Observe(time.Since(v.(time.Time)).Seconds())
+		if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+			dbQueryErrorsTotal.WithLabelValues(table, operation).Inc()
+		}
+	}
+}
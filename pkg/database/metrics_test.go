@@ -0,0 +1,72 @@
+package database
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func openMeasuredSQLite(t *testing.T) *gorm.DB {
+	db, err := NewSQLiteConnection(context.Background(), filepath.Join(t.TempDir(), "articles.db"), Options{}, zap.NewNop())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	require.NoError(t, db.Exec("CREATE TABLE notes (id INTEGER PRIMARY KEY, body TEXT NOT NULL)").Error)
+	return db
+}
+
+func observations(t *testing.T, table, operation string) uint64 {
+	var m dto.Metric
+	h := dbQueryDuration.WithLabelValues(table, operation).(prometheus.Histogram)
+	require.NoError(t, h.Write(&m))
+	return m.GetHistogram().GetSampleCount()
+}
+
+func TestMetricsRecordQueryDurationByTableAndOperation(t *testing.T) {
+	db := openMeasuredSQLite(t)
+	type note struct {
+		ID   uint
+		Body string
+	}
+	created, queried := observations(t, "notes", "create"), observations(t, "notes", "query")
+
+	require.NoError(t, db.Create(&note{Body: "hello"}).Error)
+	var notes []note
+	require.NoError(t, db.Find(&notes).Error)
+
+	assert.Equal(t, created+1, observations(t, "notes", "create"))
+	assert.Equal(t, queried+1, observations(t, "notes", "query"))
+}
+
+func TestMetricsCountFailedQueries(t *testing.T) {
+	db := openMeasuredSQLite(t)
+	failed := testutil.ToFloat64(dbQueryErrorsTotal.WithLabelValues("notes", "create"))
+	missing := testutil.ToFloat64(dbQueryErrorsTotal.WithLabelValues("notes", "query"))
+
+	err := db.Table("notes").Create(map[string]any{"body": nil}).Error
+	require.Error(t, err)
+	err = db.Table("notes").Where("id = ?", 42).Take(&map[string]any{}).Error
+	require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	assert.Equal(t, failed+1, testutil.ToFloat64(dbQueryErrorsTotal.WithLabelValues("notes", "create")))
+	assert.Equal(t, missing, testutil.ToFloat64(dbQueryErrorsTotal.WithLabelValues("notes", "query")),
+		"missing rows are not errors")
+}
+
+func TestMetricsExportConnectionPoolStats(t *testing.T) {
+	openMeasuredSQLite(t)
+
+	assert.Equal(t, 5, testutil.CollectAndCount(poolStats, "db_connections_open", "db_connections_in_use",
+		"db_connections_idle", "db_connections_wait_count", "db_connections_wait_duration_seconds"))
+}
@@ -0,0 +1,211 @@
+package database
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func setupMigrator(t *testing.T, migrations []Migration) (*Migrator, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+	require.NoError(t, err)
+
+	return &Migrator{db: gormDB, migrations: migrations}, mock
+}
+
+func TestEmbeddedMigrationsAreOrderedAndComplete(t *testing.T) {
+	for dir, fsys := range map[string]fs.FS{
+		"migrations/postgres": postgresMigrations,
+		"migrations/sqlite":   sqliteMigrations,
+	} {
+		t.Run(dir, func(t *testing.T) {
+			migrations, err := loadMigrations(fsys, dir)
+
+			require.NoError(t, err)
+			require.NotEmpty(t, migrations)
+			for i, m := range migrations {
+				assert.Equal(t, int64(i+1), m.Version, "migration versions must be contiguous")
+				assert.NotEmpty(t, m.Up)
+				assert.NotEmpty(t, m.Down)
+			}
+		})
+	}
+}
+
+func TestLoadMigrationsRequiresBothDirections(t *testing.T) {
+	fsys := fstest.MapFS{
+		"m/0001_init.up.sql": {Data: []byte("CREATE TABLE t (id int);")},
+	}
+
+	_, err := loadMigrations(fsys, "m")
+
+	assert.ErrorContains(t, err, "must have both up and down")
+}
+
+func TestLoadMigrationsRejectsUnexpectedFiles(t *testing.T) {
+	fsys := fstest.MapFS{
+		"m/init.sql": {Data: []byte("CREATE TABLE t (id int);")},
+	}
+
+	_, err := loadMigrations(fsys, "m")
+
+	assert.ErrorContains(t, err, "unexpected migration file name")
+}
+
+func TestMigratorUpAppliesPendingMigrationsUnderLock(t *testing.T) {
+	migrator, mock := setupMigrator(t, []Migration{
+		{Version: 1, Name: "one", Up: "CREATE TABLE one (id int)", Down: "DROP TABLE one"},
+		{Version: 2, Name: "two", Up: "CREATE TABLE two (id int)", Down: "DROP TABLE two"},
+	})
+
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_lock($1)`)).
+		WithArgs(migrationLockKey).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`CREATE TABLE IF NOT EXISTS schema_migrations`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT version, applied_at FROM schema_migrations`)).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).AddRow(1, time.Now()))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`CREATE TABLE two (id int)`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`)).
+		WithArgs(2, "two").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_unlock($1)`)).
+		WithArgs(migrationLockKey).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	applied, err := migrator.Up(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, applied, 1)
+	assert.Equal(t, int64(2), applied[0].Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigratorUpStopsAtFailingMigration(t *testing.T) {
+	migrator, mock := setupMigrator(t, []Migration{
+		{Version: 1, Name: "one", Up: "CREATE TABLE one (id int)", Down: "DROP TABLE one"},
+		{Version: 2, Name: "two", Up: "CREATE TABLE two (id int)", Down: "DROP TABLE two"},
+	})
+
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_lock($1)`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`CREATE TABLE IF NOT EXISTS schema_migrations`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT version, applied_at FROM schema_migrations`)).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`CREATE TABLE one (id int)`)).
+		WillReturnError(assert.AnError)
+	mock.ExpectRollback()
+
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_unlock($1)`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	applied, err := migrator.Up(context.Background())
+
+	assert.ErrorContains(t, err, "migration 1_one failed")
+	assert.Empty(t, applied)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigratorDownRollsBackLatestMigrations(t *testing.T) {
+	migrator, mock := setupMigrator(t, []Migration{
+		{Version: 1, Name: "one", Up: "CREATE TABLE one (id int)", Down: "DROP TABLE one"},
+		{Version: 2, Name: "two", Up: "CREATE TABLE two (id int)", Down: "DROP TABLE two"},
+	})
+
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_lock($1)`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`CREATE TABLE IF NOT EXISTS schema_migrations`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT version, applied_at FROM schema_migrations`)).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).
+			AddRow(1, time.Now()).
+			AddRow(2, time.Now()))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DROP TABLE two`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM schema_migrations WHERE version = $1`)).
+		WithArgs(2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_unlock($1)`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	reverted, err := migrator.Down(context.Background(), 1)
+
+	require.NoError(t, err)
+	require.Len(t, reverted, 1)
+	assert.Equal(t, int64(2), reverted[0].Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigratorStatusReportsPendingMigrations(t *testing.T) {
+	migrator, mock := setupMigrator(t, []Migration{
+		{Version: 1, Name: "one", Up: "CREATE TABLE one (id int)", Down: "DROP TABLE one"},
+		{Version: 2, Name: "two", Up: "CREATE TABLE two (id int)", Down: "DROP TABLE two"},
+	})
+
+	appliedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectExec(regexp.QuoteMeta(`CREATE TABLE IF NOT EXISTS schema_migrations`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT version, applied_at FROM schema_migrations`)).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).AddRow(1, appliedAt))
+
+	statuses, err := migrator.Status(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+	assert.True(t, statuses[0].Applied)
+	assert.Equal(t, appliedAt, *statuses[0].AppliedAt)
+	assert.False(t, statuses[1].Applied)
+	assert.Nil(t, statuses[1].AppliedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLiteMigrationsApplyAndRollBack(t *testing.T) {
+	db, err := NewSQLiteConnection(context.Background(), filepath.Join(t.TempDir(), "articles.db"), Options{}, zap.NewNop())
+	require.NoError(t, err)
+	migrator, err := NewMigrator(db)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	applied, err := migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Len(t, applied, len(migrator.migrations))
+	assert.True(t, db.Migrator().HasTable("articles"))
+
+	statuses, err := migrator.Status(ctx)
+	require.NoError(t, err)
+	for _, st := range statuses {
+		assert.True(t, st.Applied, "migration %d_%s", st.Version, st.Name)
+	}
+
+	reverted, err := migrator.Down(ctx, len(applied))
+	require.NoError(t, err)
+	assert.Len(t, reverted, len(applied))
+	assert.False(t, db.Migrator().HasTable("articles"))
+}
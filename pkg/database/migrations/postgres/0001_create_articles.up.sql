@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS articles (
+    id         bigserial PRIMARY KEY,
+    title      text        NOT NULL,
+    created_at timestamptz
+);
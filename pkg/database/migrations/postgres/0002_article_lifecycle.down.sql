@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_articles_deleted_at;
+DROP INDEX IF EXISTS idx_articles_created_at;
+
+ALTER TABLE articles
+    DROP COLUMN IF EXISTS version,
+    DROP COLUMN IF EXISTS deleted_at,
+    DROP COLUMN IF EXISTS updated_at;
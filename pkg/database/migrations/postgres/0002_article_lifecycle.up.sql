@@ -0,0 +1,7 @@
+ALTER TABLE articles
+    ADD COLUMN IF NOT EXISTS updated_at timestamptz,
+    ADD COLUMN IF NOT EXISTS deleted_at timestamptz,
+    ADD COLUMN IF NOT EXISTS version    bigint NOT NULL DEFAULT 1;
+
+CREATE INDEX IF NOT EXISTS idx_articles_created_at ON articles (created_at);
+CREATE INDEX IF NOT EXISTS idx_articles_deleted_at ON articles (deleted_at);
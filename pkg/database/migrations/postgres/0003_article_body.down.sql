@@ -0,0 +1,5 @@
+ALTER TABLE articles
+    DROP COLUMN IF EXISTS body_html,
+    DROP COLUMN IF EXISTS excerpt,
+    DROP COLUMN IF EXISTS summary,
+    DROP COLUMN IF EXISTS body;
@@ -0,0 +1,5 @@
+ALTER TABLE articles
+    ADD COLUMN IF NOT EXISTS body      text,
+    ADD COLUMN IF NOT EXISTS summary   text,
+    ADD COLUMN IF NOT EXISTS excerpt   text,
+    ADD COLUMN IF NOT EXISTS body_html text;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_articles_author_id;
+
+ALTER TABLE articles
+    DROP COLUMN IF EXISTS author_id;
+
+DROP TABLE IF EXISTS authors;
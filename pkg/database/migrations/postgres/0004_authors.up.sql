@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS authors (
+    id         bigserial PRIMARY KEY,
+    name       text        NOT NULL,
+    email      text        NOT NULL,
+    bio        text,
+    subject    text,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_authors_email ON authors (email);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_authors_subject ON authors (subject);
+
+ALTER TABLE articles
+    ADD COLUMN IF NOT EXISTS author_id bigint REFERENCES authors (id) ON DELETE RESTRICT;
+
+CREATE INDEX IF NOT EXISTS idx_articles_author_id ON articles (author_id);
@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS tags (
+    id   bigserial PRIMARY KEY,
+    name text NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_name ON tags (name);
+
+CREATE TABLE IF NOT EXISTS categories (
+    id   bigserial PRIMARY KEY,
+    name text NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_name ON categories (name);
+
+-- the primary keys serve lookups by article, the extra indexes lookups by label
+CREATE TABLE IF NOT EXISTS article_tags (
+    article_id bigint NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+    tag_id     bigint NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+    PRIMARY KEY (article_id, tag_id)
+);
+CREATE INDEX IF NOT EXISTS idx_article_tags_tag_id ON article_tags (tag_id, article_id);
+
+CREATE TABLE IF NOT EXISTS article_categories (
+    article_id  bigint NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+    category_id bigint NOT NULL REFERENCES categories (id) ON DELETE CASCADE,
+    PRIMARY KEY (article_id, category_id)
+);
+CREATE INDEX IF NOT EXISTS idx_article_categories_category_id ON article_categories (category_id, article_id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_articles_search_vector;
+
+ALTER TABLE articles
+    DROP COLUMN IF EXISTS search_vector,
+    DROP COLUMN IF EXISTS language;
@@ -0,0 +1,13 @@
+-- language selects the text search configuration used to index the article
+ALTER TABLE articles
+    ADD COLUMN IF NOT EXISTS language regconfig NOT NULL DEFAULT 'english';
+
+ALTER TABLE articles
+    ADD COLUMN IF NOT EXISTS search_vector tsvector
+        GENERATED ALWAYS AS (
+            setweight(to_tsvector(language, coalesce(title, '')), 'A') ||
+            setweight(to_tsvector(language, coalesce(summary, '')), 'B') ||
+            setweight(to_tsvector(language, coalesce(body, '')), 'C')
+        ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_articles_search_vector ON articles USING GIN (search_vector);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_articles_status;
+
+ALTER TABLE articles
+    DROP COLUMN IF EXISTS published_at,
+    DROP COLUMN IF EXISTS status;
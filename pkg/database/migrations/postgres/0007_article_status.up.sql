@@ -0,0 +1,9 @@
+ALTER TABLE articles
+    ADD COLUMN IF NOT EXISTS status varchar(16) NOT NULL DEFAULT 'draft'
+        CHECK (status IN ('draft', 'in_review', 'published', 'archived')),
+    ADD COLUMN IF NOT EXISTS published_at timestamptz;
+
+-- articles created before the workflow existed were public, keep them so
+UPDATE articles SET status = 'published', published_at = created_at;
+
+CREATE INDEX IF NOT EXISTS idx_articles_status ON articles (status);
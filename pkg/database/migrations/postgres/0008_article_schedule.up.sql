@@ -0,0 +1,6 @@
+ALTER TABLE articles
+    ADD COLUMN IF NOT EXISTS publish_at timestamptz;
+
+-- the scheduler only ever looks for scheduled articles under review
+CREATE INDEX IF NOT EXISTS idx_articles_publish_at ON articles (publish_at)
+    WHERE status = 'in_review' AND publish_at IS NOT NULL;
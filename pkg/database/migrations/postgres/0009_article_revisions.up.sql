@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS article_revisions (
+    id         bigserial PRIMARY KEY,
+    article_id bigint      NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+    version    bigint      NOT NULL,
+    title      text        NOT NULL,
+    summary    text,
+    body       text,
+    status     varchar(16),
+    tags       jsonb       NOT NULL DEFAULT '[]',
+    categories jsonb       NOT NULL DEFAULT '[]',
+    created_by text,
+    created_at timestamptz
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_article_revisions_version ON article_revisions (article_id, version);
+
+-- start the history of existing articles with their current state
+INSERT INTO article_revisions (article_id, version, title, summary, body, status, tags, categories, created_at)
+SELECT a.id, a.version, a.title, a.summary, a.body, a.status,
+       COALESCE((SELECT jsonb_agg(t.name ORDER BY t.name)
+                 FROM article_tags at JOIN tags t ON t.id = at.tag_id
+                 WHERE at.article_id = a.id), '[]'),
+       COALESCE((SELECT jsonb_agg(c.name ORDER BY c.name)
+                 FROM article_categories ac JOIN categories c ON c.id = ac.category_id
+                 WHERE ac.article_id = a.id), '[]'),
+       COALESCE(a.updated_at, a.created_at)
+FROM articles a
+ON CONFLICT DO NOTHING;
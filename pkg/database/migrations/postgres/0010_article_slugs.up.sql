@@ -0,0 +1,46 @@
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS slug text;
+
+-- the index is created first so the backfill below can use it; rows still
+-- without a slug don't conflict
+CREATE UNIQUE INDEX IF NOT EXISTS idx_articles_slug ON articles (slug);
+
+-- existing articles get a plain ASCII slug of their title, cut to 100
+-- characters before trimming so it never ends in a hyphen. Titles without
+-- any ASCII letters or digits fall back to "article". Like the application,
+-- taken slugs are numbered "-2", "-3" and so on until a free one is found,
+-- which also skips natural slugs that happen to end in such a number.
+DO $$
+DECLARE
+    a         record;
+    base      text;
+    candidate text;
+    n         integer;
+BEGIN
+    FOR a IN SELECT id, title FROM articles WHERE slug IS NULL ORDER BY id LOOP
+        base := trim(both '-' FROM left(regexp_replace(lower(a.title), '[^a-z0-9]+', '-', 'g'), 100));
+        IF base = '' THEN
+            base := 'article';
+        END IF;
+
+        candidate := base;
+        n := 2;
+        WHILE EXISTS (SELECT 1 FROM articles WHERE slug = candidate) LOOP
+            candidate := base || '-' || n;
+            n := n + 1;
+        END LOOP;
+
+        UPDATE articles SET slug = candidate WHERE id = a.id;
+    END LOOP;
+END
+$$;
+
+ALTER TABLE articles ALTER COLUMN slug SET NOT NULL;
+
+-- former slugs keep old links working
+CREATE TABLE IF NOT EXISTS article_slugs (
+    slug       text PRIMARY KEY,
+    article_id bigint NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+    created_at timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS idx_article_slugs_article_id ON article_slugs (article_id);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    subject      text        NOT NULL,
+    key          text        NOT NULL,
+    request_hash text        NOT NULL,
+    status       integer     NOT NULL DEFAULT 0,
+    header       jsonb,
+    body         bytea,
+    created_at   timestamptz NOT NULL,
+    expires_at   timestamptz NOT NULL,
+    PRIMARY KEY (subject, key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
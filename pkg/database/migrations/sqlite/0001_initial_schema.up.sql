@@ -0,0 +1,133 @@
+-- SQLite has no migration history to replay, so it starts from the schema
+-- the PostgreSQL migrations have built up
+CREATE TABLE IF NOT EXISTS authors (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    name       TEXT     NOT NULL,
+    email      TEXT     NOT NULL,
+    bio        TEXT,
+    subject    TEXT,
+    created_at DATETIME,
+    updated_at DATETIME
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_authors_email ON authors (email);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_authors_subject ON authors (subject);
+
+CREATE TABLE IF NOT EXISTS articles (
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    title        TEXT     NOT NULL,
+    slug         TEXT     NOT NULL,
+    author_id    INTEGER REFERENCES authors (id) ON DELETE RESTRICT,
+    body         TEXT,
+    summary      TEXT,
+    excerpt      TEXT,
+    body_html    TEXT,
+    -- kept for parity with PostgreSQL, the full text index always uses English stemming
+    language     TEXT     NOT NULL DEFAULT 'english',
+    status       TEXT     NOT NULL DEFAULT 'draft'
+        CHECK (status IN ('draft', 'in_review', 'published', 'archived')),
+    published_at DATETIME,
+    publish_at   DATETIME,
+    created_at   DATETIME,
+    updated_at   DATETIME,
+    deleted_at   DATETIME,
+    version      INTEGER  NOT NULL DEFAULT 1
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_articles_slug ON articles (slug);
+CREATE INDEX IF NOT EXISTS idx_articles_author_id ON articles (author_id);
+CREATE INDEX IF NOT EXISTS idx_articles_created_at ON articles (created_at);
+CREATE INDEX IF NOT EXISTS idx_articles_deleted_at ON articles (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_articles_status ON articles (status);
+CREATE INDEX IF NOT EXISTS idx_articles_publish_at ON articles (publish_at)
+    WHERE status = 'in_review' AND publish_at IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS tags (
+    id   INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_name ON tags (name);
+
+CREATE TABLE IF NOT EXISTS categories (
+    id   INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_name ON categories (name);
+
+CREATE TABLE IF NOT EXISTS article_tags (
+    article_id INTEGER NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+    tag_id     INTEGER NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+    PRIMARY KEY (article_id, tag_id)
+);
+CREATE INDEX IF NOT EXISTS idx_article_tags_tag_id ON article_tags (tag_id, article_id);
+
+CREATE TABLE IF NOT EXISTS article_categories (
+    article_id  INTEGER NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+    category_id INTEGER NOT NULL REFERENCES categories (id) ON DELETE CASCADE,
+    PRIMARY KEY (article_id, category_id)
+);
+CREATE INDEX IF NOT EXISTS idx_article_categories_category_id ON article_categories (category_id, article_id);
+
+CREATE TABLE IF NOT EXISTS article_revisions (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    article_id INTEGER  NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+    version    INTEGER  NOT NULL,
+    title      TEXT     NOT NULL,
+    summary    TEXT,
+    body       TEXT,
+    status     TEXT,
+    tags       TEXT     NOT NULL DEFAULT '[]',
+    categories TEXT     NOT NULL DEFAULT '[]',
+    created_by TEXT,
+    created_at DATETIME
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_article_revisions_version ON article_revisions (article_id, version);
+
+-- former slugs keep old links working
+CREATE TABLE IF NOT EXISTS article_slugs (
+    slug       TEXT PRIMARY KEY,
+    article_id INTEGER NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+    created_at DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_article_slugs_article_id ON article_slugs (article_id);
+
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    subject      TEXT     NOT NULL,
+    key          TEXT     NOT NULL,
+    request_hash TEXT     NOT NULL,
+    status       INTEGER  NOT NULL DEFAULT 0,
+    header       TEXT,
+    body         BLOB,
+    created_at   DATETIME NOT NULL,
+    expires_at   DATETIME NOT NULL,
+    PRIMARY KEY (subject, key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
+
+-- the full text index reads the articles table and is kept up to date by
+-- triggers; bm25 weights the columns like the tsvector weights do
+CREATE VIRTUAL TABLE IF NOT EXISTS articles_fts USING fts5 (
+    title, summary, body,
+    content = 'articles', content_rowid = 'id',
+    tokenize = 'porter unicode61 remove_diacritics 2'
+);
+
+CREATE TRIGGER IF NOT EXISTS articles_fts_insert AFTER INSERT ON articles BEGIN
+    INSERT INTO articles_fts (rowid, title, summary, body)
+    VALUES (new.id, new.title, coalesce(new.summary, ''), coalesce(new.body, ''));
+END;
+
+CREATE TRIGGER IF NOT EXISTS articles_fts_delete AFTER DELETE ON articles BEGIN
+    INSERT INTO articles_fts (articles_fts, rowid, title, summary, body)
+    VALUES ('delete', old.id, old.title, coalesce(old.summary, ''), coalesce(old.body, ''));
+END;
+
+CREATE TRIGGER IF NOT EXISTS articles_fts_update AFTER UPDATE OF title, summary, body ON articles BEGIN
+    INSERT INTO articles_fts (articles_fts, rowid, title, summary, body)
+    VALUES ('delete', old.id, old.title, coalesce(old.summary, ''), coalesce(old.body, ''));
+    INSERT INTO articles_fts (rowid, title, summary, body)
+    VALUES (new.id, new.title, coalesce(new.summary, ''), coalesce(new.body, ''));
+END;
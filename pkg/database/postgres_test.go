@@ -1,13 +1,20 @@
 package database
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"net"
+	"path/filepath"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
@@ -38,11 +45,97 @@ func TestNewPostgresConnectionSuccess(t *testing.T) {
 
 func TestNewPostgresConnectionWithInvalidDSN(t *testing.T) {
 	invalidDSN := "invalid connection string"
+	opts := Options{Retry: RetryPolicy{InitialInterval: time.Millisecond, MaxElapsedTime: 50 * time.Millisecond}}
+	core, logs := observer.New(zap.WarnLevel)
 
-	db, err := NewPostgresConnection(invalidDSN)
+	db, err := NewPostgresConnection(context.Background(), invalidDSN, opts, zap.New(core))
+
+	assert.ErrorContains(t, err, "failed to connect to database after")
+	assert.Nil(t, db)
+	assert.NotZero(t, logs.FilterMessage("failed to connect to database, retrying").Len())
+}
+
+func TestOpenStopsRetryingWhenContextIsCanceled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	// without a limit only the context ends the retries
+	opts := Options{Retry: RetryPolicy{InitialInterval: time.Millisecond, MaxInterval: 5 * time.Millisecond}}
+
+	db, err := Open(ctx, DriverPostgres, "invalid connection string", opts, zap.NewNop())
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Nil(t, db)
+}
+
+// silentServer accepts connections and never answers, like a host behind a
+// firewall dropping packets, but without depending on the network of the
+// machine running the tests. It returns its address.
+func silentServer(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+	go func() {
+		var conns []net.Conn
+		defer func() {
+			for _, c := range conns {
+				c.Close()
+			}
+		}()
+		for {
+			c, err := l.Accept()
+			if err != nil {
+				return
+			}
+			conns = append(conns, c)
+		}
+	}()
+	return l.Addr().String()
+}
+
+func TestOpenGivesUpOnUnresponsiveHostInTime(t *testing.T) {
+	host, port, err := net.SplitHostPort(silentServer(t))
+	require.NoError(t, err)
+	dsn := fmt.Sprintf("host=%s port=%s user=articles dbname=articles sslmode=disable connect_timeout=30", host, port)
+	opts := Options{Retry: RetryPolicy{InitialInterval: 10 * time.Millisecond, MaxElapsedTime: 200 * time.Millisecond}}
+
+	start := time.Now()
+	db, err := Open(context.Background(), DriverPostgres, dsn, opts, zap.NewNop())
 
 	assert.Error(t, err)
 	assert.Nil(t, db)
+	assert.Less(t, time.Since(start), 5*time.Second, "an attempt in progress must not outlast MaxElapsedTime")
+}
+
+func TestOpenWithoutRetryPolicyConnectsOnce(t *testing.T) {
+	core, logs := observer.New(zap.WarnLevel)
+
+	_, err := Open(context.Background(), DriverPostgres, "invalid connection string", Options{}, zap.New(core))
+
+	assert.ErrorContains(t, err, "after 1 attempts")
+	assert.Zero(t, logs.Len())
+}
+
+func TestOpenConfiguresConnectionPool(t *testing.T) {
+	opts := Options{MaxOpenConns: 3, MaxIdleConns: 2, ConnMaxLifetime: time.Minute}
+
+	db, err := Open(context.Background(), DriverSQLite, SQLiteDSN(filepath.Join(t.TempDir(), "articles.db")), opts, zap.NewNop())
+
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+	assert.Equal(t, 3, sqlDB.Stats().MaxOpenConnections)
+}
+
+func TestRetryPolicyBacksOffExponentiallyWithJitter(t *testing.T) {
+	p := RetryPolicy{InitialInterval: 100 * time.Millisecond, MaxInterval: time.Second}
+
+	for range 20 {
+		assert.InDelta(t, 75*time.Millisecond, p.backoff(1), float64(25*time.Millisecond))
+		assert.InDelta(t, 300*time.Millisecond, p.backoff(3), float64(100*time.Millisecond))
+		assert.InDelta(t, 750*time.Millisecond, p.backoff(10), float64(250*time.Millisecond))
+	}
+	assert.Zero(t, RetryPolicy{}.backoff(1))
 }
 
 func TestNewPostgresConnectionWithAutoMigrateError(t *testing.T) {
@@ -71,3 +164,10 @@ func TestNewPostgresConnectionWithAutoMigrateError(t *testing.T) {
 
 	assert.Error(t, err)
 }
+
+func TestOpenRejectsUnknownDriver(t *testing.T) {
+	db, err := Open(context.Background(), "mysql", "user@/articles", Options{}, zap.NewNop())
+
+	assert.ErrorContains(t, err, `unsupported database driver "mysql"`)
+	assert.Nil(t, db)
+}
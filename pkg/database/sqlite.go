@@ -0,0 +1,28 @@
+package database
+
+import (
+	"context"
+	"net/url"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// SQLiteDSN returns the DSN of the SQLite database file at path. Foreign keys
+// are enforced, the journal is written ahead so that readers don't block the
+// writer, and transactions take the write lock up front, so that they wait
+// for it (up to the driver's 5s busy timeout) rather than fail midway when
+// another connection holds it.
+func SQLiteDSN(path string) string {
+	q := url.Values{}
+	q.Add("_pragma", "foreign_keys(1)")
+	q.Add("_pragma", "journal_mode(WAL)")
+	q.Set("_txlock", "immediate")
+	return path + "?" + q.Encode()
+}
+
+// NewSQLiteConnection initializes a new GORM DB connection to the SQLite
+// database file at path, creating it if needed, see Open.
+func NewSQLiteConnection(ctx context.Context, path string, opts Options, logger *zap.Logger) (*gorm.DB, error) {
+	return Open(ctx, DriverSQLite, SQLiteDSN(path), opts, logger)
+}
@@ -0,0 +1,73 @@
+package database
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.43.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// tracingSpanKey is the instance setting holding the span of a statement.
+const tracingSpanKey = "tracing:span"
+
+// dbSystems maps the GORM dialects onto the OpenTelemetry database systems.
+var dbSystems = map[string]attribute.KeyValue{
+	DriverPostgres: semconv.DBSystemNamePostgreSQL,
+	DriverSQLite:   semconv.DBSystemNameSQLite,
+}
+
+// Tracing is a GORM plugin that records every statement as a client span,
+// child of the span in the context the statement runs with. Statements are
+// recorded with their placeholders, never with their arguments. Open
+// installs it; spans go to the global TracerProvider.
+type Tracing struct{}
+
+func (Tracing) Name() string {
+	return "tracing"
+}
+
+func (Tracing) Initialize(db *gorm.DB) error {
+	tracer := otel.Tracer("github.com/antonchaban/articles-go/pkg/database")
+	system, ok := dbSystems[db.Dialector.Name()]
+	if !ok {
+		system = semconv.DBSystemNameKey.String(db.Dialector.Name())
+	}
+
+	start := func(operation string) func(*gorm.DB) {
+		return func(db *gorm.DB) {
+			_, span := tracer.Start(db.Statement.Context, operation,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(system, semconv.DBOperationName(operation)))
+			db.InstanceSet(tracingSpanKey, span)
+		}
+	}
+	end := func(operation string) func(*gorm.DB) {
+		return func(db *gorm.DB) {
+			v, ok := db.InstanceGet(tracingSpanKey)
+			if !ok {
+				return
+			}
+			span := v.(trace.Span)
+			defer span.End()
+
+			// the table is only known once the statement has been built
+			if table := db.Statement.Table; table != "" {
+				span.SetName(operation + " " + table)
+				span.SetAttributes(semconv.DBCollectionName(table))
+			}
+			span.SetAttributes(
+				semconv.DBQueryText(db.Statement.SQL.String()),
+				attribute.Int64("db.response.affected_rows", db.Statement.RowsAffected),
+			)
+			if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+				span.RecordError(db.Error)
+				span.SetStatus(codes.Error, db.Error.Error())
+			}
+		}
+	}
+	return hookOperations(db, "tracing", start, end)
+}
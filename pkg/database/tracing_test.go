@@ -0,0 +1,75 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.43.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// recordSpans installs a TracerProvider recording spans for the rest of the
+// test. It must be called before the DB is opened.
+func recordSpans(t *testing.T) (*tracetest.SpanRecorder, trace.Tracer) {
+	spans := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return spans, provider.Tracer("test")
+}
+
+func TestTracingRecordsStatementsAsChildSpans(t *testing.T) {
+	spans, tracer := recordSpans(t)
+	db := openMeasuredSQLite(t)
+	type note struct {
+		ID   uint
+		Body string
+	}
+
+	ctx, parent := tracer.Start(context.Background(), "request")
+	require.NoError(t, db.WithContext(ctx).Create(&note{Body: "secret"}).Error)
+	parent.End()
+
+	var created sdktrace.ReadOnlySpan
+	for _, s := range spans.Ended() {
+		if s.Name() == "create notes" {
+			created = s
+		}
+	}
+	require.NotNil(t, created, "statement span is named after operation and table")
+	assert.Equal(t, parent.SpanContext().SpanID(), created.Parent().SpanID())
+	assert.Equal(t, trace.SpanKindClient, created.SpanKind())
+	assert.Contains(t, created.Attributes(), semconv.DBSystemNameSQLite)
+	assert.Contains(t, created.Attributes(), semconv.DBCollectionName("notes"))
+	for _, a := range created.Attributes() {
+		if a.Key == semconv.DBQueryTextKey {
+			assert.Contains(t, a.Value.AsString(), "INSERT INTO")
+			assert.NotContains(t, a.Value.AsString(), "secret", "arguments are left out")
+		}
+	}
+}
+
+func TestTracingMarksFailedStatements(t *testing.T) {
+	spans, _ := recordSpans(t)
+	db := openMeasuredSQLite(t)
+
+	err := db.Table("notes").Create(map[string]any{"body": nil}).Error
+	require.Error(t, err)
+	err = db.Table("notes").Where("id = ?", 42).Take(&map[string]any{}).Error
+	require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	status := map[string]codes.Code{}
+	for _, s := range spans.Ended() {
+		status[s.Name()] = s.Status().Code
+	}
+	assert.Equal(t, codes.Error, status["create notes"])
+	assert.Equal(t, codes.Unset, status["query notes"], "missing rows are not errors")
+}
@@ -0,0 +1,56 @@
+// Package markdown renders user-supplied Markdown into HTML that is safe to embed.
+package markdown
+
+import (
+	"bytes"
+	"html"
+	"strings"
+	"unicode"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+var (
+	md = goldmark.New(goldmark.WithExtensions(extension.GFM))
+
+	// ugc allows the formatting Markdown produces but strips scripts,
+	// event handlers and other active content.
+	ugc = bluemonday.UGCPolicy()
+
+	// text strips every tag, leaving plain text.
+	text = bluemonday.StrictPolicy()
+)
+
+// Render converts Markdown source to sanitized HTML.
+func Render(src string) (string, error) {
+	var buf bytes.Buffer
+	if err := md.Convert([]byte(src), &buf); err != nil {
+		return "", err
+	}
+	return ugc.Sanitize(buf.String()), nil
+}
+
+// Excerpt derives a plain-text preview from rendered HTML, truncated to at
+// most maxRunes characters on a word boundary. An ellipsis is appended when
+// the text was shortened.
+func Excerpt(rendered string, maxRunes int) string {
+	plain := strings.Join(strings.Fields(html.UnescapeString(text.Sanitize(rendered))), " ")
+
+	runes := []rune(plain)
+	if len(runes) <= maxRunes {
+		return plain
+	}
+
+	cut := maxRunes
+	for cut > 0 && !unicode.IsSpace(runes[cut]) {
+		cut--
+	}
+	if cut == 0 {
+		// a single word longer than the limit
+		cut = maxRunes
+	}
+
+	return strings.TrimRightFunc(string(runes[:cut]), unicode.IsSpace) + "…"
+}
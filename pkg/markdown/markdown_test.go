@@ -0,0 +1,43 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderConvertsMarkdown(t *testing.T) {
+	out, err := Render("# Title\n\nSome **bold** text and a [link](https://example.com).")
+
+	require.NoError(t, err)
+	assert.Contains(t, out, "<h1>Title</h1>")
+	assert.Contains(t, out, "<strong>bold</strong>")
+	assert.Contains(t, out, `href="https://example.com"`)
+}
+
+func TestRenderStripsActiveContent(t *testing.T) {
+	out, err := Render("<script>alert(1)</script>\n\n[click](javascript:alert(1))\n\n<img src=x onerror=alert(1)>")
+
+	require.NoError(t, err)
+	assert.NotContains(t, out, "<script")
+	assert.NotContains(t, out, "javascript:")
+	assert.NotContains(t, out, "onerror")
+}
+
+func TestExcerptKeepsShortText(t *testing.T) {
+	assert.Equal(t, "Hello & welcome", Excerpt("<p>Hello &amp; <em>welcome</em></p>", 100))
+}
+
+func TestExcerptTruncatesOnWordBoundary(t *testing.T) {
+	out := Excerpt("<p>The quick brown fox jumps over the lazy dog</p>", 18)
+
+	assert.Equal(t, "The quick brown…", out)
+}
+
+func TestExcerptTruncatesSingleLongWord(t *testing.T) {
+	out := Excerpt(strings.Repeat("a", 50), 10)
+
+	assert.Equal(t, strings.Repeat("a", 10)+"…", out)
+}
@@ -0,0 +1,78 @@
+// Package slug derives URL-friendly identifiers from free text.
+package slug
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxLength is the maximum length of a slug returned by Make.
+const MaxLength = 100
+
+// Fallback is used for text without any transliterable letters or digits.
+const Fallback = "article"
+
+// translit maps letters that do not decompose into ASCII base letters.
+// Cyrillic follows the Ukrainian national romanization, extended with the
+// letters only used in Russian and Belarusian.
+var translit = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "h", 'ґ': "g", 'д': "d", 'е': "e",
+	'є': "ie", 'ж': "zh", 'з': "z", 'и': "y", 'і': "i", 'ї': "i", 'й': "i",
+	'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o", 'п': "p", 'р': "r",
+	'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch",
+	'ш': "sh", 'щ': "shch", 'ь': "", 'ю': "iu", 'я': "ia",
+	'ё': "e", 'ъ': "", 'ы': "y", 'э': "e", 'ў': "u",
+	'ß': "ss", 'æ': "ae", 'ø': "o", 'œ': "oe", 'đ': "d", 'ð': "d",
+	'ł': "l", 'þ': "th", 'ı': "i",
+	'&': " and ", '\'': "", '’': "",
+}
+
+// Make returns a lower-case slug of s consisting of ASCII letters, digits
+// and single hyphens. Accents are dropped, Cyrillic and a few other letters
+// are transliterated and everything else separates words. The result is cut
+// to MaxLength on a word boundary; Fallback is returned when nothing is left.
+func Make(s string) string {
+	var b strings.Builder
+	hyphen := false
+	write := func(str string) {
+		for _, r := range str {
+			if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+				if hyphen && b.Len() > 0 {
+					b.WriteByte('-')
+				}
+				hyphen = false
+				b.WriteRune(r)
+			} else {
+				hyphen = true
+			}
+		}
+	}
+
+	for _, r := range norm.NFC.String(strings.ToLower(s)) {
+		if t, ok := translit[r]; ok {
+			write(t)
+			continue
+		}
+		// decompose to drop accents, e.g. "é" into "e" and U+0301
+		for _, d := range norm.NFD.String(string(r)) {
+			if !unicode.Is(unicode.Mn, d) {
+				write(string(d))
+			}
+		}
+	}
+
+	out := b.String()
+	if len(out) > MaxLength {
+		out = out[:MaxLength]
+		if i := strings.LastIndexByte(out, '-'); i > 0 {
+			out = out[:i]
+		}
+		out = strings.TrimSuffix(out, "-")
+	}
+	if out == "" {
+		return Fallback
+	}
+	return out
+}
@@ -0,0 +1,39 @@
+package slug
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMake(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Hello, World!", "hello-world"},
+		{"  Go 1.25 -- released ", "go-1-25-released"},
+		{"Crème brûlée à la française", "creme-brulee-a-la-francaise"},
+		{"Straße & Ærø", "strasse-and-aero"},
+		{"Привіт, Україно", "pryvit-ukraino"},
+		{"Щука їсть ґедзя", "shchuka-ist-gedzia"},
+		{"Київ", "kyiv"},
+		{"Объявление", "obiavlenye"},
+		{"Don't panic", "dont-panic"},
+		{"日本語", Fallback},
+		{"", Fallback},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			assert.Equal(t, tt.want, Make(tt.in))
+		})
+	}
+}
+
+func TestMakeTruncatesOnWordBoundary(t *testing.T) {
+	s := Make(strings.Repeat("word ", 40))
+
+	assert.LessOrEqual(t, len(s), MaxLength)
+	assert.False(t, strings.HasSuffix(s, "-"))
+	assert.True(t, strings.HasSuffix(s, "word"))
+}
@@ -0,0 +1,169 @@
+// Package validation holds the declarative validation rules of requests.
+// Rules are read from `binding` struct tags, the same ones gin uses, so a
+// request is checked identically when it is bound by a handler and when a
+// service validates a document it assembled itself, like a patched article.
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError describes a field breaking one of its rules.
+type FieldError struct {
+	// Field is the path of the field as clients name it, e.g. "tags[2]".
+	Field string `json:"field"`
+	// Rule is the name of the broken rule, e.g. "max".
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Errors lists every field that failed validation.
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	parts := make([]string, 0, len(e))
+	for _, f := range e {
+		parts = append(parts, f.Field+": "+f.Message)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// rules are the validators added on top of the built-in ones.
+var rules = map[string]validator.Func{
+	"notblank":   notBlank,
+	"singleline": singleLine,
+	"label":      label,
+}
+
+var validate = New()
+
+// New returns a validator reading `binding` tags with the custom rules registered.
+func New() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.SetTagName("binding")
+	Register(v)
+	return v
+}
+
+// Register adds the custom rules to v and makes it report fields by their
+// JSON or form names.
+func Register(v *validator.Validate) {
+	v.RegisterTagNameFunc(fieldName)
+	for tag, fn := range rules {
+		if err := v.RegisterValidation(tag, fn); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// Struct validates s. Fields breaking their rules are reported as Errors.
+func Struct(s any) error {
+	err := validate.Struct(s)
+	if fields, ok := Fields(err); ok {
+		return fields
+	}
+	return err
+}
+
+// Fields extracts the offending fields from an error returned by Struct or
+// by a validator set up with Register. It reports false for any other error.
+func Fields(err error) (Errors, bool) {
+	var fields Errors
+	if errors.As(err, &fields) {
+		return fields, true
+	}
+
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return nil, false
+	}
+	fields = make(Errors, 0, len(verrs))
+	for _, fe := range verrs {
+		fields = append(fields, FieldError{
+			Field:   fieldPath(fe),
+			Rule:    fe.Tag(),
+			Message: message(fe),
+		})
+	}
+	return fields, true
+}
+
+// fieldName names struct fields after their json or form tag.
+func fieldName(f reflect.StructField) string {
+	for _, key := range []string{"json", "form"} {
+		name, _, _ := strings.Cut(f.Tag.Get(key), ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return f.Name
+}
+
+// fieldPath drops the name of the validated struct from the namespace of fe.
+func fieldPath(fe validator.FieldError) string {
+	if _, path, ok := strings.Cut(fe.Namespace(), "."); ok {
+		return path
+	}
+	return fe.Field()
+}
+
+// message explains fe to humans.
+func message(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "notblank":
+		return "must not be blank"
+	case "singleline":
+		return "must be a single line without control characters"
+	case "label":
+		return "may only contain letters, digits, spaces and - _ . + #"
+	case "email":
+		return "must be a valid email address"
+	case "oneof":
+		return "must be one of " + strings.ReplaceAll(fe.Param(), " ", ", ")
+	case "min", "max":
+		bound := "at least"
+		if fe.Tag() == "max" {
+			bound = "at most"
+		}
+		switch fe.Kind() {
+		case reflect.String:
+			return fmt.Sprintf("must be %s %s characters long", bound, fe.Param())
+		case reflect.Slice, reflect.Array, reflect.Map:
+			return fmt.Sprintf("must contain %s %s items", bound, fe.Param())
+		}
+		return fmt.Sprintf("must be %s %s", bound, fe.Param())
+	}
+	return fmt.Sprintf("failed the %q rule", fe.Tag())
+}
+
+// notBlank requires a string with something besides whitespace.
+func notBlank(fl validator.FieldLevel) bool {
+	return strings.TrimSpace(fl.Field().String()) != ""
+}
+
+// singleLine rejects line breaks and other control characters.
+func singleLine(fl validator.FieldLevel) bool {
+	return !strings.ContainsFunc(fl.Field().String(), unicode.IsControl)
+}
+
+// label restricts tag and category names to words and a little punctuation.
+func label(fl validator.FieldLevel) bool {
+	for _, r := range fl.Field().String() {
+		if unicode.IsLetter(r) || unicode.IsMark(r) || unicode.IsDigit(r) || r == ' ' || strings.ContainsRune("-_.+#", r) {
+			continue
+		}
+		return false
+	}
+	return true
+}
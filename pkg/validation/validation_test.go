@@ -0,0 +1,62 @@
+package validation
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type request struct {
+	Name  string   `json:"name" binding:"required,notblank,max=10,singleline"`
+	Tags  []string `json:"tags" binding:"max=2,dive,notblank,label"`
+	Limit int      `form:"limit" binding:"omitempty,max=100"`
+	Sort  string   `json:"sort" binding:"omitempty,oneof=asc desc"`
+}
+
+func TestStructAcceptsValidInput(t *testing.T) {
+	assert.NoError(t, Struct(request{Name: "Jane Doe", Tags: []string{"go", "état #1"}, Sort: "asc"}))
+	assert.NoError(t, Struct(request{Name: "x", Tags: []string{"web dev", "c#"}}))
+}
+
+func TestStructReportsEveryField(t *testing.T) {
+	err := Struct(request{
+		Name:  "multi\nline",
+		Tags:  []string{" ", "a/b"},
+		Limit: 500,
+		Sort:  "up",
+	})
+
+	fields, ok := Fields(err)
+	require.True(t, ok)
+	assert.Equal(t, Errors{
+		{Field: "name", Rule: "singleline", Message: "must be a single line without control characters"},
+		{Field: "tags[0]", Rule: "notblank", Message: "must not be blank"},
+		{Field: "tags[1]", Rule: "label", Message: "may only contain letters, digits, spaces and - _ . + #"},
+		{Field: "limit", Rule: "max", Message: "must be at most 100"},
+		{Field: "sort", Rule: "oneof", Message: "must be one of asc, desc"},
+	}, fields)
+}
+
+func TestStructLengthMessages(t *testing.T) {
+	err := Struct(request{Name: strings.Repeat("x", 11), Tags: []string{"a", "b", "c"}})
+
+	fields, ok := Fields(err)
+	require.True(t, ok)
+	assert.Equal(t, "name: must be at most 10 characters long; tags: must contain at most 2 items", fields.Error())
+}
+
+func TestStructRequiresFields(t *testing.T) {
+	fields, ok := Fields(Struct(request{}))
+	require.True(t, ok)
+	assert.Equal(t, Errors{{Field: "name", Rule: "required", Message: "is required"}}, fields)
+}
+
+func TestFieldsIgnoresOtherErrors(t *testing.T) {
+	_, ok := Fields(errors.New("unexpected EOF"))
+	assert.False(t, ok)
+	_, ok = Fields(nil)
+	assert.False(t, ok)
+}